@@ -0,0 +1,178 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strconv"
+	"strings"
+
+	"hrms.local/core/models"
+)
+
+// Fixed width layout, every record ends with CRLF and text fields are left aligned and
+// padded with spaces, numbers are right aligned and padded with zeros, amounts in cents.
+// Values are never truncated, a batch with a value longer than its field is refused.
+//
+//	H  type(1) company(40) run id(36) pay date YYYYMMDD(8) transfers(6) total(15)
+//	D  type(1) employee number(20) name(40) national id(20) bank(30) account(34) amount(15) currency(3)
+//	T  type(1) transfers(6) total(15)
+const (
+	fixedWidthNewLine = "\r\n"
+	maxAmountCents    = 999999999999999
+)
+
+var csvHeader = []string{"employee_number", "employee_name", "national_id", "bank_name", "bank_account", "amount", "currency"}
+
+// GenerateBankFile writes one transfer per employee in the requested format.
+// Batches mixing currencies are refused, the bank pays them in the currency of the file.
+func (e *PayrollExporter) GenerateBankFile(batch models.BankTransferBatch, format models.BankFileFormat) ([]byte, *models.SystemError) {
+	for _, transfer := range batch.Transfers {
+		if transfer.Amount < 0 || toCents(transfer.Amount) > maxAmountCents {
+			return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid transfer amount for employee "+transfer.EmployeeNumber, struct{}{})
+		}
+		if transfer.Currency != batch.Transfers[0].Currency {
+			return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the transfers mix "+batch.Transfers[0].Currency+" and "+transfer.Currency+", a bank file pays a single currency", struct{}{})
+		}
+	}
+	switch format {
+	case models.BankFileFormatCSV:
+		return generateCSV(batch)
+	case models.BankFileFormatFixedWidth:
+		return generateFixedWidth(batch)
+	}
+	return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid bank file format", struct{}{})
+}
+
+func generateCSV(batch models.BankTransferBatch) ([]byte, *models.SystemError) {
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	_ = writer.Write(csvHeader)
+	for _, transfer := range batch.Transfers {
+		_ = writer.Write([]string{
+			transfer.EmployeeNumber,
+			transfer.EmployeeName,
+			transfer.NationalID,
+			transfer.BankName,
+			transfer.BankAccount,
+			strconv.FormatFloat(transfer.Amount, 'f', 2, 64),
+			transfer.Currency,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not write bank file: "+err.Error(), struct{}{})
+	}
+	return out.Bytes(), nil
+}
+
+// generateFixedWidth refuses values longer than their field, a cut account number would pay someone else
+func generateFixedWidth(batch models.BankTransferBatch) ([]byte, *models.SystemError) {
+	var out bytes.Buffer
+	count := int64(len(batch.Transfers))
+	// the total is the sum of the detail amounts, as the bank adds them
+	var total int64
+	for _, transfer := range batch.Transfers {
+		total += toCents(transfer.Amount)
+	}
+
+	header := record{owner: "the header"}
+	header.text("H")
+	header.alpha("company name", batch.CompanyName, 40)
+	header.alpha("run id", batch.RunID, 36)
+	header.text(batch.PayDate.Format("20060102"))
+	header.numeric("transfer count", count, 6)
+	header.numeric("total", total, 15)
+	if err := header.writeTo(&out); err != nil {
+		return nil, err
+	}
+	for _, transfer := range batch.Transfers {
+		detail := record{owner: "employee " + transfer.EmployeeNumber}
+		detail.text("D")
+		detail.alpha("employee number", transfer.EmployeeNumber, 20)
+		detail.alpha("name", transfer.EmployeeName, 40)
+		detail.alpha("national id", transfer.NationalID, 20)
+		detail.alpha("bank name", transfer.BankName, 30)
+		detail.alpha("bank account", transfer.BankAccount, 34)
+		detail.numeric("amount", toCents(transfer.Amount), 15)
+		detail.alpha("currency", transfer.Currency, 3)
+		if err := detail.writeTo(&out); err != nil {
+			return nil, err
+		}
+	}
+	trailer := record{owner: "the trailer"}
+	trailer.text("T")
+	trailer.numeric("transfer count", count, 6)
+	trailer.numeric("total", total, 15)
+	if err := trailer.writeTo(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// record builds a fixed width record, keeping the first field that does not fit
+type record struct {
+	owner  string
+	fields strings.Builder
+	err    *models.SystemError
+}
+
+func (r *record) text(s string) {
+	r.fields.WriteString(s)
+}
+
+// alpha left aligns s in a field of the given width, padded with spaces
+func (r *record) alpha(name, s string, width int) {
+	value := ascii(s)
+	if len(value) > width {
+		r.fail(name + " is longer than " + strconv.Itoa(width) + " characters")
+		return
+	}
+	r.fields.WriteString(value + strings.Repeat(" ", width-len(value)))
+}
+
+// numeric right aligns n in a field of the given width, padded with zeros
+func (r *record) numeric(name string, n int64, width int) {
+	value := strconv.FormatInt(n, 10)
+	if n < 0 || len(value) > width {
+		r.fail(name + " does not fit in " + strconv.Itoa(width) + " digits")
+		return
+	}
+	r.fields.WriteString(strings.Repeat("0", width-len(value)) + value)
+}
+
+func (r *record) fail(reason string) {
+	if r.err == nil {
+		r.err = models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "cannot write the bank file, the "+reason+" in "+r.owner, struct{}{})
+	}
+}
+
+func (r *record) writeTo(out *bytes.Buffer) *models.SystemError {
+	if r.err != nil {
+		return r.err
+	}
+	out.WriteString(r.fields.String() + fixedWidthNewLine)
+	return nil
+}
+
+// accents maps the accented letters of employee names to the ASCII letters banks accept
+var accents = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N",
+)
+
+// ascii replaces accents and the characters banks do not accept
+func ascii(s string) string {
+	var b strings.Builder
+	for _, r := range accents.Replace(s) {
+		if r < 0x20 || r >= 0x7f {
+			r = ' '
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
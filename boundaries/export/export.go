@@ -0,0 +1,118 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+const dateLayout = "2006-01-02"
+
+type PayrollExporter struct {
+}
+
+func NewPayrollExporter() contracts.PayrollExportContract {
+	return &PayrollExporter{}
+}
+
+// RenderPayslipPDF lays out the payslip on A4 pages: header, employee data, the earning
+// and deduction lines and the totals. Lines that do not fit continue on a new page.
+func (e *PayrollExporter) RenderPayslipPDF(document models.PayslipDocument) ([]byte, *models.SystemError) {
+	payslip := document.Payslip
+	employee := document.Employee
+	pdf := newPDFWriter()
+
+	left, right := pageMargin, pageWidth-pageMargin
+	y := pageMargin + 10.0
+	pdf.text(left, y, fontBold, 16, document.CompanyName)
+	pdf.textRight(right, y, fontBold, 16, "PAYSLIP")
+	y += 20
+	pdf.text(left, y, fontRegular, 10, fmt.Sprintf("Pay period: %s (%s - %s)", document.Period.Name, document.Period.StartDate.Format(dateLayout), document.Period.EndDate.Format(dateLayout)))
+	pdf.textRight(right, y, fontRegular, 10, "Pay date: "+document.Period.PayDate.Format(dateLayout))
+	y += 10
+	pdf.line(left, right, y)
+
+	y += 20
+	details := [][2]string{
+		{"Employee", employee.FullName()},
+		{"Employee number", employee.EmployeeNumber},
+		{"National ID", employee.NationalID},
+		{"Department", document.DepartmentName},
+		{"Position", document.PositionName},
+		{"Bank account", maskAccount(employee.BankName, employee.BankAccount)},
+		{"Worked days", strconv.Itoa(payslip.WorkedDays)},
+		{"Overtime hours", strconv.FormatFloat(float64(payslip.OvertimeMinutes)/60, 'f', 2, 64)},
+	}
+	for _, detail := range details {
+		pdf.text(left, y, fontBold, 10, detail[0])
+		pdf.text(left+120, y, fontRegular, 10, detail[1])
+		y += 15
+	}
+
+	y += 15
+	earningsColumn, deductionsColumn := right-110, right
+	pdf.text(left, y, fontBold, 10, "Code")
+	pdf.text(left+90, y, fontBold, 10, "Description")
+	pdf.textRight(earningsColumn, y, fontBold, 10, "Earnings")
+	pdf.textRight(deductionsColumn, y, fontBold, 10, "Deductions")
+	y += 6
+	pdf.line(left, right, y)
+	y += 15
+	for _, line := range payslip.Lines {
+		if y > pageHeight-pageMargin-80 {
+			pdf.addPage()
+			y = pageMargin + 10
+		}
+		pdf.text(left, y, fontRegular, 10, line.Code)
+		pdf.text(left+90, y, fontRegular, 10, line.Description)
+		column := earningsColumn
+		if line.Kind == models.PayslipLineKindDeduction {
+			column = deductionsColumn
+		}
+		pdf.textRight(column, y, fontRegular, 10, formatAmount(line.Amount))
+		y += 15
+	}
+
+	pdf.line(left, right, y-9)
+	y += 6
+	pdf.text(left+90, y, fontBold, 10, "Totals")
+	pdf.textRight(earningsColumn, y, fontBold, 10, formatAmount(payslip.GrossPay))
+	pdf.textRight(deductionsColumn, y, fontBold, 10, formatAmount(payslip.TotalDeductions))
+	y += 25
+	pdf.text(left+90, y, fontBold, 12, "Net pay")
+	pdf.textRight(deductionsColumn, y, fontBold, 12, payslip.Currency+" "+formatAmount(payslip.NetPay))
+
+	return pdf.bytes(), nil
+}
+
+// formatAmount prints an amount with two decimals and thousands separators
+func formatAmount(amount float64) string {
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+	formatted := fmt.Sprintf("%s.%02d", strings.Join(groups, ","), cents%100)
+	if amount < 0 && cents > 0 {
+		return "-" + formatted
+	}
+	return formatted
+}
+
+// maskAccount hides all but the last four digits of the bank account printed on payslips
+func maskAccount(bank, account string) string {
+	if account == "" {
+		return ""
+	}
+	if len(account) > 4 {
+		account = strings.Repeat("*", len(account)-4) + account[len(account)-4:]
+	}
+	return strings.TrimSpace(bank + " " + account)
+}
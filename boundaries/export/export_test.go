@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"hrms.local/core/models"
+)
+
+func testBatch() models.BankTransferBatch {
+	return models.BankTransferBatch{
+		CompanyName: "HEX-HRMS",
+		RunID:       "run-1",
+		PayDate:     time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+		Transfers: []models.BankTransfer{
+			{EmployeeNumber: "E-001", EmployeeName: "José Peña", NationalID: "001-0000001-1", BankName: "Banco", BankAccount: "123456789", Amount: 1234.5, Currency: "DOP"},
+			{EmployeeNumber: "E-002", EmployeeName: "Ana, \"Annie\" Diaz", BankAccount: "987654321", Amount: 100, Currency: "DOP"},
+		},
+	}
+}
+
+func TestRenderPayslipPDF(t *testing.T) {
+	exporter := NewPayrollExporter()
+	document := models.PayslipDocument{
+		CompanyName: "HEX (HRMS)",
+		Employee:    models.Employee{FirstName: "José", LastName: "Peña", EmployeeNumber: "E-001", BankAccount: "123456789"},
+		Period:      models.PayPeriod{Name: "April 2025"},
+		Payslip: models.Payslip{
+			Currency: "DOP",
+			GrossPay: 1000,
+			NetPay:   900,
+			Lines: []models.PayslipLine{
+				{Code: "BASE", Description: "Base salary", Kind: models.PayslipLineKindEarning, Amount: 1000},
+				{Code: "SS", Description: "Social security", Kind: models.PayslipLineKindDeduction, Amount: 100},
+			},
+		},
+	}
+	// enough lines to need a second page
+	for i := 0; i < 60; i++ {
+		document.Payslip.Lines = append(document.Payslip.Lines, models.PayslipLine{Code: "X" + strconv.Itoa(i), Kind: models.PayslipLineKindEarning})
+	}
+
+	pdf, err := exporter.RenderPayslipPDF(document)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("Expected a complete PDF document")
+	}
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Fatal("Expected the lines to continue on a second page")
+	}
+	if !bytes.Contains(pdf, []byte(`HEX \(HRMS\)`)) || !bytes.Contains(pdf, []byte(`Jos\351 Pe\361a`)) {
+		t.Fatal("Expected text to be escaped and encoded as WinAnsi")
+	}
+	if bytes.Contains(pdf, []byte("123456789")) || !bytes.Contains(pdf, []byte("*****6789")) {
+		t.Fatal("Expected the bank account to be masked")
+	}
+
+	// every xref entry must point at the object it declares
+	start := bytes.LastIndex(pdf, []byte("startxref\n"))
+	xref, _ := strconv.Atoi(strings.TrimSpace(strings.Split(string(pdf[start+len("startxref\n"):]), "\n")[0]))
+	entries := strings.Split(string(pdf[xref:]), "\n")[3:]
+	for i, entry := range entries {
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		if !bytes.HasPrefix(pdf[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")) {
+			t.Fatalf("xref entry %d points at the wrong offset", i+1)
+		}
+	}
+}
+
+func TestGenerateCSVBankFile(t *testing.T) {
+	file, err := NewPayrollExporter().GenerateBankFile(testBatch(), models.BankFileFormatCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(file)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and two transfers, got %d lines", len(lines))
+	}
+	if lines[1] != "E-001,José Peña,001-0000001-1,Banco,123456789,1234.50,DOP" {
+		t.Errorf("Unexpected transfer row %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], `E-002,"Ana, ""Annie"" Diaz",`) {
+		t.Errorf("Expected names to be quoted, got %q", lines[2])
+	}
+}
+
+func TestGenerateFixedWidthBankFile(t *testing.T) {
+	file, err := NewPayrollExporter().GenerateBankFile(testBatch(), models.BankFileFormatFixedWidth)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	records := strings.Split(strings.TrimSuffix(string(file), "\r\n"), "\r\n")
+	if len(records) != 4 {
+		t.Fatalf("Expected header, two details and trailer, got %d records", len(records))
+	}
+	if len(records[0]) != 106 || len(records[1]) != 163 || len(records[3]) != 22 {
+		t.Fatalf("Unexpected record lengths %d %d %d", len(records[0]), len(records[1]), len(records[3]))
+	}
+	if records[1][21:61] != "Jose Pena"+strings.Repeat(" ", 31) {
+		t.Errorf("Expected accents to be removed, got %q", records[1][21:61])
+	}
+	if records[1][145:160] != "000000000123450" {
+		t.Errorf("Unexpected amount %q", records[1][145:160])
+	}
+	if records[3] != "T000002000000000133450" {
+		t.Errorf("Unexpected trailer %q", records[3])
+	}
+}
+
+func TestGenerateBankFileRejectsNegativeAmounts(t *testing.T) {
+	batch := testBatch()
+	batch.Transfers[0].Amount = -1
+	if _, err := NewPayrollExporter().GenerateBankFile(batch, models.BankFileFormatCSV); err == nil {
+		t.Fatal("Expected negative transfers to be rejected")
+	}
+}
+
+func TestGenerateFixedWidthBankFileRejectsWhatDoesNotFit(t *testing.T) {
+	long := testBatch()
+	long.Transfers[0].BankAccount = strings.Repeat("1", 35)
+	huge := testBatch()
+	huge.Transfers[0].Amount = 9999999999999
+	huge.Transfers[1].Amount = 9999999999999
+	for name, batch := range map[string]models.BankTransferBatch{"account": long, "total": huge} {
+		if _, err := NewPayrollExporter().GenerateBankFile(batch, models.BankFileFormatFixedWidth); err == nil {
+			t.Errorf("Expected a %s longer than its field to be rejected", name)
+		}
+	}
+}
+
+func TestGenerateBankFileRejectsMixedCurrencies(t *testing.T) {
+	batch := testBatch()
+	batch.Transfers[1].Currency = "USD"
+	for _, format := range []models.BankFileFormat{models.BankFileFormatCSV, models.BankFileFormatFixedWidth} {
+		if _, err := NewPayrollExporter().GenerateBankFile(batch, format); err == nil {
+			t.Errorf("Expected the %s file of a batch mixing currencies to be rejected", format)
+		}
+	}
+}
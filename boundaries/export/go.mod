@@ -0,0 +1,5 @@
+module hrms.local/export
+
+go 1.23.9
+
+require hrms.local/core v0.0.0
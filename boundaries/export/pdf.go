@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size in PDF points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 50.0
+)
+
+type pdfFont string
+
+const (
+	fontRegular pdfFont = "F1"
+	fontBold    pdfFont = "F2"
+)
+
+// helveticaWidths holds the widths of the Helvetica glyphs used by numbers, in 1/1000 of the font size
+var helveticaWidths = map[rune]float64{
+	'0': 556, '1': 556, '2': 556, '3': 556, '4': 556, '5': 556, '6': 556, '7': 556, '8': 556, '9': 556,
+	'.': 278, ',': 278, '-': 333, ' ': 278, '(': 333, ')': 333,
+}
+
+// pdfWriter builds a minimal PDF 1.4 document with the standard Helvetica fonts,
+// which every reader ships, so no font has to be embedded.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.addPage()
+	return w
+}
+
+func (w *pdfWriter) addPage() {
+	w.pages = append(w.pages, &bytes.Buffer{})
+}
+
+func (w *pdfWriter) page() *bytes.Buffer {
+	return w.pages[len(w.pages)-1]
+}
+
+// text writes s with its baseline starting at x, y measured from the top left corner of the page
+func (w *pdfWriter) text(x, y float64, font pdfFont, size float64, s string) {
+	fmt.Fprintf(w.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pageHeight-y, escapePDFText(s))
+}
+
+// textRight writes s so that it ends at x, used to align amounts
+func (w *pdfWriter) textRight(x, y float64, font pdfFont, size float64, s string) {
+	w.text(x-textWidth(s, size), y, font, size, s)
+}
+
+// line draws a horizontal rule between x1 and x2
+func (w *pdfWriter) line(x1, x2, y float64) {
+	fmt.Fprintf(w.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, pageHeight-y, x2, pageHeight-y)
+}
+
+// bytes serializes the document, computing the cross reference table from the object offsets
+func (w *pdfWriter) bytes() []byte {
+	var objects []string
+	pageCount := len(w.pages)
+	// 1 catalog, 2 page tree, 3 and 4 fonts, then a page and its content for every page
+	kids := make([]string, pageCount)
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, content := range w.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// escapePDFText converts s to WinAnsi and escapes the characters reserved by PDF strings.
+// WinAnsi matches Latin-1 for accented letters, characters outside of it are replaced by '?'.
+func escapePDFText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth approximates the width of s in Helvetica, exact for amounts
+func textWidth(s string, size float64) float64 {
+	var width float64
+	for _, r := range s {
+		w, ok := helveticaWidths[r]
+		if !ok {
+			w = 556
+		}
+		width += w
+	}
+	return width * size / 1000
+}
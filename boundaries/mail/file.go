@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// FileMailer writes every mail as an .eml file of the directory, they open in any mail client
+type FileMailer struct {
+	dir   string
+	from  string
+	count atomic.Int64
+}
+
+func NewFileMailer(dir string, from string) (contracts.MailerContract, *models.SystemError) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, internalError("could not create mail directory: " + err.Error())
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(mail models.Mail) *models.SystemError {
+	now := time.Now()
+	message, err := buildMessage(m.from, mail, now)
+	if err != nil {
+		return err
+	}
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + strconv.FormatInt(m.count.Add(1), 10) + ".eml"
+	if err := os.WriteFile(filepath.Join(m.dir, name), message, 0o600); err != nil {
+		return internalError("could not write mail: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+module hrms.local/mail
+
+go 1.23.9
+
+require hrms.local/core v0.0.0
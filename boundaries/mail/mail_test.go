@@ -0,0 +1,121 @@
+package mail
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"hrms.local/core/models"
+)
+
+func TestTemplatesRenderEveryLanguage(t *testing.T) {
+	templates, err := NewTemplates("HEX-HRMS")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	subjects := map[models.Language]string{}
+	for _, language := range []models.Language{models.LanguageSpanish, models.LanguageEnglish} {
+		rendered, err := templates.Render(models.MailMessage{
+			Template: models.MailTemplatePasswordReset,
+			Language: language,
+			To:       "ana@example.com",
+			Data:     map[string]string{"Name": "Ana <b>", "Link": "https://hrms.example.com/reset-password?token=abc&x=1", "Hours": "1"},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rendered.To != "ana@example.com" || !strings.Contains(rendered.Subject, "HEX-HRMS") {
+			t.Fatalf("Unexpected mail %+v", rendered)
+		}
+		if !strings.Contains(rendered.Text, "Ana <b>") || !strings.Contains(rendered.Text, "token=abc&x=1") {
+			t.Fatalf("Expected the text part to be unescaped, got %q", rendered.Text)
+		}
+		if strings.Contains(rendered.HTML, "Ana <b>") || !strings.Contains(rendered.HTML, "Ana &lt;b&gt;") {
+			t.Fatalf("Expected the HTML part to be escaped, got %q", rendered.HTML)
+		}
+		subjects[language] = rendered.Subject
+	}
+	if subjects[models.LanguageSpanish] == subjects[models.LanguageEnglish] {
+		t.Fatalf("Expected a translated subject, got %v", subjects)
+	}
+
+	fallback, err := templates.Render(models.MailMessage{Template: models.MailTemplateEmailVerification, Language: "fr", To: "ana@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(fallback.Subject, "Confirme") {
+		t.Fatalf("Expected the Spanish template as fallback, got %q", fallback.Subject)
+	}
+}
+
+func TestFileMailerWritesMultipartMessage(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := NewFileMailer(dir, "HRMS <no-reply@example.com>")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sent := models.Mail{To: "ana@example.com", Subject: "Contraseña", Text: "Hola\nAna\n", HTML: "<p>Hola</p>\n"}
+	if err := mailer.Send(sent); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one .eml file, got %v", files)
+	}
+	file, openErr := os.Open(files[0])
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+	defer file.Close()
+
+	message, parseErr := mail.ReadMessage(file)
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if subject != "Contraseña" || message.Header.Get("To") != "ana@example.com" {
+		t.Fatalf("Unexpected headers %v", message.Header)
+	}
+	mediaType, params, _ := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, got %s", mediaType)
+	}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		parts = append(parts, part.Header.Get("Content-Type")+"|"+string(body))
+	}
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "text/plain") || !strings.Contains(parts[0], "Hola\r\nAna") || !strings.HasPrefix(parts[1], "text/html") {
+		t.Fatalf("Unexpected parts %q", parts)
+	}
+}
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	if _, err := buildMessage("no-reply@example.com", models.Mail{To: "ana@example.com\r\nBcc: all@example.com", Subject: "x"}, time.Now()); err == nil {
+		t.Fatal("Expected a recipient with line breaks to be refused")
+	}
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+	mailer.Send(models.Mail{To: "a@example.com"})
+	mailer.Send(models.Mail{To: "b@example.com"})
+	sent := mailer.Sent()
+	if len(sent) != 2 || sent[1].To != "b@example.com" {
+		t.Fatalf("Expected the two mails in order, got %+v", sent)
+	}
+}
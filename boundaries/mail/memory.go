@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"sync"
+
+	"hrms.local/core/models"
+)
+
+// MemoryMailer keeps the mails instead of sending them, meant for tests and local development
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []models.Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(mail models.Mail) *models.SystemError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+// Sent returns a copy of the mails sent so far, oldest first
+func (m *MemoryMailer) Sent() []models.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.Mail(nil), m.sent...)
+}
@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// SMTPConfig is the relay used to send mail, Username empty disables authentication
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends through the relay, upgrading to TLS when it offers STARTTLS.
+// PLAIN authentication is refused by net/smtp over unencrypted connections to other hosts.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) contracts.MailerContract {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(mail models.Mail) *models.SystemError {
+	message, err := buildMessage(m.config.From, mail, time.Now())
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{mail.To}, message); err != nil {
+		return internalError("could not send mail: " + err.Error())
+	}
+	return nil
+}
+
+// buildMessage writes the RFC 5322 message, multipart/alternative when the mail has an HTML part
+func buildMessage(from string, mail models.Mail, now time.Time) ([]byte, *models.SystemError) {
+	for _, value := range []string{from, mail.To, mail.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, internalError("mail headers cannot contain line breaks")
+		}
+	}
+	if mail.To == "" {
+		return nil, internalError("mail recipient is required")
+	}
+
+	var buffer bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buffer, "%s: %s\r\n", name, value)
+	}
+	header("From", from)
+	header("To", mail.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", mail.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if mail.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+		writeQuotedPrintable(&buffer, mail.Text)
+		return buffer.Bytes(), nil
+	}
+
+	boundary := make([]byte, 12)
+	if _, err := rand.Read(boundary); err != nil {
+		return nil, internalError("could not build mail: " + err.Error())
+	}
+	separator := "hrms-" + hex.EncodeToString(boundary)
+	header("Content-Type", `multipart/alternative; boundary="`+separator+`"`)
+	buffer.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", mail.Text},
+		{"text/html; charset=utf-8", mail.HTML},
+	} {
+		buffer.WriteString("--" + separator + "\r\n")
+		header("Content-Type", part.contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+		writeQuotedPrintable(&buffer, part.body)
+		buffer.WriteString("\r\n")
+	}
+	buffer.WriteString("--" + separator + "--\r\n")
+	return buffer.Bytes(), nil
+}
+
+// writeQuotedPrintable also turns the line breaks of the body into CRLF
+func writeQuotedPrintable(buffer *bytes.Buffer, body string) {
+	writer := quotedprintable.NewWriter(buffer)
+	writer.Write([]byte(body))
+	writer.Close()
+}
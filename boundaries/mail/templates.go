@@ -0,0 +1,91 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var languages = []models.Language{models.LanguageSpanish, models.LanguageEnglish}
+
+var mailTemplates = []models.MailTemplate{models.MailTemplatePasswordReset, models.MailTemplateEmailVerification, models.MailTemplateInvitation}
+
+type parsedTemplate struct {
+	text *textTemplate.Template
+	html *htmlTemplate.Template
+}
+
+// Templates renders the messages of templates/<template>.<language>.tmpl, each file defines
+// a "subject", a "text" and an "html" block. The HTML block is escaped, the others are not.
+type Templates struct {
+	company   string
+	templates map[string]parsedTemplate
+}
+
+// NewTemplates parses every template in every language, a missing translation is an error
+func NewTemplates(company string) (contracts.MailTemplateContract, *models.SystemError) {
+	t := &Templates{company: company, templates: map[string]parsedTemplate{}}
+	for _, template := range mailTemplates {
+		for _, language := range languages {
+			name := templateName(template, language)
+			text, err := textTemplate.ParseFS(templateFiles, "templates/"+name)
+			if err != nil {
+				return nil, internalError("could not parse mail template " + name + ": " + err.Error())
+			}
+			html, err := htmlTemplate.ParseFS(templateFiles, "templates/"+name)
+			if err != nil {
+				return nil, internalError("could not parse mail template " + name + ": " + err.Error())
+			}
+			t.templates[name] = parsedTemplate{text: text, html: html}
+		}
+	}
+	return t, nil
+}
+
+// Render falls back to models.DefaultLanguage when the language is not supported
+func (t *Templates) Render(message models.MailMessage) (models.Mail, *models.SystemError) {
+	parsed, ok := t.templates[templateName(message.Template, message.Language)]
+	if !ok {
+		parsed, ok = t.templates[templateName(message.Template, models.DefaultLanguage)]
+	}
+	if !ok {
+		return models.Mail{}, internalError("unknown mail template " + string(message.Template))
+	}
+	data := map[string]string{"Company": t.company}
+	for key, value := range message.Data {
+		data[key] = value
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := parsed.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return models.Mail{}, internalError("could not render mail subject: " + err.Error())
+	}
+	if err := parsed.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return models.Mail{}, internalError("could not render mail: " + err.Error())
+	}
+	if err := parsed.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return models.Mail{}, internalError("could not render mail: " + err.Error())
+	}
+	return models.Mail{
+		To:      message.To,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+	}, nil
+}
+
+func templateName(template models.MailTemplate, language models.Language) string {
+	return string(template) + "." + string(language) + ".tmpl"
+}
+
+func internalError(message string) *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, message, struct{}{})
+}
@@ -3,6 +3,7 @@ module hrms.local/security
 go 1.23.9
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.3.0
 	golang.org/x/crypto v0.31.0
 	hrms.local/core v0.0.0
 )
@@ -0,0 +1,349 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// oidcTimeout bounds every request to the provider
+	oidcTimeout = 10 * time.Second
+	// oidcKeysRefresh limits how often an unknown kid refetches the keys of the provider
+	oidcKeysRefresh = time.Minute
+	// oidcClockSkew is tolerated on the times of the ID token
+	oidcClockSkew = time.Minute
+)
+
+// OIDCConfig registers the API as a client of an OpenID Connect provider
+type OIDCConfig struct {
+	// Issuer URL, the discovery document is read from <IssuerURL>/.well-known/openid-configuration
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// Page of the web client the provider redirects to with the code
+	RedirectURL string
+	Scopes      []string
+	// Claim of the ID token listing the groups of the user
+	GroupsClaim string
+	HTTPClient  *http.Client
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider is a relying party using the authorization code flow with PKCE. The discovery document
+// is read on first use so the API starts while the provider is down, the keys are refetched on rotation.
+type OIDCProvider struct {
+	config      OIDCConfig
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]any
+	keysFetched time.Time
+}
+
+func NewOIDCProvider(config OIDCConfig) contracts.OIDCProviderContract {
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: oidcTimeout}
+	}
+	return &OIDCProvider{config: config}
+}
+
+func (p *OIDCProvider) AuthorizationURL(state string, nonce string, codeChallenge string) (string, *models.SystemError) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	endpoint, parseErr := url.Parse(discovery.AuthorizationEndpoint)
+	if parseErr != nil {
+		return "", internalError("invalid authorization endpoint: " + parseErr.Error())
+	}
+	query := endpoint.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	endpoint.RawQuery = query.Encode()
+	return endpoint.String(), nil
+}
+
+func (p *OIDCProvider) Exchange(code string, codeVerifier string, nonce string) (*models.OIDCIdentity, *models.SystemError) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	request, reqErr := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if reqErr != nil {
+		return nil, internalError("could not build token request: " + reqErr.Error())
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+	response, reqErr := p.config.HTTPClient.Do(request)
+	if reqErr != nil {
+		return nil, internalError("token request failed: " + reqErr.Error())
+	}
+	defer response.Body.Close()
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if decodeErr := json.NewDecoder(response.Body).Decode(&tokens); decodeErr != nil {
+		return nil, internalError("invalid token response: " + decodeErr.Error())
+	}
+	if response.StatusCode != http.StatusOK || tokens.IDToken == "" {
+		return nil, invalidIDToken("token request refused: " + tokens.Error + " " + tokens.ErrorDescription)
+	}
+	return p.verifyIDToken(discovery, tokens.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, the issuer, the audience, the times and the nonce of the ID token
+func (p *OIDCProvider) verifyIDToken(discovery *oidcDiscovery, idToken string, nonce string) (*models.OIDCIdentity, *models.SystemError) {
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := p.key(discovery, kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		// the algorithm must match the type of the key, never trust the header alone
+		if !algorithmMatches(token.Method.Alg(), key) {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", AlgorithmEdDSA}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil || !token.Valid {
+		return nil, invalidIDToken("invalid ID token")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, invalidIDToken("invalid ID token")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); nonce == "" || tokenNonce != nonce {
+		return nil, invalidIDToken("ID token nonce does not match")
+	}
+	// with several audiences the token must be issued to us
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, invalidIDToken("ID token issued to another client")
+		}
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, invalidIDToken("ID token without subject")
+	}
+	email, _ := claims["email"].(string)
+	name, _ := claims["given_name"].(string)
+	lastName, _ := claims["family_name"].(string)
+	return &models.OIDCIdentity{
+		Issuer:        discovery.Issuer,
+		Subject:       subject,
+		Email:         strings.ToLower(email),
+		EmailVerified: claimTrue(claims["email_verified"]),
+		Name:          name,
+		LastName:      lastName,
+		Groups:        claimStrings(claims[p.config.GroupsClaim]),
+	}, nil
+}
+
+func (p *OIDCProvider) discover() (*oidcDiscovery, *models.SystemError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	var discovery oidcDiscovery
+	if err := p.getJSON(p.config.IssuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, internalError("discovery document of " + p.config.IssuerURL + " names issuer " + discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, internalError("incomplete discovery document for " + p.config.IssuerURL)
+	}
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key returns the verification key of the kid, refetching the key set when the kid is unknown
+func (p *OIDCProvider) key(discovery *oidcDiscovery, kid string) (any, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+	if time.Since(p.keysFetched) < oidcKeysRefresh {
+		return nil, false
+	}
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := p.getJSON(discovery.JWKSURI, &set); err != nil {
+		return nil, false
+	}
+	p.keysFetched = time.Now()
+	p.keys = make(map[string]any, len(set.Keys))
+	for _, raw := range set.Keys {
+		if id, key, ok := parseJWK(raw); ok {
+			p.keys[id] = key
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) getJSON(address string, target any) *models.SystemError {
+	response, err := p.config.HTTPClient.Get(address)
+	if err != nil {
+		return internalError("could not reach the identity provider: " + err.Error())
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return internalError("identity provider answered " + response.Status + " to " + address)
+	}
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		return internalError("invalid answer from the identity provider: " + err.Error())
+	}
+	return nil
+}
+
+// parseJWK reads the signing keys of a JWKS, RSA, EC and Ed25519 keys are supported
+func parseJWK(raw json.RawMessage) (string, any, bool) {
+	var jwk struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Kid string `json:"kid"`
+		Crv string `json:"crv"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	if err := json.Unmarshal(raw, &jwk); err != nil || (jwk.Use != "" && jwk.Use != "sig") {
+		return "", nil, false
+	}
+	decode := func(value string) *big.Int {
+		bytes, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || len(bytes) == 0 {
+			return nil
+		}
+		return new(big.Int).SetBytes(bytes)
+	}
+	switch jwk.Kty {
+	case "RSA":
+		n, e := decode(jwk.N), decode(jwk.E)
+		if n == nil || e == nil || !e.IsInt64() {
+			return "", nil, false
+		}
+		return jwk.Kid, &rsa.PublicKey{N: n, E: int(e.Int64())}, true
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[jwk.Crv]
+		x, y := decode(jwk.X), decode(jwk.Y)
+		if !ok || x == nil || y == nil {
+			return "", nil, false
+		}
+		return jwk.Kid, &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, true
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if jwk.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return "", nil, false
+		}
+		return jwk.Kid, ed25519.PublicKey(x), true
+	}
+	return "", nil, false
+}
+
+func algorithmMatches(algorithm string, key any) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(algorithm, "RS") || strings.HasPrefix(algorithm, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(algorithm, "ES")
+	case ed25519.PublicKey:
+		return algorithm == AlgorithmEdDSA
+	}
+	return false
+}
+
+// claimTrue reads a boolean claim, some providers send it as a string
+func claimTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// claimStrings reads a claim holding a list of strings or a single string
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return []string{}
+}
+
+func invalidIDToken(message string) *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, message, struct{}{})
+}
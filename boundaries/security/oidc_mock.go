@@ -0,0 +1,259 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"hrms.local/core/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	mockKeyID     = "mock"
+	mockCodeTTL   = time.Minute
+	mockTokenTTL  = 5 * time.Minute
+	mockKeyLength = 2048
+)
+
+var mockLoginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><title>Mock OIDC</title></head><body>
+<h1>Mock OpenID Connect</h1>
+<form method="post">
+<label>Email <input type="email" name="email" required></label>
+<label>Groups <input type="text" name="groups" placeholder="hr-admins,staff"></label>
+<button type="submit">Sign in</button>
+</form>
+</body></html>`))
+
+type mockGrant struct {
+	identity      models.OIDCIdentity
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	expiresAt     time.Time
+}
+
+// MockIssuer is an OpenID Connect provider for development and tests, mount it at the path of the
+// issuer URL. Known identities sign in directly with login_hint, anyone else types an email and groups.
+// It signs in whoever asks, never expose it in production.
+type MockIssuer struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+	identities   map[string]models.OIDCIdentity
+	mu           sync.Mutex
+	codes        map[string]mockGrant
+}
+
+func NewMockIssuer(issuer string, clientID string, clientSecret string, identities ...models.OIDCIdentity) (*MockIssuer, *models.SystemError) {
+	key, err := rsa.GenerateKey(rand.Reader, mockKeyLength)
+	if err != nil {
+		return nil, internalError("could not generate the mock issuer key: " + err.Error())
+	}
+	known := make(map[string]models.OIDCIdentity, len(identities))
+	for _, identity := range identities {
+		known[strings.ToLower(identity.Email)] = identity
+	}
+	return &MockIssuer{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		key:          key,
+		identities:   known,
+		codes:        map[string]mockGrant{},
+	}, nil
+}
+
+func (m *MockIssuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+		m.discovery(w)
+	case strings.HasSuffix(r.URL.Path, "/jwks"):
+		m.jwks(w)
+	case strings.HasSuffix(r.URL.Path, "/authorize"):
+		m.authorize(w, r)
+	case strings.HasSuffix(r.URL.Path, "/token"):
+		m.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *MockIssuer) discovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                m.issuer,
+		"authorization_endpoint":                m.issuer + "/authorize",
+		"token_endpoint":                        m.issuer + "/token",
+		"jwks_uri":                              m.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (m *MockIssuer) jwks(w http.ResponseWriter) {
+	public := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"kid": mockKeyID,
+		"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}}})
+}
+
+func (m *MockIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	query := r.Form
+	redirectURI := query.Get("redirect_uri")
+	if query.Get("response_type") != "code" || query.Get("client_id") != m.clientID || redirectURI == "" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	var identity models.OIDCIdentity
+	if r.Method == http.MethodPost {
+		email := strings.ToLower(strings.TrimSpace(query.Get("email")))
+		if email == "" {
+			http.Error(w, "email is required", http.StatusBadRequest)
+			return
+		}
+		identity = models.OIDCIdentity{Subject: email, Email: email, EmailVerified: true, Groups: []string{}}
+		for _, group := range strings.Split(query.Get("groups"), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	} else if known, ok := m.identities[strings.ToLower(query.Get("login_hint"))]; ok {
+		identity = known
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		// the form posts back to this URL, keeping the parameters of the request
+		_ = mockLoginPage.Execute(w, nil)
+		return
+	}
+	if identity.Subject == "" {
+		identity.Subject = identity.Email
+	}
+
+	code := randomToken()
+	m.mu.Lock()
+	m.codes[code] = mockGrant{
+		identity:      identity,
+		clientID:      m.clientID,
+		redirectURI:   redirectURI,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		expiresAt:     time.Now().Add(mockCodeTTL),
+	}
+	m.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	values := target.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	target.RawQuery = values.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (m *MockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "invalid_request")
+		return
+	}
+	clientID, secret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+	}
+	if clientID != m.clientID || subtle.ConstantTimeCompare([]byte(secret), []byte(m.clientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// codes are single use, a replay finds nothing
+	m.mu.Lock()
+	grant, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if !ok || time.Now().After(grant.expiresAt) || grant.clientID != clientID ||
+		grant.redirectURI != r.PostForm.Get("redirect_uri") || challenge != grant.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.issuer,
+		"sub":            grant.identity.Subject,
+		"aud":            m.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(mockTokenTTL).Unix(),
+		"email":          grant.identity.Email,
+		"email_verified": grant.identity.EmailVerified,
+		"given_name":     grant.identity.Name,
+		"family_name":    grant.identity.LastName,
+		"groups":         grant.identity.Groups,
+	}
+	if grant.nonce != "" {
+		claims["nonce"] = grant.nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomToken(),
+		"token_type":   "Bearer",
+		"expires_in":   int(mockTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func randomToken() string {
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
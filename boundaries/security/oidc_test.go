@@ -0,0 +1,113 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+const testRedirectURL = "http://hrms.test/auth/callback"
+
+func newTestIssuer(t *testing.T) (contracts.OIDCProviderContract, *http.Client) {
+	t.Helper()
+	var issuer *MockIssuer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	var err *models.SystemError
+	issuer, err = NewMockIssuer(server.URL, "hrms", "secret", models.OIDCIdentity{
+		Subject:       "42",
+		Email:         "Ana@Example.com",
+		EmailVerified: true,
+		Name:          "Ana",
+		LastName:      "Pérez",
+		Groups:        []string{"hr-admins", "staff"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	provider := NewOIDCProvider(OIDCConfig{IssuerURL: server.URL, ClientID: "hrms", ClientSecret: "secret", RedirectURL: testRedirectURL})
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	return provider, browser
+}
+
+// authorize signs in at the provider and returns the code it redirected with
+func authorize(t *testing.T, provider contracts.OIDCProviderContract, browser *http.Client, verifier string, nonce string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(verifier))
+	address, err := provider.AuthorizationURL("state-1", nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response, getErr := browser.Get(address + "&login_hint=ana@example.com")
+	if getErr != nil {
+		t.Fatal(getErr)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("Expected a redirect, got %d", response.StatusCode)
+	}
+	location, parseErr := url.Parse(response.Header.Get("Location"))
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	if location.Query().Get("state") != "state-1" {
+		t.Fatalf("Expected the state back, got %q", location.Query().Get("state"))
+	}
+	return location.Query().Get("code")
+}
+
+func TestOIDCProviderCodeFlow(t *testing.T) {
+	provider, browser := newTestIssuer(t)
+	code := authorize(t, provider, browser, "verifier-1", "nonce-1")
+
+	identity, err := provider.Exchange(code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.Subject != "42" || identity.Email != "ana@example.com" || !identity.EmailVerified {
+		t.Fatalf("Unexpected identity %+v", identity)
+	}
+	if identity.LastName != "Pérez" || len(identity.Groups) != 2 || identity.Groups[0] != "hr-admins" {
+		t.Fatalf("Expected profile and groups, got %+v", identity)
+	}
+
+	if _, err := provider.Exchange(code, "verifier-1", "nonce-1"); err == nil {
+		t.Fatal("Expected a used code to be refused")
+	}
+}
+
+func TestOIDCProviderRejectsWrongVerifierAndNonce(t *testing.T) {
+	provider, browser := newTestIssuer(t)
+
+	code := authorize(t, provider, browser, "verifier-1", "nonce-1")
+	if _, err := provider.Exchange(code, "another-verifier", "nonce-1"); err == nil {
+		t.Fatal("Expected a wrong PKCE verifier to be refused")
+	}
+
+	code = authorize(t, provider, browser, "verifier-2", "nonce-2")
+	if _, err := provider.Exchange(code, "verifier-2", "replayed-nonce"); err == nil {
+		t.Fatal("Expected an ID token with another nonce to be refused")
+	}
+}
+
+func TestOIDCProviderRejectsCodeOfAnotherClient(t *testing.T) {
+	provider, browser := newTestIssuer(t)
+	code := authorize(t, provider, browser, "verifier-1", "nonce-1")
+
+	// the code was issued to hrms, another client of the same issuer cannot redeem it
+	other := provider.(*OIDCProvider)
+	other.config.ClientID = "another-client"
+	other.config.ClientSecret = ""
+	if _, err := other.Exchange(code, "verifier-1", "nonce-1"); err == nil {
+		t.Fatal("Expected a token exchange for another client to be refused")
+	}
+}
@@ -0,0 +1,129 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// PasswordPolicyImpl checks the composition rules of a models.PasswordPolicy and a list of breached passwords
+type PasswordPolicyImpl struct {
+	policy   models.PasswordPolicy
+	breached map[string]struct{}
+}
+
+// NewPasswordPolicy loads the breached password list from breachedFile, an empty path disables that check.
+// The file holds one entry per line, either a plain password or the upper or lower case SHA-1 of one,
+// optionally followed by ":count" as in the Have I Been Pwned downloads. Lines starting with # are ignored.
+func NewPasswordPolicy(policy models.PasswordPolicy, breachedFile string) (contracts.PasswordPolicyContract, *models.SystemError) {
+	impl := &PasswordPolicyImpl{policy: policy, breached: map[string]struct{}{}}
+	if breachedFile == "" {
+		return impl, nil
+	}
+	file, err := os.Open(breachedFile)
+	if err != nil {
+		return nil, internalError("could not open breached password list: " + err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1(hash) {
+			impl.breached[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		impl.breached[sha1Hex(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, internalError("could not read breached password list: " + err.Error())
+	}
+	return impl, nil
+}
+
+func (p *PasswordPolicyImpl) Policy() models.PasswordPolicy {
+	return p.policy
+}
+
+func (p *PasswordPolicyImpl) Check(username string, password string) []models.PasswordViolation {
+	violations := []models.PasswordViolation{}
+	add := func(rule models.PasswordRule, message string) {
+		violations = append(violations, models.PasswordViolation{Rule: rule, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.policy.MinLength {
+		add(models.PasswordRuleMinLength, fmt.Sprintf("Debe tener al menos %d caracteres", p.policy.MinLength))
+	}
+	if len(password) > models.PasswordMaxBytes {
+		add(models.PasswordRuleMaxLength, fmt.Sprintf("No puede superar los %d bytes", models.PasswordMaxBytes))
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.policy.RequireUpper && !upper {
+		add(models.PasswordRuleUppercase, "Debe contener una letra mayúscula")
+	}
+	if p.policy.RequireLower && !lower {
+		add(models.PasswordRuleLowercase, "Debe contener una letra minúscula")
+	}
+	if p.policy.RequireDigit && !digit {
+		add(models.PasswordRuleDigit, "Debe contener un número")
+	}
+	if p.policy.RequireSymbol && !symbol {
+		add(models.PasswordRuleSymbol, "Debe contener un símbolo")
+	}
+	if p.isBreached(username, password) {
+		add(models.PasswordRuleBreached, "La contraseña es demasiado común o aparece en filtraciones conocidas")
+	}
+	return violations
+}
+
+// isBreached also refuses the lower case form and the username, lists usually hold lower case entries
+func (p *PasswordPolicyImpl) isBreached(username string, password string) bool {
+	if username != "" && strings.EqualFold(username, password) {
+		return true
+	}
+	if len(p.breached) == 0 {
+		return false
+	}
+	for _, candidate := range []string{password, strings.ToLower(password)} {
+		if _, ok := p.breached[sha1Hex(candidate)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sha1Hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1(value string) bool {
+	if len(value) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
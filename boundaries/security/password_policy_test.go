@@ -0,0 +1,70 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hrms.local/core/models"
+)
+
+func rules(violations []models.PasswordViolation) map[models.PasswordRule]bool {
+	found := map[models.PasswordRule]bool{}
+	for _, v := range violations {
+		found[v.Rule] = true
+	}
+	return found
+}
+
+func TestPasswordPolicyComposition(t *testing.T) {
+	policy, err := NewPasswordPolicy(models.PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := rules(policy.Check("jdoe", "short"))
+	for _, rule := range []models.PasswordRule{models.PasswordRuleMinLength, models.PasswordRuleUppercase, models.PasswordRuleDigit, models.PasswordRuleSymbol} {
+		if !found[rule] {
+			t.Fatalf("Expected %s violation, got %v", rule, found)
+		}
+	}
+	if found[models.PasswordRuleLowercase] {
+		t.Fatal("Expected lower case letters to satisfy the lowercase rule")
+	}
+
+	if violations := policy.Check("jdoe", "Ñandú-2025-seguro"); len(violations) != 0 {
+		t.Fatalf("Expected password to be accepted, got %v", violations)
+	}
+	if found := rules(policy.Check("jdoe", string(make([]byte, 73)))); !found[models.PasswordRuleMaxLength] {
+		t.Fatal("Expected passwords longer than bcrypt accepts to be refused")
+	}
+}
+
+func TestPasswordPolicyBreachedList(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# common passwords\npassword123\n" +
+		// SHA-1 of "Verano2024!" in the Have I Been Pwned format
+		"591EA73A6051392DAB1AF8D052EEF3E83B811013:12\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewPasswordPolicy(models.PasswordPolicy{}, file)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !rules(policy.Check("jdoe", "Password123"))[models.PasswordRuleBreached] {
+		t.Fatal("Expected listed password to be refused regardless of case")
+	}
+	if !rules(policy.Check("jdoe", "Verano2024!"))[models.PasswordRuleBreached] {
+		t.Fatal("Expected password listed by its SHA-1 to be refused")
+	}
+	if !rules(policy.Check("jdoe", "JDoe"))[models.PasswordRuleBreached] {
+		t.Fatal("Expected the username to be refused as password")
+	}
+	if len(policy.Check("jdoe", "otra-clave")) != 0 {
+		t.Fatal("Expected unlisted password to be accepted")
+	}
+	if _, err := NewPasswordPolicy(models.PasswordPolicy{}, filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Fatal("Expected a missing list to be reported")
+	}
+}
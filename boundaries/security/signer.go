@@ -0,0 +1,229 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minRSABits is the smallest RSA key accepted for signing tokens
+const minRSABits = 2048
+
+// SigningKey is a key known by the token signer, keys without a private part only verify tokens
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	sign      any
+	verify    any
+}
+
+// CanSign reports whether the key holds its private part
+func (k SigningKey) CanSign() bool {
+	return k.sign != nil
+}
+
+// NewHMACSigningKey returns a symmetric HS256 key, it is never published in the JWKS
+func NewHMACSigningKey(id string, secret []byte) SigningKey {
+	return SigningKey{ID: id, Algorithm: AlgorithmHS256, sign: secret, verify: secret}
+}
+
+// NewSigningKey wraps an RSA or Ed25519 key, public keys are kept to verify tokens of retired keys
+func NewSigningKey(id string, key any) (SigningKey, *models.SystemError) {
+	if id == "" {
+		return SigningKey{}, internalError("signing key id is required")
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return SigningKey{}, internalError("RSA key " + id + " is shorter than 2048 bits")
+		}
+		return SigningKey{ID: id, Algorithm: AlgorithmRS256, sign: k, verify: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return SigningKey{ID: id, Algorithm: AlgorithmRS256, verify: k}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{ID: id, Algorithm: AlgorithmEdDSA, sign: k, verify: k.Public()}, nil
+	case ed25519.PublicKey:
+		return SigningKey{ID: id, Algorithm: AlgorithmEdDSA, verify: k}, nil
+	}
+	return SigningKey{}, internalError("unsupported key type for " + id + ", use RSA or Ed25519")
+}
+
+// GenerateSigningKey creates an Ed25519 key, meant for development where no key is configured
+func GenerateSigningKey(id string) (SigningKey, *models.SystemError) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, internalError("could not generate signing key: " + err.Error())
+	}
+	return NewSigningKey(id, private)
+}
+
+// ParseSigningKey reads a PEM encoded PKCS#8, PKCS#1 or PKIX key
+func ParseSigningKey(id string, data []byte) (SigningKey, *models.SystemError) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, internalError("key " + id + " is not PEM encoded")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return SigningKey{}, internalError("unsupported PEM block " + block.Type + " in key " + id)
+	}
+	if err != nil {
+		return SigningKey{}, internalError("could not parse key " + id + ": " + err.Error())
+	}
+	return NewSigningKey(id, key)
+}
+
+// LoadSigningKeys reads every *.pem file of the directory, the file name without extension is the kid.
+// Keys are sorted by kid so naming files by date makes the newest one the default active key.
+func LoadSigningKeys(dir string) ([]SigningKey, *models.SystemError) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, internalError("could not list keys in " + dir + ": " + err.Error())
+	}
+	sort.Strings(files)
+	keys := make([]SigningKey, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, internalError("could not read key " + file + ": " + err.Error())
+		}
+		key, sysErr := ParseSigningKey(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if sysErr != nil {
+			return nil, sysErr
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, internalError("no signing keys found in " + dir)
+	}
+	return keys, nil
+}
+
+// TokenSigner signs with a single active key and verifies with every known key,
+// so rotating the active key does not invalidate the tokens already issued.
+type TokenSigner struct {
+	active SigningKey
+	keys   map[string]SigningKey
+	order  []string
+}
+
+// NewTokenSigner uses the key named activeID to sign, or the last key able to sign when it is empty
+func NewTokenSigner(activeID string, keys ...SigningKey) (contracts.TokenSignerContract, *models.SystemError) {
+	signer := &TokenSigner{keys: make(map[string]SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, exists := signer.keys[key.ID]; exists {
+			return nil, internalError("duplicated signing key id " + key.ID)
+		}
+		signer.keys[key.ID] = key
+		signer.order = append(signer.order, key.ID)
+		if activeID == "" && key.CanSign() {
+			signer.active = key
+		}
+	}
+	if activeID != "" {
+		key, ok := signer.keys[activeID]
+		if !ok {
+			return nil, internalError("active signing key " + activeID + " not found")
+		}
+		signer.active = key
+	}
+	if !signer.active.CanSign() {
+		return nil, internalError("no private key available to sign tokens")
+	}
+	return signer, nil
+}
+
+func (s *TokenSigner) Sign(claims map[string]any) (string, *models.SystemError) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.active.Algorithm), jwt.MapClaims(claims))
+	token.Header["kid"] = s.active.ID
+	signed, err := token.SignedString(s.active.sign)
+	if err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not sign token: "+err.Error(), struct{}{})
+	}
+	return signed, nil
+}
+
+func (s *TokenSigner) Verify(tokenString string) (map[string]any, *models.SystemError) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		// the algorithm is bound to the key, never to the header
+		if token.Method.Alg() != key.Algorithm {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verify, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid or expired token", struct{}{})
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid or expired token", struct{}{})
+	}
+	return claims, nil
+}
+
+func (s *TokenSigner) PublicKeys() models.JSONWebKeySet {
+	set := models.JSONWebKeySet{Keys: []models.JSONWebKey{}}
+	for _, id := range s.order {
+		key := s.keys[id]
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, models.JSONWebKey{
+				Kty: "RSA",
+				Use: "sig",
+				Kid: key.ID,
+				Alg: key.Algorithm,
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, models.JSONWebKey{
+				Kty: "OKP",
+				Use: "sig",
+				Kid: key.ID,
+				Alg: key.Algorithm,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return set
+}
+
+func internalError(message string) *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, message, struct{}{})
+}
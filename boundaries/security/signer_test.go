@@ -0,0 +1,130 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokenSignerRotation(t *testing.T) {
+	old, err := GenerateSigningKey("2026-01")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rsaKey, genErr := rsa.GenerateKey(rand.Reader, 2048)
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	current, err := NewSigningKey("2026-02", rsaKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	before, err := NewTokenSigner("", old)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	issued, err := before.Sign(map[string]any{"sub": "admin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// rotate: the new key signs, the old one keeps verifying
+	after, err := NewTokenSigner("2026-02", old, current)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	claims, err := after.Verify(issued)
+	if err != nil {
+		t.Fatalf("Expected token of the previous key to verify, got %v", err)
+	}
+	if claims["sub"] != "admin" {
+		t.Fatalf("Expected sub admin, got %v", claims["sub"])
+	}
+
+	fresh, err := after.Sign(map[string]any{"sub": "admin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	parsed, _, parseErr := jwt.NewParser().ParseUnverified(fresh, jwt.MapClaims{})
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	if parsed.Header["kid"] != "2026-02" || parsed.Method.Alg() != AlgorithmRS256 {
+		t.Fatalf("Expected token signed by 2026-02 with RS256, got %v %v", parsed.Header["kid"], parsed.Method.Alg())
+	}
+
+	if _, err := before.Verify(fresh); err == nil {
+		t.Fatal("Expected token of an unknown key to be rejected")
+	}
+
+	jwks := after.PublicKeys()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kty != "OKP" || jwks.Keys[1].Kty != "RSA" {
+		t.Fatalf("Expected the two public keys in the JWKS, got %+v", jwks.Keys)
+	}
+}
+
+func TestTokenSignerRejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, genErr := rsa.GenerateKey(rand.Reader, 2048)
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	key, err := NewSigningKey("rsa", rsaKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	signer, err := NewTokenSigner("", key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// an HS256 token keyed with the published public key must not verify
+	public := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin"})
+	forged.Header["kid"] = "rsa"
+	token, signErr := forged.SignedString(public)
+	if signErr != nil {
+		t.Fatal(signErr)
+	}
+	if _, err := signer.Verify(token); err == nil {
+		t.Fatal("Expected HS256 token to be rejected for an RSA key")
+	}
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, genErr := rsa.GenerateKey(rand.Reader, 2048)
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	private, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	public, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	write := func(name, kind string, der []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der})
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("2025-12.pem", "PUBLIC KEY", public)
+	write("2026-01.pem", "PRIVATE KEY", private)
+
+	keys, err := LoadSigningKeys(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 2 || keys[0].CanSign() || !keys[1].CanSign() {
+		t.Fatalf("Expected a verify only key and a signing key, got %+v", keys)
+	}
+	if _, err := NewTokenSigner("2025-12", keys...); err == nil {
+		t.Fatal("Expected a public key to be refused as the active key")
+	}
+	if _, err := LoadSigningKeys(t.TempDir()); err == nil {
+		t.Fatal("Expected an empty directory to be refused")
+	}
+}
@@ -0,0 +1,82 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+const (
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew is the number of steps accepted before and after the current one, clocks drift
+	totpSkew = 1
+	// totpSecretBytes is the 160 bits recommended by RFC 4226
+	totpSecretBytes = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPImpl implements RFC 6238 with the parameters every authenticator app supports: SHA-1, 6 digits, 30 seconds
+type TOTPImpl struct {
+	issuer string
+}
+
+func NewTOTP(issuer string) contracts.OTPContract {
+	return &TOTPImpl{issuer: issuer}
+}
+
+func (t *TOTPImpl) GenerateSecret() (string, *models.SystemError) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", internalError("could not generate MFA secret: " + err.Error())
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+func (t *TOTPImpl) URI(account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(t.issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func (t *TOTPImpl) Verify(secret string, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	current := at.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// hotp computes the RFC 4226 code of a counter
+func hotp(key []byte, counter int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
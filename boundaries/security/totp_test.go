@@ -0,0 +1,65 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors, "12345678901234567890" in base32
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPMatchesRFC6238Vectors(t *testing.T) {
+	// the RFC lists 8 digit codes, the last 6 digits are the 6 digit code
+	vectors := []struct {
+		at   int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	totp := NewTOTP("HRMS")
+	for _, vector := range vectors {
+		step, ok := totp.Verify(rfc6238Secret, vector.code, time.Unix(vector.at, 0))
+		if !ok {
+			t.Fatalf("Expected code %s to be valid at %d", vector.code, vector.at)
+		}
+		if step != vector.at/totpPeriod {
+			t.Fatalf("Expected step %d, got %d", vector.at/totpPeriod, step)
+		}
+	}
+}
+
+func TestTOTPAcceptsOneStepOfDrift(t *testing.T) {
+	totp := NewTOTP("HRMS")
+	at := time.Unix(59, 0)
+	if _, ok := totp.Verify(rfc6238Secret, "287082", at.Add(totpPeriod*time.Second)); !ok {
+		t.Fatal("Expected the code of the previous step to be accepted")
+	}
+	if _, ok := totp.Verify(rfc6238Secret, "287082", at.Add(2*totpPeriod*time.Second)); ok {
+		t.Fatal("Expected the code of two steps ago to be rejected")
+	}
+	if _, ok := totp.Verify(rfc6238Secret, "000000", at); ok {
+		t.Fatal("Expected a wrong code to be rejected")
+	}
+}
+
+func TestTOTPSecretAndURI(t *testing.T) {
+	totp := NewTOTP("Acme HR")
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	key, decodeErr := totpEncoding.DecodeString(secret)
+	if decodeErr != nil || len(key) != totpSecretBytes {
+		t.Fatalf("Expected a base32 secret of %d bytes, got %q", totpSecretBytes, secret)
+	}
+	uri := totp.URI("admin", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Acme%20HR:admin?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("Unexpected otpauth URI %s", uri)
+	}
+}
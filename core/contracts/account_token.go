@@ -0,0 +1,24 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define the single use tokens mailed to the users
+// example :
+//
+//	token, err := accountTokenContract.GetOnce("token_hash", hash)
+//	if err != nil {
+//		return nil, err
+//	}
+//	used, err := accountTokenContract.Use(token.ID, time.Now())
+type AccountTokenContract interface {
+	ReadOperation[models.AccountToken]
+	WriteOperation[models.AccountToken]
+	// Mark a token as used, false when it was already used
+	Use(id string, at time.Time) (bool, *models.SystemError)
+	// Mark every unused token of the user for the purpose as used
+	Invalidate(userID string, purpose models.AccountTokenPurpose, at time.Time) *models.SystemError
+}
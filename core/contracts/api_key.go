@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define the service accounts owning the API keys
+// example :
+//
+//	account, err := serviceAccountContract.GetOnce("name", "bank-connector")
+type ServiceAccountContract interface {
+	ReadOperation[models.ServiceAccount]
+	WriteOperation[models.ServiceAccount]
+	// List every service account by name
+	All() ([]models.ServiceAccount, *models.SystemError)
+}
+
+// define the API keys of the service accounts
+// example :
+//
+//	key, err := apiKeyContract.GetOnce("key_hash", hash)
+//	if err != nil || !key.Usable(time.Now()) {
+//		return nil, err
+//	}
+//	apiKeyContract.Touch(key.ID, time.Now())
+type APIKeyContract interface {
+	ReadOperation[models.APIKey]
+	WriteOperation[models.APIKey]
+	// List the keys of a service account, revoked and expired ones included
+	ByServiceAccount(serviceAccountID string) ([]models.APIKey, *models.SystemError)
+	// Revoke a key, false when it does not exist or was already revoked
+	Revoke(id string, at time.Time) (bool, *models.SystemError)
+	// Record the last use of a key
+	Touch(id string, at time.Time) *models.SystemError
+}
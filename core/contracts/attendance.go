@@ -0,0 +1,39 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define attendance operations
+// example :
+//
+//	events, err := attendanceContract.GetEventsBetween("employee-id", from, to)
+//	if err != nil {
+//		return nil, err
+//	}
+//	last, err := attendanceContract.GetLastEvent("employee-id")
+type AttendanceContract interface {
+	// define basic read operations
+	ReadOperation[models.AttendanceEvent]
+	// define basic write operations
+	WriteOperation[models.AttendanceEvent]
+
+	// Get the events of an employee between two instants ordered by timestamp,
+	// an empty employeeID returns the events of every employee
+	GetEventsBetween(employeeID string, from, to time.Time) ([]models.AttendanceEvent, *models.SystemError)
+	// Get the most recent event of an employee, nil when the employee never clocked
+	GetLastEvent(employeeID string) (*models.AttendanceEvent, *models.SystemError)
+}
+
+// define work schedule operations
+type WorkScheduleContract interface {
+	// define basic read operations
+	ReadOperation[models.WorkSchedule]
+	// define basic write operations
+	WriteOperation[models.WorkSchedule]
+
+	// Get the schedule applied when no schedule is requested explicitly
+	GetDefault() (*models.WorkSchedule, *models.SystemError)
+}
@@ -0,0 +1,12 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define the audit log, entries are only appended
+// example :
+//
+//	err := auditContract.Record(models.AuditEntry{Action: models.AuditActionAccountLocked, Subject: "HR"})
+type AuditContract interface {
+	ReadOperation[models.AuditEntry]
+	Record(entry models.AuditEntry) *models.SystemError
+}
@@ -16,6 +16,4 @@ type DepartmentContract interface {
 	ReadOperation[models.Department]
 	// define basic write operations
 	WriteOperation[models.Department]
-
-	SomeMethod() models.SystemError
 }
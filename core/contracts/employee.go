@@ -0,0 +1,19 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define basic employee operations
+// example :
+//
+//	employeeContract := NewEmployeeContract()
+//	employeeContract.Create(models.Employee{EmployeeNumber: "E-001", FirstName: "John", LastName: "Doe"})
+//	employeeContract.Update("1", models.Employee{ID: "1", EmployeeNumber: "E-001", FirstName: "John"})
+//	employeeContract.Delete("1")
+//	employeeContract.GetOnce("id", "1")
+//	employeeContract.GetByFilter(models.SearchQuery{Filters: models.Filters{{Key: "Status", Value: "active"}}})
+type EmployeeContract interface {
+	// define basic read operations
+	ReadOperation[models.Employee]
+	// define basic write operations
+	WriteOperation[models.Employee]
+}
@@ -6,7 +6,7 @@ import "hrms.local/core/models"
 type ReadOperation[T any] interface {
 	// Get resource by filter in repository
 	// example :
-	// 		data,err:=GetByFilter(models.Filter{Key: "name", Value: "HR"} )
+	// 		data,err:=GetByFilter(models.SearchQuery{Filters: models.Filters{{Key: "Name", Value: "HR"}}})
 	// 		if err != nil {
 	// 			return nil, err
 	// 		}
@@ -0,0 +1,26 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define the invitations to register issued by administrators
+// example :
+//
+//	invitation, err := invitationContract.GetOnce("token_hash", hash)
+//	if err != nil {
+//		return nil, err
+//	}
+//	accepted, err := invitationContract.Accept(invitation.ID, time.Now())
+type InvitationContract interface {
+	ReadOperation[models.Invitation]
+	WriteOperation[models.Invitation]
+	// Mark the invitation as used, false when it was already used or revoked
+	Accept(id string, at time.Time) (bool, *models.SystemError)
+	// Cancel the invitation, false when it was already used or revoked
+	Revoke(id string, at time.Time) (bool, *models.SystemError)
+	// Invitations neither used, revoked nor expired, newest first
+	Pending(now time.Time) ([]models.Invitation, *models.SystemError)
+}
@@ -0,0 +1,34 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define leave type operations
+type LeaveTypeContract interface {
+	// define basic read operations
+	ReadOperation[models.LeaveType]
+	// define basic write operations
+	WriteOperation[models.LeaveType]
+}
+
+// define leave request operations
+// example :
+//
+//	requests, err := leaveRequestContract.GetOverlapping("employee-id", start, end, []models.LeaveStatus{models.LeaveStatusApproved})
+//	if err != nil {
+//		return nil, err
+//	}
+type LeaveRequestContract interface {
+	// define basic read operations
+	ReadOperation[models.LeaveRequest]
+	// define basic write operations
+	WriteOperation[models.LeaveRequest]
+
+	// Get the requests of an employee sharing at least one day with [start, end] in any of the given statuses
+	GetOverlapping(employeeID string, start, end time.Time, statuses []models.LeaveStatus) ([]models.LeaveRequest, *models.SystemError)
+	// Move a request to the status of request with its decision, only when it still holds the status from
+	Transition(request models.LeaveRequest, from models.LeaveStatus) (models.LeaveRequest, *models.SystemError)
+}
@@ -0,0 +1,19 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define the delivery of emails
+// example :
+//
+//	err := mailerContract.Send(models.Mail{To: "hr@example.com", Subject: "Hola", Text: "..."})
+type MailerContract interface {
+	Send(mail models.Mail) *models.SystemError
+}
+
+// define the rendering of the templated emails in every supported language
+// example :
+//
+//	mail, err := mailTemplateContract.Render(models.MailMessage{Template: models.MailTemplatePasswordReset, Language: models.LanguageEnglish, To: "hr@example.com"})
+type MailTemplateContract interface {
+	Render(message models.MailMessage) (models.Mail, *models.SystemError)
+}
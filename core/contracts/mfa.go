@@ -0,0 +1,33 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define the one time passwords of the authenticator apps
+// example :
+//
+//	secret, err := otpContract.GenerateSecret()
+//	uri := otpContract.URI("jdoe", secret)
+//	step, ok := otpContract.Verify(secret, "123456", time.Now())
+type OTPContract interface {
+	// Random secret encoded in base32
+	GenerateSecret() (string, *models.SystemError)
+	// otpauth:// URI shown as QR code to the user
+	URI(account string, secret string) string
+	// Time step matched by the code, ok is false when the code is wrong
+	Verify(secret string, code string, at time.Time) (int64, bool)
+}
+
+// define the hashed recovery codes of the users
+type RecoveryCodeContract interface {
+	// Replace every code of the user with the given hashes
+	Replace(userID string, hashes []string) *models.SystemError
+	// Codes of the user not used yet
+	Unused(userID string) ([]models.RecoveryCode, *models.SystemError)
+	// Mark a code as used, false when it was already used
+	Use(id string, at time.Time) (bool, *models.SystemError)
+	DeleteByUser(userID string) *models.SystemError
+}
@@ -0,0 +1,16 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define the OpenID Connect provider users sign in with
+// example :
+//
+//	url, err := oidcContract.AuthorizationURL(state, nonce, challenge)
+//	// the user signs in at url and comes back with a code
+//	identity, err := oidcContract.Exchange(code, verifier, nonce)
+type OIDCProviderContract interface {
+	// Authorization endpoint URL of an authorization code request protected with PKCE S256
+	AuthorizationURL(state string, nonce string, codeChallenge string) (string, *models.SystemError)
+	// Redeem the code and return the identity of its verified ID token
+	Exchange(code string, codeVerifier string, nonce string) (*models.OIDCIdentity, *models.SystemError)
+}
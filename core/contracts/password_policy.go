@@ -0,0 +1,24 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define the rules passwords must follow
+// example :
+//
+//	violations := passwordPolicy.Check("HR", "password")
+//	if len(violations) > 0 {
+//		return models.NewPasswordPolicyError(passwordPolicy.Policy(), violations)
+//	}
+type PasswordPolicyContract interface {
+	Policy() models.PasswordPolicy
+	// Check the composition rules and the breached password list, empty when the password is accepted
+	Check(username string, password string) []models.PasswordViolation
+}
+
+// define the previous password hashes of the users
+type PasswordHistoryContract interface {
+	// Hashes of the last passwords of the user, newest first
+	Recent(userID string, limit int) ([]string, *models.SystemError)
+	// Record the hash of a new password keeping only the last keep hashes of the user
+	Record(userID string, hash string, keep int) *models.SystemError
+}
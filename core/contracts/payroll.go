@@ -0,0 +1,62 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define pay period operations
+type PayPeriodContract interface {
+	// define basic read operations
+	ReadOperation[models.PayPeriod]
+	// define basic write operations
+	WriteOperation[models.PayPeriod]
+
+	// Get the periods sharing at least one day with [start, end]
+	GetOverlapping(start, end time.Time) ([]models.PayPeriod, *models.SystemError)
+}
+
+// define payroll run operations
+type PayrollRunContract interface {
+	// define basic read operations
+	ReadOperation[models.PayrollRun]
+	// define basic write operations
+	WriteOperation[models.PayrollRun]
+
+	// Replace the payslips and totals of a draft run at once, creating the run when it has no id.
+	// Fails without changing anything when the run is no longer a draft.
+	SaveCalculation(run models.PayrollRun, payslips []models.Payslip) (models.PayrollRun, *models.SystemError)
+	// Move a run to the status of run, only when it still holds the status from
+	ChangeStatus(run models.PayrollRun, from models.PayrollStatus) (models.PayrollRun, *models.SystemError)
+	// Delete a draft run and its payslips at once, fails without deleting anything when the run is no longer a draft
+	DeleteDraft(id string) *models.SystemError
+}
+
+// define payslip operations
+type PayslipContract interface {
+	// define basic read operations
+	ReadOperation[models.Payslip]
+	// define basic write operations
+	WriteOperation[models.Payslip]
+}
+
+// PayrollRuleContract computes one earning or deduction of a payslip.
+// Earning rules run first and deduction rules after them, each rule sees the payslip built so far.
+// A rule returns a nil line when it does not apply to the payslip.
+// example :
+//
+//	line, err := rule.Calculate(payslip)
+//	if err != nil {
+//		return err
+//	}
+//	if line != nil {
+//		payslip.AddLine(*line)
+//	}
+type PayrollRuleContract interface {
+	// Unique code of the rule, used as the code of the lines it produces
+	Code() string
+	// Whether the rule produces earnings or deductions
+	Kind() models.PayslipLineKind
+	Calculate(payslip models.Payslip) (*models.PayslipLine, *models.SystemError)
+}
@@ -0,0 +1,18 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define payroll export operations
+// example :
+//
+//	pdf, err := payrollExportContract.RenderPayslipPDF(document)
+//	if err != nil {
+//		return nil, err
+//	}
+//	file, err := payrollExportContract.GenerateBankFile(batch, models.BankFileFormatCSV)
+type PayrollExportContract interface {
+	// Render the payslip of an employee as a PDF document
+	RenderPayslipPDF(document models.PayslipDocument) ([]byte, *models.SystemError)
+	// Generate the transfer file sent to the bank to pay a run
+	GenerateBankFile(batch models.BankTransferBatch, format models.BankFileFormat) ([]byte, *models.SystemError)
+}
@@ -0,0 +1,11 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define the full-text search of users and employees, results come ranked best first
+// example :
+//
+//	results, err := searchContract.Search(models.PeopleSearch{Query: "jose garcia", Kinds: []models.SearchKind{models.SearchKindEmployee}, Limit: 20})
+type SearchContract interface {
+	Search(search models.PeopleSearch) ([]models.SearchResult, *models.SystemError)
+}
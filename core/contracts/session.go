@@ -0,0 +1,33 @@
+package contracts
+
+import (
+	"time"
+
+	"hrms.local/core/models"
+)
+
+// define refresh token operations
+// example :
+//
+//	token, err := refreshTokenContract.GetOnce("token_hash", hash)
+//	if err != nil {
+//		return nil, err
+//	}
+//	err = refreshTokenContract.RevokeFamily(token.FamilyID, time.Now())
+type RefreshTokenContract interface {
+	ReadOperation[models.RefreshToken]
+	WriteOperation[models.RefreshToken]
+	// Revoke an active token replaced by another one, false when the token was already revoked
+	Revoke(id string, replacedByID string, at time.Time) (bool, *models.SystemError)
+	// Revoke every active token of a family
+	RevokeFamily(familyID string, at time.Time) *models.SystemError
+	// Revoke every active token of a user
+	RevokeByUser(userID string, at time.Time) *models.SystemError
+}
+
+// define the denylist of access tokens revoked before their expiration
+type TokenDenylistContract interface {
+	// Revoke a token, false when it was already revoked
+	Revoke(token models.RevokedToken) (bool, *models.SystemError)
+	IsRevoked(jti string) (bool, *models.SystemError)
+}
@@ -0,0 +1,20 @@
+package contracts
+
+import "hrms.local/core/models"
+
+// define how access tokens are signed and verified
+// example :
+//
+//	token, err := signer.Sign(map[string]any{"sub": username})
+//	if err != nil {
+//		return err
+//	}
+//	claims, err := signer.Verify(token)
+type TokenSignerContract interface {
+	// Sign the claims with the active key, the key id travels in the kid header
+	Sign(claims map[string]any) (string, *models.SystemError)
+	// Verify a token signed by any of the known keys and return its claims
+	Verify(token string) (map[string]any, *models.SystemError)
+	// Public part of the asymmetric keys, served as the JWKS of the API
+	PublicKeys() models.JSONWebKeySet
+}
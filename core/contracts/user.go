@@ -1,6 +1,8 @@
 package contracts
 
 import (
+	"time"
+
 	"hrms.local/core/models"
 )
 
@@ -12,8 +14,20 @@ import (
 //	userContract.Update("1", models.User{ID: "1", Username: "HR", Password: "HR", Email: "HR", Type: "HR"})
 //	userContract.Delete("1")
 //	userContract.GetAll()
-//	userContract.GetByFilter(models.Filter{Key: "name", Value: "HR"})
+//	userContract.GetByFilter(models.SearchQuery{Filters: models.Filters{{Key: "Username", Value: "HR"}}})
 type UserContract interface {
 	ReadOperation[models.User]
 	WriteOperation[models.User]
+	// Count a failed login and return the consecutive failures of the user
+	RegisterFailedLogin(id string) (int, *models.SystemError)
+	// Lock the user out until the given time
+	Lock(id string, until time.Time) *models.SystemError
+	// Clear the failed logins and the lock of the user
+	ResetFailedLogins(id string) *models.SystemError
+	// Set the TOTP secret of the user and whether MFA is enabled, also forgets the last accepted step
+	UpdateMFA(id string, secret string, enabled bool) *models.SystemError
+	// Record the TOTP step of an accepted code, false when that step or a later one was already used
+	AcceptMFAStep(id string, step int64) (bool, *models.SystemError)
+	// Mark the email of the user as verified, false when the user no longer has that email
+	VerifyEmail(id string, email string, at time.Time) (bool, *models.SystemError)
 }
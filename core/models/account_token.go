@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+type AccountTokenPurpose string
+
+const (
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+const (
+	// PasswordResetTTL is how long a password reset link works
+	PasswordResetTTL = time.Hour
+	// EmailVerificationTTL is how long an email verification link works
+	EmailVerificationTTL = 48 * time.Hour
+)
+
+// AccountToken is a single use token mailed to the user, only its SHA-256 is stored
+type AccountToken struct {
+	ID        string
+	UserID    string
+	Purpose   AccountTokenPurpose
+	TokenHash string
+	// Address the token was sent to, a verification only applies while the user keeps it
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Usable reports whether the token can still be used for the purpose at the given time
+func (t *AccountToken) Usable(purpose AccountTokenPurpose, now time.Time) bool {
+	return t.Purpose == purpose && t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// ForgotPassword asks for a password reset link, the answer is the same whether the email exists or not
+type ForgotPassword struct {
+	Email    string   `json:"email"`
+	Language Language `json:"language"`
+	// Page of the client receiving the token, set by the server
+	ResetURL string `json:"-"`
+}
+
+func (f *ForgotPassword) Validate() *SystemError {
+	if f.Email == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "email is required", struct{}{})
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with the token of a reset link
+type ResetPassword struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+	// Address of the client, recorded in the audit log
+	IP string `json:"-"`
+}
+
+func (r *ResetPassword) Validate() *SystemError {
+	if r.Token == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "token is required", struct{}{})
+	}
+	if r.NewPassword == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "new password is required", struct{}{})
+	}
+	return nil
+}
+
+// SendEmailVerification mails a verification link to the current email of the user
+type SendEmailVerification struct {
+	Username  string   `json:"-"`
+	Language  Language `json:"language"`
+	VerifyURL string   `json:"-"`
+}
+
+func (s *SendEmailVerification) Validate() *SystemError {
+	if s.Username == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
+	}
+	return nil
+}
+
+// VerifyEmail confirms the email of a user with the token of a verification link
+type VerifyEmail struct {
+	Token string `json:"token"`
+}
+
+func (v *VerifyEmail) Validate() *SystemError {
+	if v.Token == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "token is required", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccountTokenUsable(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	token := AccountToken{Purpose: AccountTokenPasswordReset, ExpiresAt: now.Add(PasswordResetTTL)}
+	if !token.Usable(AccountTokenPasswordReset, now) {
+		t.Fatal("Expected a fresh token to be usable")
+	}
+	if token.Usable(AccountTokenEmailVerification, now) {
+		t.Fatal("Expected a reset token to be refused for email verification")
+	}
+	if token.Usable(AccountTokenPasswordReset, now.Add(PasswordResetTTL)) {
+		t.Fatal("Expected an expired token to be refused")
+	}
+	token.UsedAt = &now
+	if token.Usable(AccountTokenPasswordReset, now) {
+		t.Fatal("Expected a used token to be refused")
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	cases := map[string]Language{
+		"":                        LanguageSpanish,
+		"en":                      LanguageEnglish,
+		"en-US,en;q=0.9":          LanguageEnglish,
+		"fr-FR, en;q=0.8":         LanguageEnglish,
+		"es-DO":                   LanguageSpanish,
+		"de":                      DefaultLanguage,
+		"  EN-gb ; q=1, es;q=0.5": LanguageEnglish,
+	}
+	for value, expected := range cases {
+		if got := ParseLanguage(value); got != expected {
+			t.Fatalf("ParseLanguage(%q): expected %s, got %s", value, expected, got)
+		}
+	}
+}
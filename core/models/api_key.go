@@ -0,0 +1,136 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyHeader carries the key of a service account instead of a bearer token
+	APIKeyHeader = "X-API-Key"
+	// APIKeyDefaultTTL is the lifetime of a key created without ExpiresInDays
+	APIKeyDefaultTTL = 90 * 24 * time.Hour
+	// APIKeyMaxDays bounds the lifetime of a key, keys must be rotated
+	APIKeyMaxDays = 365
+	// APIKeyLastUsedPrecision avoids writing the last use on every request
+	APIKeyLastUsedPrecision = time.Minute
+)
+
+// ServiceAccount is a non human caller, such as the bank connector, authenticating with API keys
+type ServiceAccount struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Actor is the name the service account acts as, in the audit log and the handlers
+func (s *ServiceAccount) Actor() string {
+	return "service:" + s.Name
+}
+
+// APIKey grants a service account the permissions listed in Permissions.
+// Only the SHA-256 of the key is stored, Prefix identifies it in listings.
+type APIKey struct {
+	ID               string     `json:"id"`
+	ServiceAccountID string     `json:"serviceAccountId"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	KeyHash          string     `json:"-"`
+	Permissions      []string   `json:"permissions"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	LastUsedAt       *time.Time `json:"lastUsedAt"`
+	RevokedAt        *time.Time `json:"revokedAt"`
+	CreatedBy        string     `json:"createdBy"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+// Usable reports whether the key authenticates at the given time
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// Grants returns the permissions of the key in the form models.HasPermission expects
+func (k *APIKey) Grants() []Permission {
+	granted := make([]Permission, 0, len(k.Permissions))
+	for _, name := range k.Permissions {
+		granted = append(granted, Permission{Name: name})
+	}
+	return granted
+}
+
+// CreatedAPIKey is answered once on creation, Key is never shown again
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyIdentity is the caller authenticated by an API key
+type APIKeyIdentity struct {
+	ServiceAccount ServiceAccount
+	Key            APIKey
+}
+
+type CreateServiceAccount struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Actor       string `json:"-"`
+}
+
+func (cs *CreateServiceAccount) Validate() *SystemError {
+	cs.Name = strings.TrimSpace(cs.Name)
+	if cs.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	return nil
+}
+
+type CreateAPIKey struct {
+	ServiceAccountID string   `json:"serviceAccountId"`
+	Name             string   `json:"name"`
+	Permissions      []string `json:"permissions"`
+	// Lifetime of the key, APIKeyDefaultTTL when zero
+	ExpiresInDays int    `json:"expiresInDays"`
+	Actor         string `json:"-"`
+	// Permissions of the caller, a key cannot grant more than its creator holds
+	ActorPermissions []Permission `json:"-"`
+}
+
+func (ck *CreateAPIKey) Validate() *SystemError {
+	if ck.ServiceAccountID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "serviceAccountId is required", struct{}{})
+	}
+	if len(ck.Permissions) == 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "at least one permission is required", struct{}{})
+	}
+	if ck.ExpiresInDays < 0 || ck.ExpiresInDays > APIKeyMaxDays {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "expiresInDays must be between 1 and 365", struct{}{})
+	}
+	return nil
+}
+
+// TTL returns the lifetime requested for the key
+func (ck *CreateAPIKey) TTL() time.Duration {
+	if ck.ExpiresInDays == 0 {
+		return APIKeyDefaultTTL
+	}
+	return time.Duration(ck.ExpiresInDays) * 24 * time.Hour
+}
+
+type ListAPIKeys struct {
+	ServiceAccountID string `json:"serviceAccountId"`
+}
+
+type RevokeAPIKey struct {
+	ID    string `json:"id"`
+	Actor string `json:"-"`
+	IP    string `json:"-"`
+}
+
+func (rk *RevokeAPIKey) Validate() *SystemError {
+	if rk.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIKeyUsable(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	key := APIKey{ExpiresAt: now.Add(APIKeyDefaultTTL)}
+	if !key.Usable(now) {
+		t.Fatal("Expected a fresh key to be usable")
+	}
+	if key.Usable(now.Add(APIKeyDefaultTTL)) {
+		t.Fatal("Expected an expired key to be refused")
+	}
+	key.RevokedAt = &now
+	if key.Usable(now) {
+		t.Fatal("Expected a revoked key to be refused")
+	}
+}
+
+func TestAPIKeyGrantsOnlyItsPermissions(t *testing.T) {
+	key := APIKey{Permissions: []string{PermissionExportPayroll}}
+	if !HasPermission(key.Grants(), PermissionExportPayroll) {
+		t.Fatal("Expected the key to grant its permission")
+	}
+	if HasPermission(key.Grants(), PermissionEditUsers) {
+		t.Fatal("Expected the key to grant nothing else")
+	}
+}
+
+func TestCreateAPIKeyValidate(t *testing.T) {
+	request := CreateAPIKey{ServiceAccountID: "1", Permissions: []string{PermissionViewEmployees}}
+	if err := request.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if request.TTL() != APIKeyDefaultTTL {
+		t.Fatalf("Expected the default lifetime, got %v", request.TTL())
+	}
+	request.ExpiresInDays = 30
+	if request.TTL() != 30*24*time.Hour {
+		t.Fatalf("Expected 30 days, got %v", request.TTL())
+	}
+	request.ExpiresInDays = APIKeyMaxDays + 1
+	if err := request.Validate(); err == nil {
+		t.Fatal("Expected a lifetime beyond the maximum to be refused")
+	}
+	if err := (&CreateAPIKey{ServiceAccountID: "1"}).Validate(); err == nil {
+		t.Fatal("Expected a key without permissions to be refused")
+	}
+}
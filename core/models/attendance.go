@@ -0,0 +1,364 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type ClockEventType string
+
+const (
+	ClockEventTypeIn  ClockEventType = "clock_in"
+	ClockEventTypeOut ClockEventType = "clock_out"
+)
+
+type ClockSource string
+
+const (
+	ClockSourceWeb   ClockSource = "web"
+	ClockSourceKiosk ClockSource = "kiosk"
+	ClockSourceAPI   ClockSource = "api"
+)
+
+// IsValid reports whether the source is one of the known clock sources
+func (s ClockSource) IsValid() bool {
+	switch s {
+	case ClockSourceWeb, ClockSourceKiosk, ClockSourceAPI:
+		return true
+	}
+	return false
+}
+
+// AttendanceEvent is a single clock-in or clock-out of an employee
+type AttendanceEvent struct {
+	ID         string         `json:"id"`
+	EmployeeID string         `json:"employee_id"`
+	Type       ClockEventType `json:"type"`
+	Source     ClockSource    `json:"source"`
+	Timestamp  time.Time      `json:"timestamp"`
+	Note       string         `json:"note"`
+	// Correction data, filled when a manager fixes the event
+	Corrected         bool       `json:"corrected"`
+	OriginalTimestamp *time.Time `json:"original_timestamp"`
+	CorrectionReason  string     `json:"correction_reason"`
+	CorrectedByID     string     `json:"corrected_by_id"`
+}
+
+var attendanceEventSearchFields = SearchFields{
+	"ID":            {Column: "id", Filterable: true, Sortable: true},
+	"EmployeeID":    {Column: "employee_id", Filterable: true, Sortable: true},
+	"Type":          {Column: "type", Filterable: true, Sortable: true},
+	"Source":        {Column: "source", Filterable: true, Sortable: true},
+	"Timestamp":     {Column: "timestamp", Filterable: true, Sortable: true},
+	"Corrected":     {Column: "corrected", Filterable: true},
+	"CorrectedByID": {Column: "corrected_by_id", Filterable: true, Nullable: true},
+}
+
+// SearchFields lists the fields attendance events can be filtered and sorted on
+func (AttendanceEvent) SearchFields() SearchFields {
+	return attendanceEventSearchFields
+}
+
+// RegisterClockEvent is the request to clock an employee in or out.
+// EmployeeID may be empty when the employee clocks itself through the web.
+// Timestamp is only honoured for kiosk and api sources, which may deliver events recorded offline.
+type RegisterClockEvent struct {
+	EmployeeID string         `json:"employee_id"`
+	Type       ClockEventType `json:"-"`
+	Source     ClockSource    `json:"source"`
+	Timestamp  *time.Time     `json:"timestamp"`
+	Note       string         `json:"note"`
+	// Trusted is set for kiosks and integrations authenticated with an API key, only they may
+	// send another source or their own timestamp
+	Trusted bool `json:"-"`
+}
+
+// CorrectAttendanceEvent is the request of a manager to fix a wrong event
+type CorrectAttendanceEvent struct {
+	EventID       string         `json:"event_id"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Type          ClockEventType `json:"type"`
+	Reason        string         `json:"reason"`
+	CorrectedByID string         `json:"-"`
+}
+
+type TimesheetQuery struct {
+	EmployeeID string    `json:"employee_id"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	ScheduleID string    `json:"schedule_id"`
+}
+
+// MaxTimesheetDays limits the range of a single timesheet query
+const MaxTimesheetDays = 93
+
+func (r *RegisterClockEvent) Validate() *SystemError {
+	if r.Type != ClockEventTypeIn && r.Type != ClockEventTypeOut {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid clock event type", struct{}{})
+	}
+	if !r.Source.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid clock source", struct{}{})
+	}
+	if !r.Trusted && (r.Source != ClockSourceWeb || r.Timestamp != nil) {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "only kiosks and integrations can set the source or timestamp", struct{}{})
+	}
+	return nil
+}
+
+func (r *CorrectAttendanceEvent) Validate() *SystemError {
+	if r.EventID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "event id is required", struct{}{})
+	}
+	if r.Timestamp.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "timestamp is required", struct{}{})
+	}
+	if r.Type != "" && r.Type != ClockEventTypeIn && r.Type != ClockEventTypeOut {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid clock event type", struct{}{})
+	}
+	if r.Reason == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "reason is required", struct{}{})
+	}
+	if r.CorrectedByID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "corrector is required", struct{}{})
+	}
+	return nil
+}
+
+func (q *TimesheetQuery) Validate() *SystemError {
+	if q.From.IsZero() || q.To.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "from and to are required", struct{}{})
+	}
+	if q.To.Before(q.From) {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "to cannot be before from", struct{}{})
+	}
+	if q.To.Sub(q.From) > MaxTimesheetDays*24*time.Hour {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, fmt.Sprintf("range cannot exceed %d days", MaxTimesheetDays), struct{}{})
+	}
+	return nil
+}
+
+// WorkSchedule defines the expected working hours used to evaluate attendance
+type WorkSchedule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Start and end of the working day in HH:MM format, in the schedule timezone
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	// Unpaid break deducted when the employee does not clock the break
+	BreakMinutes int `json:"break_minutes"`
+	// Tolerance before a clock-in is considered late
+	GraceMinutes int `json:"grace_minutes"`
+	// Working days of the week
+	WorkDays []time.Weekday `json:"work_days"`
+	// IANA timezone name, UTC when empty
+	Timezone  string `json:"timezone"`
+	IsDefault bool   `json:"is_default"`
+}
+
+var workScheduleSearchFields = SearchFields{
+	"ID":        {Column: "id", Filterable: true, Sortable: true},
+	"Name":      {Column: "name", Filterable: true, Sortable: true},
+	"Timezone":  {Column: "timezone", Filterable: true, Sortable: true},
+	"IsDefault": {Column: "is_default", Filterable: true},
+}
+
+// SearchFields lists the fields work schedules can be filtered and sorted on
+func (WorkSchedule) SearchFields() SearchFields {
+	return workScheduleSearchFields
+}
+
+func (s *WorkSchedule) Validate() *SystemError {
+	if s.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	start, err := parseClock(s.StartTime)
+	if err != nil {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "start time must use the HH:MM format", struct{}{})
+	}
+	end, err := parseClock(s.EndTime)
+	if err != nil {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "end time must use the HH:MM format", struct{}{})
+	}
+	if end <= start {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "end time must be after start time", struct{}{})
+	}
+	if s.BreakMinutes < 0 || s.GraceMinutes < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "break and grace minutes cannot be negative", struct{}{})
+	}
+	if s.BreakMinutes >= end-start {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "break cannot be longer than the working day", struct{}{})
+	}
+	for _, day := range s.WorkDays {
+		if day < time.Sunday || day > time.Saturday {
+			return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid work day", struct{}{})
+		}
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid timezone", struct{}{})
+	}
+	return nil
+}
+
+// Location returns the schedule timezone, UTC when it is empty or unknown
+func (s *WorkSchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsWorkDay reports whether the weekday is part of the schedule
+func (s *WorkSchedule) IsWorkDay(day time.Weekday) bool {
+	for _, d := range s.WorkDays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduledMinutes returns the paid minutes expected on a work day
+func (s *WorkSchedule) ScheduledMinutes() int {
+	start, _ := parseClock(s.StartTime)
+	end, _ := parseClock(s.EndTime)
+	return end - start - s.BreakMinutes
+}
+
+// parseClock converts an HH:MM string into minutes from midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// DailyTimesheet is the attendance summary of one employee for one day
+type DailyTimesheet struct {
+	EmployeeID        string            `json:"employee_id"`
+	Date              string            `json:"date"`
+	WorkDay           bool              `json:"work_day"`
+	FirstIn           *time.Time        `json:"first_in"`
+	LastOut           *time.Time        `json:"last_out"`
+	ScheduledMinutes  int               `json:"scheduled_minutes"`
+	WorkedMinutes     int               `json:"worked_minutes"`
+	LateMinutes       int               `json:"late_minutes"`
+	EarlyLeaveMinutes int               `json:"early_leave_minutes"`
+	OvertimeMinutes   int               `json:"overtime_minutes"`
+	Absent            bool              `json:"absent"`
+	Incomplete        bool              `json:"incomplete"`
+	Events            []AttendanceEvent `json:"events"`
+}
+
+// BuildTimesheets aggregates the events of one employee into one timesheet per day between from and to (inclusive).
+// Clock-ins are paired with the following clock-out, and a pair belongs to the day of its clock-in so night shifts are
+// not split. When a day has a single pair longer than half the working span the unpaid break is deducted, clocked
+// breaks are already excluded.
+func (s *WorkSchedule) BuildTimesheets(employeeID string, events []AttendanceEvent, from, to time.Time) []DailyTimesheet {
+	loc := s.Location()
+	start, _ := parseClock(s.StartTime)
+	end, _ := parseClock(s.EndTime)
+
+	sorted := make([]AttendanceEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	type pair struct {
+		in  time.Time
+		out *time.Time
+	}
+	pairsByDay := map[string][]pair{}
+	eventsByDay := map[string][]AttendanceEvent{}
+	var open *time.Time
+	var openDay string
+	for _, event := range sorted {
+		ts := event.Timestamp.In(loc)
+		day := ts.Format(time.DateOnly)
+		eventsByDay[day] = append(eventsByDay[day], event)
+		switch event.Type {
+		case ClockEventTypeIn:
+			if open != nil {
+				pairsByDay[openDay] = append(pairsByDay[openDay], pair{in: *open})
+			}
+			in := ts
+			open = &in
+			openDay = day
+		case ClockEventTypeOut:
+			if open == nil {
+				// A clock-out without clock-in cannot be paired, it only marks the day as incomplete
+				pairsByDay[day] = append(pairsByDay[day], pair{})
+				continue
+			}
+			out := ts
+			pairsByDay[openDay] = append(pairsByDay[openDay], pair{in: *open, out: &out})
+			open = nil
+		}
+	}
+	if open != nil {
+		pairsByDay[openDay] = append(pairsByDay[openDay], pair{in: *open})
+	}
+
+	var timesheets []DailyTimesheet
+	first := time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+	last := time.Date(to.In(loc).Year(), to.In(loc).Month(), to.In(loc).Day(), 0, 0, 0, 0, loc)
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		day := date.Format(time.DateOnly)
+		sheet := DailyTimesheet{
+			EmployeeID: employeeID,
+			Date:       day,
+			WorkDay:    s.IsWorkDay(date.Weekday()),
+			Events:     eventsByDay[day],
+		}
+		if sheet.WorkDay {
+			sheet.ScheduledMinutes = s.ScheduledMinutes()
+		}
+
+		worked := 0
+		completePairs := 0
+		for _, p := range pairsByDay[day] {
+			if p.in.IsZero() || p.out == nil {
+				sheet.Incomplete = true
+			}
+			if !p.in.IsZero() && (sheet.FirstIn == nil || p.in.Before(*sheet.FirstIn)) {
+				in := p.in
+				sheet.FirstIn = &in
+			}
+			if p.out != nil && (sheet.LastOut == nil || p.out.After(*sheet.LastOut)) {
+				out := *p.out
+				sheet.LastOut = &out
+			}
+			if !p.in.IsZero() && p.out != nil {
+				worked += int(p.out.Sub(p.in).Minutes())
+				completePairs++
+			}
+		}
+		if completePairs == 1 && worked > (end-start)/2 && worked > s.BreakMinutes {
+			worked -= s.BreakMinutes
+		}
+		sheet.WorkedMinutes = worked
+
+		if sheet.WorkDay {
+			if sheet.FirstIn == nil {
+				sheet.Absent = true
+			} else {
+				scheduledStart := date.Add(time.Duration(start) * time.Minute)
+				if sheet.FirstIn.After(scheduledStart.Add(time.Duration(s.GraceMinutes) * time.Minute)) {
+					sheet.LateMinutes = int(sheet.FirstIn.Sub(scheduledStart).Minutes())
+				}
+			}
+			if sheet.LastOut != nil {
+				scheduledEnd := date.Add(time.Duration(end) * time.Minute)
+				if sheet.LastOut.Before(scheduledEnd) {
+					sheet.EarlyLeaveMinutes = int(scheduledEnd.Sub(*sheet.LastOut).Minutes())
+				}
+			}
+		}
+		if worked > sheet.ScheduledMinutes {
+			sheet.OvertimeMinutes = worked - sheet.ScheduledMinutes
+		}
+		timesheets = append(timesheets, sheet)
+	}
+	return timesheets
+}
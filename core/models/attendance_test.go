@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTimesheets(t *testing.T) {
+	schedule := WorkSchedule{
+		Name:         "Standard",
+		StartTime:    "09:00",
+		EndTime:      "18:00",
+		BreakMinutes: 60,
+		GraceMinutes: 10,
+		WorkDays:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Expected valid schedule, got %v", err)
+	}
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+	events := []AttendanceEvent{
+		// Monday: late (09:25) and one hour of overtime
+		{Type: ClockEventTypeIn, Timestamp: at(3, 9, 25)},
+		{Type: ClockEventTypeOut, Timestamp: at(3, 19, 25)},
+		// Tuesday: inside the grace period, leaves early, break clocked
+		{Type: ClockEventTypeIn, Timestamp: at(4, 9, 5)},
+		{Type: ClockEventTypeOut, Timestamp: at(4, 13, 0)},
+		{Type: ClockEventTypeIn, Timestamp: at(4, 13, 30)},
+		{Type: ClockEventTypeOut, Timestamp: at(4, 17, 0)},
+		// Wednesday: forgot to clock out
+		{Type: ClockEventTypeIn, Timestamp: at(5, 9, 0)},
+		// Thursday: absent
+		// Saturday: everything is overtime
+		{Type: ClockEventTypeIn, Timestamp: at(8, 10, 0)},
+		{Type: ClockEventTypeOut, Timestamp: at(8, 12, 0)},
+	}
+
+	sheets := schedule.BuildTimesheets("employee", events, at(3, 0, 0), at(8, 0, 0))
+	if len(sheets) != 6 {
+		t.Fatalf("Expected 6 days, got %d", len(sheets))
+	}
+
+	monday := sheets[0]
+	if monday.LateMinutes != 25 || monday.WorkedMinutes != 540 || monday.OvertimeMinutes != 60 || monday.EarlyLeaveMinutes != 0 {
+		t.Fatalf("Unexpected monday timesheet: %+v", monday)
+	}
+
+	tuesday := sheets[1]
+	if tuesday.LateMinutes != 0 || tuesday.WorkedMinutes != 445 || tuesday.EarlyLeaveMinutes != 60 || tuesday.OvertimeMinutes != 0 {
+		t.Fatalf("Unexpected tuesday timesheet: %+v", tuesday)
+	}
+
+	wednesday := sheets[2]
+	if !wednesday.Incomplete || wednesday.WorkedMinutes != 0 || wednesday.Absent {
+		t.Fatalf("Unexpected wednesday timesheet: %+v", wednesday)
+	}
+
+	if !sheets[3].Absent {
+		t.Fatalf("Expected thursday to be absent: %+v", sheets[3])
+	}
+
+	saturday := sheets[5]
+	if saturday.WorkDay || saturday.Absent || saturday.OvertimeMinutes != 120 {
+		t.Fatalf("Unexpected saturday timesheet: %+v", saturday)
+	}
+}
+
+func TestBuildTimesheetsNightShift(t *testing.T) {
+	schedule := WorkSchedule{
+		Name:      "Night",
+		StartTime: "00:00",
+		EndTime:   "08:00",
+		WorkDays:  []time.Weekday{time.Monday, time.Tuesday},
+	}
+	events := []AttendanceEvent{
+		{Type: ClockEventTypeIn, Timestamp: time.Date(2025, time.March, 3, 22, 0, 0, 0, time.UTC)},
+		{Type: ClockEventTypeOut, Timestamp: time.Date(2025, time.March, 4, 6, 0, 0, 0, time.UTC)},
+	}
+
+	sheets := schedule.BuildTimesheets("employee", events, events[0].Timestamp, events[0].Timestamp)
+	if len(sheets) != 1 {
+		t.Fatalf("Expected 1 day, got %d", len(sheets))
+	}
+	if sheets[0].WorkedMinutes != 480 || sheets[0].Incomplete {
+		t.Fatalf("Expected the shift to be attributed to the clock-in day: %+v", sheets[0])
+	}
+}
+
+func TestRegisterClockEventOnlyTrustsDevicesWithTheTime(t *testing.T) {
+	at := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	for _, request := range []RegisterClockEvent{
+		{Type: ClockEventTypeIn, Source: ClockSourceKiosk},
+		{Type: ClockEventTypeIn, Source: ClockSourceWeb, Timestamp: &at},
+	} {
+		if err := request.Validate(); err == nil {
+			t.Errorf("Expected %+v to be refused from a user session", request)
+		}
+		request.Trusted = true
+		if err := request.Validate(); err != nil {
+			t.Errorf("Expected %+v to be accepted from a device, got %v", request, err.Message)
+		}
+	}
+	web := RegisterClockEvent{Type: ClockEventTypeOut, Source: ClockSourceWeb}
+	if err := web.Validate(); err != nil {
+		t.Errorf("Expected a web event to be accepted, got %v", err.Message)
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionAccountLocked   AuditAction = "account_locked"
+	AuditActionAccountUnlocked AuditAction = "account_unlocked"
+	AuditActionMFAEnabled      AuditAction = "mfa_enabled"
+	AuditActionMFADisabled     AuditAction = "mfa_disabled"
+	AuditActionMFAReset        AuditAction = "mfa_reset"
+	AuditActionPasswordReset   AuditAction = "password_reset"
+	AuditActionUserInvited     AuditAction = "user_invited"
+	AuditActionAPIKeyCreated   AuditAction = "api_key_created"
+	AuditActionAPIKeyRevoked   AuditAction = "api_key_revoked"
+	AuditActionUserProvisioned AuditAction = "user_provisioned"
+	// AuditActionBankAccountChanged records a change of the account an employee is paid into
+	AuditActionBankAccountChanged AuditAction = "bank_account_changed"
+)
+
+// AuditEntry records a security relevant event
+type AuditEntry struct {
+	ID     string      `json:"id"`
+	Action AuditAction `json:"action"`
+	// Username performing the action, empty when the system acted on its own
+	Actor string `json:"actor"`
+	// Username affected by the action
+	Subject   string    `json:"subject"`
+	IP        string    `json:"ip"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,63 @@
 package models
 
+// Department model
 type Department struct {
-	ID   string
-	Name string
+	// ID of the department
+	ID string `json:"id"`
+	// Name of the department
+	Name string `json:"name"`
+	// Description of the department
+	Description string `json:"description"`
+	// ID of the parent department, empty for top level departments
+	ParentID string `json:"parent_id"`
+	// ID of the employee heading the department, empty when the head is vacant
+	HeadEmployeeID string `json:"head_employee_id"`
+}
+
+var departmentSearchFields = SearchFields{
+	"ID":             {Column: "id", Filterable: true, Sortable: true},
+	"Name":           {Column: "name", Filterable: true, Sortable: true},
+	"ParentID":       {Column: "parent_id", Filterable: true, Nullable: true},
+	"HeadEmployeeID": {Column: "head_employee_id", Filterable: true, Nullable: true},
+}
+
+// SearchFields lists the fields departments can be filtered and sorted on
+func (Department) SearchFields() SearchFields {
+	return departmentSearchFields
+}
+
+type CreateDepartment struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	ParentID       string `json:"parent_id"`
+	HeadEmployeeID string `json:"head_employee_id"`
+}
+
+func (cd *CreateDepartment) ToDepartment() *Department {
+	return &Department{
+		Name:           cd.Name,
+		Description:    cd.Description,
+		ParentID:       cd.ParentID,
+		HeadEmployeeID: cd.HeadEmployeeID,
+	}
+}
+
+func (cd *CreateDepartment) Validate() *SystemError {
+	if cd.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	return nil
+}
+
+func (d *Department) Validate() *SystemError {
+	if d.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	if d.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	if d.ParentID != "" && d.ParentID == d.ID {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a department cannot be its own parent", struct{}{})
+	}
+	return nil
 }
@@ -0,0 +1,251 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type EmploymentStatus string
+
+const (
+	EmploymentStatusActive     EmploymentStatus = "active"
+	EmploymentStatusOnLeave    EmploymentStatus = "on_leave"
+	EmploymentStatusSuspended  EmploymentStatus = "suspended"
+	EmploymentStatusTerminated EmploymentStatus = "terminated"
+)
+
+// IsValid reports whether the status is one of the known employment statuses
+func (s EmploymentStatus) IsValid() bool {
+	switch s {
+	case EmploymentStatusActive, EmploymentStatusOnLeave, EmploymentStatusSuspended, EmploymentStatusTerminated:
+		return true
+	}
+	return false
+}
+
+// Employee model
+type Employee struct {
+	// ID of the employee
+	ID string `json:"id"`
+	// Internal employee number, unique across the company
+	EmployeeNumber string `json:"employee_number"`
+	// First name of the employee
+	FirstName string `json:"first_name"`
+	// Last name of the employee
+	LastName string `json:"last_name"`
+	// National identification document (cedula, DNI, SSN...)
+	NationalID string `json:"national_id"`
+	// Contact data
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+	// Employment data
+	HireDate          time.Time        `json:"hire_date"`
+	TerminationDate   *time.Time       `json:"termination_date"`
+	TerminationReason string           `json:"termination_reason"`
+	Status            EmploymentStatus `json:"status"`
+	// ID of the system user linked to the employee, empty when the employee has no account
+	UserID string `json:"user_id"`
+	// ID of the employee manager, empty for top level employees
+	ManagerID string `json:"manager_id"`
+	// ID of the department
+	DepartmentID string `json:"department_id"`
+	// ID of the position
+	PositionID string `json:"position_id"`
+	// Monthly salary in the currency of the position, inside its salary band
+	Salary float64 `json:"salary"`
+	// Bank account where the salary is transferred
+	BankName    string `json:"bank_name"`
+	BankAccount string `json:"bank_account"`
+}
+
+var employeeSearchFields = SearchFields{
+	"ID":              {Column: "id", Filterable: true, Sortable: true},
+	"EmployeeNumber":  {Column: "employee_number", Filterable: true, Sortable: true},
+	"FirstName":       {Column: "first_name", Filterable: true, Sortable: true},
+	"LastName":        {Column: "last_name", Filterable: true, Sortable: true},
+	"NationalID":      {Column: "national_id", Filterable: true},
+	"Email":           {Column: "email", Filterable: true, Sortable: true},
+	"Phone":           {Column: "phone", Filterable: true},
+	"HireDate":        {Column: "hire_date", Filterable: true, Sortable: true},
+	"TerminationDate": {Column: "termination_date", Filterable: true, Sortable: true},
+	"Status":          {Column: "status", Filterable: true, Sortable: true},
+	"UserID":          {Column: "user_id", Filterable: true, Nullable: true},
+	"ManagerID":       {Column: "manager_id", Filterable: true, Nullable: true},
+	"DepartmentID":    {Column: "department_id", Filterable: true, Sortable: true, Nullable: true},
+	"PositionID":      {Column: "position_id", Filterable: true, Sortable: true, Nullable: true},
+}
+
+// SearchFields lists the fields employees can be filtered and sorted on
+func (Employee) SearchFields() SearchFields {
+	return employeeSearchFields
+}
+
+type CreateEmployee struct {
+	EmployeeNumber string    `json:"employee_number"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	NationalID     string    `json:"national_id"`
+	Email          string    `json:"email"`
+	Phone          string    `json:"phone"`
+	Address        string    `json:"address"`
+	HireDate       time.Time `json:"hire_date"`
+	UserID         string    `json:"user_id"`
+	ManagerID      string    `json:"manager_id"`
+	DepartmentID   string    `json:"department_id"`
+	PositionID     string    `json:"position_id"`
+	Salary         float64   `json:"salary"`
+	BankName       string    `json:"bank_name"`
+	BankAccount    string    `json:"bank_account"`
+}
+
+type ModifyEmployee struct {
+	ID             string           `json:"id"`
+	EmployeeNumber string           `json:"employee_number"`
+	FirstName      string           `json:"first_name"`
+	LastName       string           `json:"last_name"`
+	NationalID     string           `json:"national_id"`
+	Email          string           `json:"email"`
+	Phone          string           `json:"phone"`
+	Address        string           `json:"address"`
+	HireDate       time.Time        `json:"hire_date"`
+	Status         EmploymentStatus `json:"status"`
+	UserID         string           `json:"user_id"`
+	ManagerID      string           `json:"manager_id"`
+	DepartmentID   string           `json:"department_id"`
+	PositionID     string           `json:"position_id"`
+	Salary         float64          `json:"salary"`
+	BankName       string           `json:"bank_name"`
+	BankAccount    string           `json:"bank_account"`
+	// Caller of the modification, changing the bank account requires manage_payroll and is audited
+	Actor            string       `json:"-"`
+	IP               string       `json:"-"`
+	ActorPermissions []Permission `json:"-"`
+}
+
+type TerminateEmployee struct {
+	ID              string    `json:"id"`
+	TerminationDate time.Time `json:"termination_date"`
+	Reason          string    `json:"reason"`
+}
+
+func (ce *CreateEmployee) ToEmployee() *Employee {
+	return &Employee{
+		EmployeeNumber: ce.EmployeeNumber,
+		FirstName:      ce.FirstName,
+		LastName:       ce.LastName,
+		NationalID:     ce.NationalID,
+		Email:          ce.Email,
+		Phone:          ce.Phone,
+		Address:        ce.Address,
+		HireDate:       ce.HireDate,
+		Status:         EmploymentStatusActive,
+		UserID:         ce.UserID,
+		ManagerID:      ce.ManagerID,
+		DepartmentID:   ce.DepartmentID,
+		PositionID:     ce.PositionID,
+		Salary:         ce.Salary,
+		BankName:       ce.BankName,
+		BankAccount:    ce.BankAccount,
+	}
+}
+
+// ChangesBankAccount reports whether the modification changes the account the employee is paid into
+func (me *ModifyEmployee) ChangesBankAccount(employee *Employee) bool {
+	return me.BankName != employee.BankName || me.BankAccount != employee.BankAccount
+}
+
+// ApplyTo copies the modifiable fields over an existing employee, keeping the termination data untouched
+func (me *ModifyEmployee) ApplyTo(employee *Employee) *Employee {
+	employee.EmployeeNumber = me.EmployeeNumber
+	employee.FirstName = me.FirstName
+	employee.LastName = me.LastName
+	employee.NationalID = me.NationalID
+	employee.Email = me.Email
+	employee.Phone = me.Phone
+	employee.Address = me.Address
+	employee.HireDate = me.HireDate
+	employee.Status = me.Status
+	employee.UserID = me.UserID
+	employee.ManagerID = me.ManagerID
+	employee.DepartmentID = me.DepartmentID
+	employee.PositionID = me.PositionID
+	employee.Salary = me.Salary
+	employee.BankName = me.BankName
+	employee.BankAccount = me.BankAccount
+	return employee
+}
+
+func (ce *CreateEmployee) Validate() *SystemError {
+	if ce.EmployeeNumber == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "employee number is required", struct{}{})
+	}
+	if ce.FirstName == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "first name is required", struct{}{})
+	}
+	if ce.LastName == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "last name is required", struct{}{})
+	}
+	if ce.NationalID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "national id is required", struct{}{})
+	}
+	if ce.HireDate.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "hire date is required", struct{}{})
+	}
+	if ce.Salary < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "salary cannot be negative", struct{}{})
+	}
+	return nil
+}
+
+func (me *ModifyEmployee) Validate() *SystemError {
+	if me.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	if me.EmployeeNumber == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "employee number is required", struct{}{})
+	}
+	if me.FirstName == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "first name is required", struct{}{})
+	}
+	if me.LastName == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "last name is required", struct{}{})
+	}
+	if me.NationalID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "national id is required", struct{}{})
+	}
+	if me.HireDate.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "hire date is required", struct{}{})
+	}
+	if me.Salary < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "salary cannot be negative", struct{}{})
+	}
+	if !me.Status.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid employment status", struct{}{})
+	}
+	if me.Status == EmploymentStatusTerminated {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "use the terminate operation to terminate an employee", struct{}{})
+	}
+	if me.ManagerID != "" && me.ManagerID == me.ID {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "an employee cannot be its own manager", struct{}{})
+	}
+	return nil
+}
+
+func (te *TerminateEmployee) Validate() *SystemError {
+	if te.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	if te.TerminationDate.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "termination date is required", struct{}{})
+	}
+	if te.Reason == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "reason is required", struct{}{})
+	}
+	return nil
+}
+
+// FullName returns the first and last name of the employee
+func (e *Employee) FullName() string {
+	return strings.TrimSpace(e.FirstName + " " + e.LastName)
+}
@@ -1,17 +1,70 @@
 package models
 
-import "reflect"
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// FilterOperator compares the field of a Filter with its value, the empty operator is FilterOperatorEq
+type FilterOperator string
+
+const (
+	FilterOperatorEq    FilterOperator = "eq"
+	FilterOperatorNeq   FilterOperator = "neq"
+	FilterOperatorLt    FilterOperator = "lt"
+	FilterOperatorLte   FilterOperator = "lte"
+	FilterOperatorGt    FilterOperator = "gt"
+	FilterOperatorGte   FilterOperator = "gte"
+	FilterOperatorIn    FilterOperator = "in"
+	FilterOperatorNotIn FilterOperator = "not_in"
+	// Like and ILike take a SQL pattern, "Ana%" finds the names starting with Ana
+	FilterOperatorLike  FilterOperator = "like"
+	FilterOperatorILike FilterOperator = "ilike"
+	// Between takes a list with the lower and upper bounds, both included
+	FilterOperatorBetween FilterOperator = "between"
+	// IsNull takes true for the empty values and false for the rest, only on nullable fields
+	FilterOperatorIsNull FilterOperator = "is_null"
+)
+
+// FilterLogic joins the conditions of a FilterGroup
+type FilterLogic string
+
+const (
+	FilterLogicAnd FilterLogic = "and"
+	FilterLogicOr  FilterLogic = "or"
+)
+
+const (
+	// MaxFilterValues bounds the lists of in and not_in
+	MaxFilterValues = 100
+	// MaxFilterDepth bounds the nesting of filter groups
+	MaxFilterDepth = 4
+	// MaxFilterConditions bounds the conditions of a query, groups included
+	MaxFilterConditions = 50
+)
 
 type Filter struct {
-	Key   string `json:"key"`
-	Value any    `json:"value"`
+	Key      string         `json:"key"`
+	Operator FilterOperator `json:"operator,omitempty"`
+	Value    any            `json:"value"`
+}
+
+// Op returns the operator of the filter, eq when none is set
+func (f *Filter) Op() FilterOperator {
+	if f.Operator == "" {
+		return FilterOperatorEq
+	}
+	return f.Operator
 }
 
 type Filters []Filter
 
+// Validate checks the filters against the SearchFields of structure, only its filterable fields are accepted
 func (f Filters) Validate(structure any) *SystemError {
 	val := reflect.ValueOf(structure)
 	reqType := val.Type()
+	fields := SearchFieldsOf(structure)
 
 	for _, filter := range f {
 		if filter.Key == "" {
@@ -34,8 +87,9 @@ func (f Filters) Validate(structure any) *SystemError {
 			}
 		}
 
-		field, ok := reqType.FieldByName(filter.Key)
-		if !ok {
+		name, spec, ok := fields.Lookup(filter.Key)
+		field, found := reqType.FieldByName(name)
+		if !ok || !found {
 			return &SystemError{
 				Code:    SystemErrorCodeValidation,
 				Type:    SystemErrorTypeValidation,
@@ -45,21 +99,76 @@ func (f Filters) Validate(structure any) *SystemError {
 			}
 		}
 
-		filterVal := reflect.ValueOf(filter.Value)
-		if filterVal.Kind() != field.Type.Kind() {
-			return &SystemError{
-				Code:    SystemErrorCodeValidation,
-				Type:    SystemErrorTypeValidation,
-				Level:   SystemErrorLevelError,
-				Message: "field type mismatch: " + filter.Key,
-				Details: struct{}{},
-			}
+		if !spec.Filterable {
+			return invalidFilter("field cannot be filtered: " + filter.Key)
+		}
+
+		if err := filter.validateValue(field.Type, spec.Nullable); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// validateValue checks the operator is meaningful for the type of the field and the value fits both
+func (f *Filter) validateValue(fieldType reflect.Type, nullable bool) *SystemError {
+	class := filterClassOf(fieldType)
+	value := reflect.ValueOf(f.Value)
+
+	switch f.Op() {
+	case FilterOperatorEq, FilterOperatorNeq:
+		if !class.accepts(fieldType, f.Value) {
+			return invalidFilter("field type mismatch: " + f.Key)
+		}
+	case FilterOperatorLt, FilterOperatorLte, FilterOperatorGt, FilterOperatorGte:
+		if !class.ordered() {
+			return invalidFilter("operator " + string(f.Op()) + " cannot compare " + f.Key)
+		}
+		if !class.accepts(fieldType, f.Value) {
+			return invalidFilter("field type mismatch: " + f.Key)
+		}
+	case FilterOperatorBetween:
+		if !class.ordered() {
+			return invalidFilter("operator between cannot compare " + f.Key)
+		}
+		if !isList(value) || value.Len() != 2 {
+			return invalidFilter("between takes the lower and upper bounds of " + f.Key)
+		}
+		for i := 0; i < 2; i++ {
+			if !class.accepts(fieldType, value.Index(i).Interface()) {
+				return invalidFilter("field type mismatch: " + f.Key)
+			}
+		}
+	case FilterOperatorIn, FilterOperatorNotIn:
+		if !isList(value) || value.Len() == 0 || value.Len() > MaxFilterValues {
+			return invalidFilter(string(f.Op()) + " takes a list of 1 to " + strconv.Itoa(MaxFilterValues) + " values for " + f.Key)
+		}
+		for i := 0; i < value.Len(); i++ {
+			if !class.accepts(fieldType, value.Index(i).Interface()) {
+				return invalidFilter("field type mismatch: " + f.Key)
+			}
+		}
+	case FilterOperatorLike, FilterOperatorILike:
+		if class != filterClassString {
+			return invalidFilter("operator " + string(f.Op()) + " only applies to text, not to " + f.Key)
+		}
+		if value.Kind() != reflect.String {
+			return invalidFilter("field type mismatch: " + f.Key)
+		}
+	case FilterOperatorIsNull:
+		if !nullable && !isNullable(fieldType) {
+			return invalidFilter(f.Key + " is never empty")
+		}
+		if value.Kind() != reflect.Bool {
+			return invalidFilter("is_null takes true or false for " + f.Key)
+		}
+	default:
+		return invalidFilter("unknown operator " + string(f.Operator))
+	}
+	return nil
+}
+
 func (f *Filter) Build() (Filter, *SystemError) {
 	if f.Key == "" {
 		return Filter{}, &SystemError{
@@ -82,9 +191,142 @@ func (f *Filter) Build() (Filter, *SystemError) {
 	return *f, nil
 }
 
+// FilterGroup joins its filters and nested groups with Logic, "and" when empty
+//
+//	// active users named Ana or hired in 2026
+//	models.FilterGroup{Logic: models.FilterLogicOr, Filters: models.Filters{
+//		{Key: "Name", Operator: models.FilterOperatorILike, Value: "ana%"},
+//		{Key: "HireDate", Operator: models.FilterOperatorBetween, Value: []any{"2026-01-01", "2026-12-31"}},
+//	}}
+type FilterGroup struct {
+	Logic   FilterLogic   `json:"logic,omitempty"`
+	Filters Filters       `json:"filters"`
+	Groups  []FilterGroup `json:"groups,omitempty"`
+}
+
+// Or reports whether the conditions of the group are alternatives
+func (g *FilterGroup) Or() bool {
+	return g.Logic == FilterLogicOr
+}
+
+func (g *FilterGroup) validate(structure any, depth int, conditions *int) *SystemError {
+	if depth > MaxFilterDepth {
+		return invalidFilter("filter groups nest at most " + strconv.Itoa(MaxFilterDepth) + " levels")
+	}
+	if g.Logic != "" && g.Logic != FilterLogicAnd && g.Logic != FilterLogicOr {
+		return invalidFilter("unknown logic " + string(g.Logic) + ", use and or or")
+	}
+	if len(g.Filters) == 0 && len(g.Groups) == 0 {
+		return invalidFilter("filter group is empty")
+	}
+	*conditions += len(g.Filters)
+	if *conditions > MaxFilterConditions {
+		return invalidFilter("a query takes at most " + strconv.Itoa(MaxFilterConditions) + " filters")
+	}
+	if err := g.Filters.Validate(structure); err != nil {
+		return err
+	}
+	for i := range g.Groups {
+		if err := g.Groups[i].validate(structure, depth+1, conditions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortDirection orders a sorted field, ascending when empty
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// SortNulls places the empty values of a sorted field, where the database puts them when empty
+type SortNulls string
+
+const (
+	SortNullsFirst SortNulls = "first"
+	SortNullsLast  SortNulls = "last"
+)
+
+// MaxSortFields bounds the fields a query sorts on
+const MaxSortFields = 5
+
+// Sort orders the rows of a query by one of the sortable SearchFields of the entity
+type Sort struct {
+	Field     string        `json:"field"`
+	Direction SortDirection `json:"direction,omitempty"`
+	Nulls     SortNulls     `json:"nulls,omitempty"`
+}
+
+// Desc reports whether the field is sorted in descending order
+func (s *Sort) Desc() bool {
+	return s.Direction == SortDesc
+}
+
+// SortOrder is the sort of a query, rows tied on every field are ordered by ID so pages never overlap
+type SortOrder []Sort
+
+// Validate checks the sort against the SearchFields of structure, only its sortable fields are accepted
+func (so SortOrder) Validate(structure any) *SystemError {
+	if len(so) > MaxSortFields {
+		return invalidFilter("a query sorts on at most " + strconv.Itoa(MaxSortFields) + " fields")
+	}
+	fields := SearchFieldsOf(structure)
+	seen := map[string]bool{}
+	for _, sort := range so {
+		name, spec, ok := fields.Lookup(sort.Field)
+		if !ok || !spec.Sortable {
+			return invalidFilter("field cannot be sorted: " + sort.Field)
+		}
+		if seen[name] {
+			return invalidFilter("field sorted twice: " + sort.Field)
+		}
+		seen[name] = true
+		if sort.Direction != "" && sort.Direction != SortAsc && sort.Direction != SortDesc {
+			return invalidFilter("unknown direction " + string(sort.Direction) + ", use asc or desc")
+		}
+		if sort.Nulls != "" && sort.Nulls != SortNullsFirst && sort.Nulls != SortNullsLast {
+			return invalidFilter("unknown nulls order " + string(sort.Nulls) + ", use first or last")
+		}
+	}
+	return nil
+}
+
+// PaginationMode selects how a query pages through its rows, offset when empty
+type PaginationMode string
+
+const (
+	// PaginationModeOffset skips the rows of the previous pages, its cost grows with the page
+	PaginationModeOffset PaginationMode = "offset"
+	// PaginationModeCursor continues from the row of a cursor, every page costs the same
+	PaginationModeCursor PaginationMode = "cursor"
+)
+
+// CountMode selects how the total of a query is computed, exact when empty
+type CountMode string
+
+const (
+	// CountExact counts the matching rows
+	CountExact CountMode = "exact"
+	// CountEstimate takes the estimate of the query planner, cheap but approximate
+	CountEstimate CountMode = "estimate"
+	// CountNone skips the total, TotalRows and TotalPages are -1
+	CountNone CountMode = "none"
+)
+
+// MaxCursorLength bounds the cursors a query takes
+const MaxCursorLength = 1024
+
+// Pagination selects a page of a query. In cursor mode Page is ignored, the first page
+// has no Cursor and the next ones take the NextCursor or PrevCursor of the page before.
 type Pagination struct {
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
+	Page   int            `json:"page"`
+	Limit  int            `json:"limit"`
+	Mode   PaginationMode `json:"mode,omitempty"`
+	Cursor string         `json:"cursor,omitempty"`
+	Count  CountMode      `json:"count,omitempty"`
 }
 
 func (p *Pagination) GetOffset() int {
@@ -101,20 +343,189 @@ func (p *Pagination) GetLimit() int {
 	return p.Limit
 }
 
+// UsesCursor reports whether the query pages with cursors instead of offsets
+func (p *Pagination) UsesCursor() bool {
+	return p.Mode == PaginationModeCursor || p.Cursor != ""
+}
+
+// CountMode returns how the total is computed, exact when none is set
+func (p *Pagination) CountMode() CountMode {
+	if p.Count == "" {
+		return CountExact
+	}
+	return p.Count
+}
+
+func (p *Pagination) Validate() *SystemError {
+	switch p.Mode {
+	case "", PaginationModeOffset, PaginationModeCursor:
+	default:
+		return invalidFilter("unknown pagination mode " + string(p.Mode) + ", use offset or cursor")
+	}
+	if p.Mode == PaginationModeOffset && p.Cursor != "" {
+		return invalidFilter("a cursor needs the cursor pagination mode")
+	}
+	if len(p.Cursor) > MaxCursorLength {
+		return invalidFilter("invalid cursor")
+	}
+	switch p.Count {
+	case "", CountExact, CountEstimate, CountNone:
+	default:
+		return invalidFilter("unknown count " + string(p.Count) + ", use exact, estimate or none")
+	}
+	return nil
+}
+
+// SearchQuery matches the rows meeting every filter and every group, in the order of Sort
 type SearchQuery struct {
-	Filters    Filters    `json:"filters"`
-	Pagination Pagination `json:"pagination"`
+	Filters    Filters       `json:"filters"`
+	Groups     []FilterGroup `json:"groups,omitempty"`
+	Sort       SortOrder     `json:"sort,omitempty"`
+	Pagination Pagination    `json:"pagination"`
 }
 
 func (sq *SearchQuery) Validate(structure any) *SystemError {
+	conditions := len(sq.Filters)
+	if conditions > MaxFilterConditions {
+		return invalidFilter("a query takes at most " + strconv.Itoa(MaxFilterConditions) + " filters")
+	}
 	if err := sq.Filters.Validate(structure); err != nil {
 		return err
 	}
+	for i := range sq.Groups {
+		if err := sq.Groups[i].validate(structure, 1, &conditions); err != nil {
+			return err
+		}
+	}
+	if err := sq.Sort.Validate(structure); err != nil {
+		return err
+	}
+	if err := sq.Pagination.Validate(); err != nil {
+		return err
+	}
+	if sq.Pagination.UsesCursor() {
+		return sq.validateCursorSort(structure)
+	}
 	return nil
 }
 
+// validateCursorSort refuses sorting on nullable fields with cursors, a cursor continues
+// after the values of a row and an empty value cannot be compared
+func (sq *SearchQuery) validateCursorSort(structure any) *SystemError {
+	reqType := reflect.TypeOf(structure)
+	fields := SearchFieldsOf(structure)
+	for _, sort := range sq.Sort {
+		name, spec, _ := fields.Lookup(sort.Field)
+		field, found := reqType.FieldByName(name)
+		if spec.Nullable || (found && isNullable(field.Type)) {
+			return invalidFilter("cursor pagination cannot sort on " + sort.Field + ", it can be empty")
+		}
+	}
+	return nil
+}
+
+// PaginatedResponse is a page of a query. TotalRows and TotalPages are -1 when the query skips
+// the count, and approximate when TotalEstimated is set. In cursor mode NextCursor and PrevCursor
+// continue the listing, each one empty when there are no rows in its direction.
 type PaginatedResponse[T any] struct {
-	TotalRows  int64 `json:"total_rows"`
-	TotalPages int   `json:"total_pages"`
-	Rows       []T   `json:"rows"`
+	TotalRows      int64  `json:"total_rows"`
+	TotalPages     int    `json:"total_pages"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+	PrevCursor     string `json:"prev_cursor,omitempty"`
+	Rows           []T    `json:"rows"`
+}
+
+// filterClass groups the field types by the operators and values they accept
+type filterClass int
+
+const (
+	filterClassOther filterClass = iota
+	filterClassString
+	filterClassNumber
+	filterClassBool
+	filterClassTime
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func filterClassOf(fieldType reflect.Type) filterClass {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == timeType {
+		return filterClassTime
+	}
+	switch {
+	case fieldType.Kind() == reflect.String:
+		return filterClassString
+	case isNumber(fieldType.Kind()):
+		return filterClassNumber
+	case fieldType.Kind() == reflect.Bool:
+		return filterClassBool
+	}
+	return filterClassOther
+}
+
+func (c filterClass) ordered() bool {
+	return c == filterClassString || c == filterClassNumber || c == filterClassTime
+}
+
+// accepts reports whether value can be compared with the field, numbers come as float64
+// from JSON and times as RFC 3339 timestamps or dates
+func (c filterClass) accepts(fieldType reflect.Type, value any) bool {
+	if value == nil {
+		return false
+	}
+	kind := reflect.ValueOf(value).Kind()
+	switch c {
+	case filterClassString:
+		return kind == reflect.String
+	case filterClassNumber:
+		return isNumber(kind)
+	case filterClassBool:
+		return kind == reflect.Bool
+	case filterClassTime:
+		switch v := value.(type) {
+		case time.Time, *time.Time:
+			return true
+		case string:
+			if _, err := time.Parse(time.RFC3339, v); err == nil {
+				return true
+			}
+			_, err := time.Parse(time.DateOnly, v)
+			return err == nil
+		}
+		return false
+	}
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	return kind == fieldType.Kind()
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isList(value reflect.Value) bool {
+	return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+}
+
+func isNullable(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func invalidFilter(message string) *SystemError {
+	return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, message, struct{}{})
 }
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+type filterSubject struct {
+	Name      string
+	Age       int
+	Active    bool
+	HireDate  time.Time
+	LockedAt  *time.Time
+	Roles     []string
+	UserType  UserType
+	Reference *string
+	ManagerID string
+	Secret    string
+}
+
+func (filterSubject) SearchFields() SearchFields {
+	return SearchFields{
+		"Name":      {Column: "name", Filterable: true, Sortable: true},
+		"Age":       {Column: "age", Filterable: true},
+		"Active":    {Column: "active", Filterable: true},
+		"HireDate":  {Column: "hire_date", Filterable: true},
+		"LockedAt":  {Column: "locked_at", Filterable: true},
+		"UserType":  {Column: "user_type", Filterable: true},
+		"Reference": {Column: "reference", Filterable: true},
+		"ManagerID": {Column: "manager_id", Filterable: true, Nullable: true},
+		"Roles":     {Column: "roles", Sortable: true},
+	}
+}
+
+func TestFiltersValidateOperatorsByType(t *testing.T) {
+	valid := Filters{
+		{Key: "Name", Value: "Ana"},
+		{Key: "Name", Operator: FilterOperatorILike, Value: "ana%"},
+		{Key: "Age", Operator: FilterOperatorGte, Value: float64(18)},
+		{Key: "Age", Operator: FilterOperatorIn, Value: []any{float64(20), 30}},
+		{Key: "Active", Operator: FilterOperatorNeq, Value: false},
+		{Key: "HireDate", Operator: FilterOperatorBetween, Value: []any{"2026-01-01", "2026-12-31T23:59:59Z"}},
+		{Key: "LockedAt", Operator: FilterOperatorIsNull, Value: true},
+		{Key: "UserType", Operator: FilterOperatorNotIn, Value: []any{"admin"}},
+		{Key: "ManagerID", Operator: FilterOperatorIsNull, Value: false},
+		{Key: "active", Value: true},
+	}
+	if err := valid.Validate(filterSubject{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err.Message)
+	}
+
+	invalid := map[string]Filter{
+		"like on a number":       {Key: "Age", Operator: FilterOperatorLike, Value: "1%"},
+		"order on a bool":        {Key: "Active", Operator: FilterOperatorGt, Value: true},
+		"text for a number":      {Key: "Age", Operator: FilterOperatorEq, Value: "18"},
+		"invalid date":           {Key: "HireDate", Operator: FilterOperatorGte, Value: "yesterday"},
+		"between with one bound": {Key: "HireDate", Operator: FilterOperatorBetween, Value: []any{"2026-01-01"}},
+		"in without a list":      {Key: "Name", Operator: FilterOperatorIn, Value: "Ana"},
+		"in with an empty list":  {Key: "Name", Operator: FilterOperatorIn, Value: []any{}},
+		"in with mixed types":    {Key: "Name", Operator: FilterOperatorIn, Value: []any{"Ana", float64(1)}},
+		"is_null on a required":  {Key: "Name", Operator: FilterOperatorIsNull, Value: true},
+		"is_null without a bool": {Key: "Reference", Operator: FilterOperatorIsNull, Value: "yes"},
+		"unknown operator":       {Key: "Name", Operator: "regex", Value: ".*"},
+		"unknown field":          {Key: "Salary", Value: float64(1)},
+		"field not on the list":  {Key: "Secret", Value: "x"},
+		"field not filterable":   {Key: "Roles", Value: []any{"admin"}},
+		"column name":            {Key: "hire_date", Value: "2026-01-01"},
+	}
+	for name, filter := range invalid {
+		if err := (Filters{filter}).Validate(filterSubject{}); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+}
+
+func TestFiltersValidateRefusesEntitiesWithoutSearchFields(t *testing.T) {
+	type plain struct{ Name string }
+	if err := (Filters{{Key: "Name", Value: "Ana"}}).Validate(plain{}); err == nil {
+		t.Fatal("Expected the filters of an entity without search fields to be refused")
+	}
+}
+
+func TestSearchQueryValidateGroups(t *testing.T) {
+	query := SearchQuery{
+		Filters: Filters{{Key: "Active", Value: true}},
+		Groups: []FilterGroup{{Logic: FilterLogicOr, Filters: Filters{
+			{Key: "Name", Operator: FilterOperatorILike, Value: "ana%"},
+			{Key: "Age", Operator: FilterOperatorLt, Value: float64(30)},
+		}}},
+	}
+	if err := query.Validate(filterSubject{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err.Message)
+	}
+
+	query.Groups[0].Logic = "xor"
+	if err := query.Validate(filterSubject{}); err == nil {
+		t.Fatal("Expected an unknown logic to be refused")
+	}
+	query.Groups[0].Logic = FilterLogicOr
+	query.Groups[0].Filters[1].Value = "30"
+	if err := query.Validate(filterSubject{}); err == nil {
+		t.Fatal("Expected the filters of a group to be validated")
+	}
+
+	deep := FilterGroup{Filters: Filters{{Key: "Active", Value: true}}}
+	for i := 0; i < MaxFilterDepth; i++ {
+		deep = FilterGroup{Groups: []FilterGroup{deep}}
+	}
+	if err := (&SearchQuery{Groups: []FilterGroup{deep}}).Validate(filterSubject{}); err == nil {
+		t.Fatal("Expected groups nested too deep to be refused")
+	}
+}
+
+func TestSortOrderValidate(t *testing.T) {
+	sort := SortOrder{{Field: "name", Direction: SortDesc, Nulls: SortNullsLast}, {Field: "Roles"}}
+	if err := sort.Validate(filterSubject{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err.Message)
+	}
+
+	invalid := map[string]SortOrder{
+		"field not sortable": {{Field: "Age"}},
+		"unknown field":      {{Field: "Salary"}},
+		"sorted twice":       {{Field: "Name"}, {Field: "name", Direction: SortDesc}},
+		"unknown direction":  {{Field: "Name", Direction: "up"}},
+		"unknown nulls":      {{Field: "Name", Nulls: "middle"}},
+		"too many fields":    make(SortOrder, MaxSortFields+1),
+	}
+	for name, sort := range invalid {
+		if err := sort.Validate(filterSubject{}); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+	if err := (&SearchQuery{Sort: SortOrder{{Field: "Age"}}}).Validate(filterSubject{}); err == nil {
+		t.Fatal("Expected the search query to validate its sort")
+	}
+}
+
+func TestPaginationValidate(t *testing.T) {
+	valid := []Pagination{
+		{Page: 2, Limit: 20},
+		{Mode: PaginationModeCursor, Count: CountNone},
+		{Cursor: "eyJzIjoiIn0", Count: CountEstimate},
+	}
+	for _, pagination := range valid {
+		if err := pagination.Validate(); err != nil {
+			t.Fatalf("Expected no error, got %v", err.Message)
+		}
+	}
+	invalid := map[string]Pagination{
+		"unknown mode":        {Mode: "keyset"},
+		"cursor with offsets": {Mode: PaginationModeOffset, Cursor: "eyJzIjoiIn0"},
+		"unknown count":       {Count: "approximate"},
+		"cursor too long":     {Cursor: string(make([]byte, MaxCursorLength+1))},
+	}
+	for name, pagination := range invalid {
+		if err := pagination.Validate(); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+
+	query := SearchQuery{Sort: SortOrder{{Field: "Name"}}, Pagination: Pagination{Mode: PaginationModeCursor}}
+	if err := query.Validate(filterSubject{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err.Message)
+	}
+	query.Sort = SortOrder{{Field: "Roles"}}
+	if err := query.Validate(filterSubject{}); err == nil {
+		t.Fatal("Expected cursors to refuse sorting on a field that can be empty")
+	}
+	query.Pagination = Pagination{Mode: PaginationModeOffset}
+	if err := query.Validate(filterSubject{}); err != nil {
+		t.Fatalf("Expected offsets to sort on any sortable field, got %v", err.Message)
+	}
+}
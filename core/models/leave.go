@@ -0,0 +1,342 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// LeaveCategory groups leave types by how they are handled by payroll and reports
+type LeaveCategory string
+
+const (
+	LeaveCategoryVacation LeaveCategory = "vacation"
+	LeaveCategorySick     LeaveCategory = "sick"
+	LeaveCategoryUnpaid   LeaveCategory = "unpaid"
+	LeaveCategoryCustom   LeaveCategory = "custom"
+)
+
+// IsValid reports whether the category is one of the known leave categories
+func (c LeaveCategory) IsValid() bool {
+	switch c {
+	case LeaveCategoryVacation, LeaveCategorySick, LeaveCategoryUnpaid, LeaveCategoryCustom:
+		return true
+	}
+	return false
+}
+
+// AccrualFrequency defines how often a leave type grants days
+type AccrualFrequency string
+
+const (
+	// AccrualFrequencyNone does not track a balance, requests are only limited by approval
+	AccrualFrequencyNone AccrualFrequency = "none"
+	// AccrualFrequencyMonthly grants AccrualDays for every month worked in the year
+	AccrualFrequencyMonthly AccrualFrequency = "monthly"
+	// AccrualFrequencyYearly grants AccrualDays at the start of the year, or at hire date
+	AccrualFrequencyYearly AccrualFrequency = "yearly"
+)
+
+// LeaveType is a kind of leave employees can request together with its accrual policy
+type LeaveType struct {
+	ID          string        `json:"id"`
+	Code        string        `json:"code"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Category    LeaveCategory `json:"category"`
+	// Paid leave days are paid by payroll
+	Paid bool `json:"paid"`
+	// Accrual policy
+	AccrualFrequency AccrualFrequency `json:"accrual_frequency"`
+	AccrualDays      float64          `json:"accrual_days"`
+	// Maximum balance an employee can hold in a year, 0 means unlimited
+	MaxBalance float64 `json:"max_balance"`
+	// Inactive types cannot be requested anymore
+	Active bool `json:"active"`
+}
+
+var leaveTypeSearchFields = SearchFields{
+	"ID":               {Column: "id", Filterable: true, Sortable: true},
+	"Code":             {Column: "code", Filterable: true, Sortable: true},
+	"Name":             {Column: "name", Filterable: true, Sortable: true},
+	"Category":         {Column: "category", Filterable: true, Sortable: true},
+	"Paid":             {Column: "paid", Filterable: true},
+	"AccrualFrequency": {Column: "accrual_frequency", Filterable: true},
+	"Active":           {Column: "active", Filterable: true},
+}
+
+// SearchFields lists the fields leave types can be filtered and sorted on
+func (LeaveType) SearchFields() SearchFields {
+	return leaveTypeSearchFields
+}
+
+func (t *LeaveType) Validate() *SystemError {
+	if t.Code == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "code is required", struct{}{})
+	}
+	if t.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	if !t.Category.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid leave category", struct{}{})
+	}
+	switch t.AccrualFrequency {
+	case AccrualFrequencyNone:
+	case AccrualFrequencyMonthly, AccrualFrequencyYearly:
+		if t.AccrualDays <= 0 {
+			return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "accrual days must be greater than zero", struct{}{})
+		}
+	default:
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid accrual frequency", struct{}{})
+	}
+	if t.MaxBalance < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "max balance cannot be negative", struct{}{})
+	}
+	if t.Category == LeaveCategoryUnpaid && t.Paid {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "unpaid leave cannot be paid", struct{}{})
+	}
+	return nil
+}
+
+// TracksBalance reports whether requests of this type consume an accrued balance
+func (t *LeaveType) TracksBalance() bool {
+	return t.AccrualFrequency == AccrualFrequencyMonthly || t.AccrualFrequency == AccrualFrequencyYearly
+}
+
+// AccruedDays returns the days granted to an employee hired at hireDate for the calendar year of asOf.
+// Balances do not carry over, every year starts again from zero.
+func (t *LeaveType) AccruedDays(hireDate, asOf time.Time) float64 {
+	if !t.TracksBalance() {
+		return 0
+	}
+	yearStart := time.Date(asOf.Year(), time.January, 1, 0, 0, 0, 0, asOf.Location())
+	start := yearStart
+	if hireDate.After(start) {
+		start = hireDate
+	}
+	if asOf.Before(start) {
+		return 0
+	}
+
+	var accrued float64
+	switch t.AccrualFrequency {
+	case AccrualFrequencyYearly:
+		accrued = t.AccrualDays
+	case AccrualFrequencyMonthly:
+		// a month counts once the employee completes it, counted from the later of hire date and year start
+		months := (asOf.Year()-start.Year())*12 + int(asOf.Month()) - int(start.Month())
+		if asOf.Day() < start.Day() {
+			months--
+		}
+		if months < 0 {
+			months = 0
+		}
+		accrued = float64(months) * t.AccrualDays
+	}
+	if t.MaxBalance > 0 && accrued > t.MaxBalance {
+		accrued = t.MaxBalance
+	}
+	return math.Round(accrued*100) / 100
+}
+
+// LeaveBalance is the situation of one employee for one leave type in a year
+type LeaveBalance struct {
+	EmployeeID  string  `json:"employee_id"`
+	LeaveTypeID string  `json:"leave_type_id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Year        int     `json:"year"`
+	Tracked     bool    `json:"tracked"`
+	Accrued     float64 `json:"accrued"`
+	Used        float64 `json:"used"`
+	Pending     float64 `json:"pending"`
+	Available   float64 `json:"available"`
+}
+
+type LeaveStatus string
+
+const (
+	LeaveStatusPending   LeaveStatus = "pending"
+	LeaveStatusApproved  LeaveStatus = "approved"
+	LeaveStatusRejected  LeaveStatus = "rejected"
+	LeaveStatusCancelled LeaveStatus = "cancelled"
+)
+
+// leaveTransitions is the leave request state machine
+var leaveTransitions = map[LeaveStatus][]LeaveStatus{
+	LeaveStatusPending:  {LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled},
+	LeaveStatusApproved: {LeaveStatusCancelled},
+}
+
+// CanTransitionTo reports whether a request in this status can move to next
+func (s LeaveStatus) CanTransitionTo(next LeaveStatus) bool {
+	for _, allowed := range leaveTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// LeaveRequest is the request of an employee to be absent between two dates (inclusive)
+type LeaveRequest struct {
+	ID          string    `json:"id"`
+	EmployeeID  string    `json:"employee_id"`
+	LeaveTypeID string    `json:"leave_type_id"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	// Working days consumed by the request
+	Days         float64     `json:"days"`
+	Reason       string      `json:"reason"`
+	Status       LeaveStatus `json:"status"`
+	ApproverID   string      `json:"approver_id"`
+	DecisionNote string      `json:"decision_note"`
+	DecidedAt    *time.Time  `json:"decided_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+var leaveRequestSearchFields = SearchFields{
+	"ID":          {Column: "id", Filterable: true, Sortable: true},
+	"EmployeeID":  {Column: "employee_id", Filterable: true, Sortable: true},
+	"LeaveTypeID": {Column: "leave_type_id", Filterable: true, Sortable: true},
+	"StartDate":   {Column: "start_date", Filterable: true, Sortable: true},
+	"EndDate":     {Column: "end_date", Filterable: true, Sortable: true},
+	"Days":        {Column: "days", Filterable: true, Sortable: true},
+	"Status":      {Column: "status", Filterable: true, Sortable: true},
+	"ApproverID":  {Column: "approver_id", Filterable: true, Nullable: true},
+	"DecidedAt":   {Column: "decided_at", Filterable: true, Sortable: true},
+	"CreatedAt":   {Column: "created_at", Filterable: true, Sortable: true},
+}
+
+// SearchFields lists the fields leave requests can be filtered and sorted on
+func (LeaveRequest) SearchFields() SearchFields {
+	return leaveRequestSearchFields
+}
+
+// Overlaps reports whether both requests share at least one day
+func (r *LeaveRequest) Overlaps(start, end time.Time) bool {
+	return !r.StartDate.After(end) && !start.After(r.EndDate)
+}
+
+// SubmitLeaveRequest is the request of an employee to take leave.
+// EmployeeID may be empty when employees submit for themselves.
+type SubmitLeaveRequest struct {
+	EmployeeID  string    `json:"employee_id"`
+	LeaveTypeID string    `json:"leave_type_id"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	Reason      string    `json:"reason"`
+}
+
+// MaxLeaveRequestDays limits the calendar days covered by a single request
+const MaxLeaveRequestDays = 366
+
+func (r *SubmitLeaveRequest) Validate() *SystemError {
+	if r.EmployeeID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "employee is required", struct{}{})
+	}
+	if r.LeaveTypeID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "leave type is required", struct{}{})
+	}
+	if r.StartDate.IsZero() || r.EndDate.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "start and end dates are required", struct{}{})
+	}
+	if r.EndDate.Before(r.StartDate) {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "end date cannot be before start date", struct{}{})
+	}
+	if r.EndDate.Sub(r.StartDate) >= MaxLeaveRequestDays*24*time.Hour {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a leave request cannot exceed one year", struct{}{})
+	}
+	if r.StartDate.Year() != r.EndDate.Year() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a leave request cannot span two years, split it in two requests", struct{}{})
+	}
+	return nil
+}
+
+// ToLeaveRequest builds a pending request, dates are truncated to whole days
+func (r *SubmitLeaveRequest) ToLeaveRequest() LeaveRequest {
+	return LeaveRequest{
+		EmployeeID:  r.EmployeeID,
+		LeaveTypeID: r.LeaveTypeID,
+		StartDate:   truncateDay(r.StartDate),
+		EndDate:     truncateDay(r.EndDate),
+		Reason:      r.Reason,
+		Status:      LeaveStatusPending,
+	}
+}
+
+type LeaveDecision string
+
+const (
+	LeaveDecisionApprove LeaveDecision = "approve"
+	LeaveDecisionReject  LeaveDecision = "reject"
+)
+
+// DecideLeaveRequest is the approval or rejection of a request by a manager
+type DecideLeaveRequest struct {
+	RequestID  string        `json:"request_id"`
+	Decision   LeaveDecision `json:"-"`
+	Note       string        `json:"note"`
+	ApproverID string        `json:"-"`
+}
+
+func (d *DecideLeaveRequest) Validate() *SystemError {
+	if d.RequestID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "request id is required", struct{}{})
+	}
+	if d.Decision != LeaveDecisionApprove && d.Decision != LeaveDecisionReject {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid decision", struct{}{})
+	}
+	if d.Decision == LeaveDecisionReject && d.Note == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a note is required to reject a request", struct{}{})
+	}
+	if d.ApproverID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "approver is required", struct{}{})
+	}
+	return nil
+}
+
+// Status returns the status the request moves to
+func (d *DecideLeaveRequest) Status() LeaveStatus {
+	if d.Decision == LeaveDecisionApprove {
+		return LeaveStatusApproved
+	}
+	return LeaveStatusRejected
+}
+
+// CancelLeaveRequest is the cancellation of a request by its employee or its manager
+type CancelLeaveRequest struct {
+	RequestID   string `json:"request_id"`
+	Reason      string `json:"reason"`
+	CancelledBy string `json:"-"`
+}
+
+func (c *CancelLeaveRequest) Validate() *SystemError {
+	if c.RequestID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "request id is required", struct{}{})
+	}
+	if c.CancelledBy == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "canceller is required", struct{}{})
+	}
+	return nil
+}
+
+// LeaveBalanceQuery asks for the balances of an employee in a year, the current year when Year is 0
+type LeaveBalanceQuery struct {
+	EmployeeID string `json:"employee_id"`
+	Year       int    `json:"year"`
+}
+
+// CountLeaveDays returns the working days of the schedule between start and end (inclusive)
+func (s *WorkSchedule) CountLeaveDays(start, end time.Time) float64 {
+	var days float64
+	for day := truncateDay(start); !day.After(truncateDay(end)); day = day.AddDate(0, 0, 1) {
+		if s.IsWorkDay(day.Weekday()) {
+			days++
+		}
+	}
+	return days
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaveTypeAccruedDays(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	monthly := LeaveType{AccrualFrequency: AccrualFrequencyMonthly, AccrualDays: 1.25, MaxBalance: 10}
+	yearly := LeaveType{AccrualFrequency: AccrualFrequencyYearly, AccrualDays: 12}
+	unpaid := LeaveType{AccrualFrequency: AccrualFrequencyNone}
+
+	cases := []struct {
+		name      string
+		leaveType LeaveType
+		hireDate  time.Time
+		asOf      time.Time
+		expected  float64
+	}{
+		{"monthly from year start", monthly, date(2020, time.June, 1), date(2025, time.April, 1), 3.75},
+		{"monthly hired during the year", monthly, date(2025, time.March, 15), date(2025, time.May, 14), 1.25},
+		{"monthly capped", monthly, date(2020, time.June, 1), date(2025, time.December, 31), 10},
+		{"monthly before hire", monthly, date(2025, time.March, 15), date(2025, time.March, 1), 0},
+		{"yearly", yearly, date(2020, time.June, 1), date(2025, time.January, 1), 12},
+		{"no accrual", unpaid, date(2020, time.June, 1), date(2025, time.June, 1), 0},
+	}
+	for _, c := range cases {
+		if got := c.leaveType.AccruedDays(c.hireDate, c.asOf); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestLeaveStatusTransitions(t *testing.T) {
+	allowed := map[LeaveStatus][]LeaveStatus{
+		LeaveStatusPending:   {LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled},
+		LeaveStatusApproved:  {LeaveStatusCancelled},
+		LeaveStatusRejected:  {},
+		LeaveStatusCancelled: {},
+	}
+	all := []LeaveStatus{LeaveStatusPending, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled}
+	for from, targets := range allowed {
+		for _, to := range all {
+			expected := false
+			for _, target := range targets {
+				expected = expected || target == to
+			}
+			if from.CanTransitionTo(to) != expected {
+				t.Errorf("transition %s -> %s: expected %v", from, to, expected)
+			}
+		}
+	}
+}
+
+func TestLeaveRequestOverlaps(t *testing.T) {
+	request := LeaveRequest{
+		StartDate: time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+	}
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+	if !request.Overlaps(day(14), day(20)) {
+		t.Error("Expected requests sharing the last day to overlap")
+	}
+	if request.Overlaps(day(15), day(20)) {
+		t.Error("Expected consecutive requests not to overlap")
+	}
+	if !request.Overlaps(day(1), day(31)) {
+		t.Error("Expected a containing range to overlap")
+	}
+}
+
+func TestCountLeaveDays(t *testing.T) {
+	schedule := WorkSchedule{WorkDays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+	// Friday 7 to Tuesday 11 of March 2025
+	days := schedule.CountLeaveDays(time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC), time.Date(2025, time.March, 11, 0, 0, 0, 0, time.UTC))
+	if days != 3 {
+		t.Fatalf("Expected 3 working days, got %v", days)
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	// LoginLockoutThreshold is the number of consecutive failed logins tolerated before locking the account
+	LoginLockoutThreshold = 5
+	// loginLockoutBase is the lock applied when the threshold is reached, doubled by every further failure
+	loginLockoutBase = time.Minute
+	loginLockoutMax  = 24 * time.Hour
+)
+
+// LockoutDuration returns how long an account stays locked after the given consecutive failed logins
+func LockoutDuration(failedAttempts int) time.Duration {
+	if failedAttempts < LoginLockoutThreshold {
+		return 0
+	}
+	lock := loginLockoutBase
+	for i := LoginLockoutThreshold; i < failedAttempts; i++ {
+		lock *= 2
+		if lock >= loginLockoutMax {
+			return loginLockoutMax
+		}
+	}
+	return lock
+}
+
+// IsLocked reports whether the user is locked out at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// UnlockUser clears the lockout of a user, requested by an administrator
+type UnlockUser struct {
+	Username string `json:"username"`
+	// Username of the administrator and address of its client, recorded in the audit log
+	Actor string `json:"-"`
+	IP    string `json:"-"`
+}
+
+func (u *UnlockUser) Validate() *SystemError {
+	if u.Username == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:                          0,
+		LoginLockoutThreshold - 1:  0,
+		LoginLockoutThreshold:      time.Minute,
+		LoginLockoutThreshold + 1:  2 * time.Minute,
+		LoginLockoutThreshold + 3:  8 * time.Minute,
+		LoginLockoutThreshold + 50: 24 * time.Hour,
+	}
+	for attempts, expected := range cases {
+		if got := LockoutDuration(attempts); got != expected {
+			t.Fatalf("Expected %v after %d failures, got %v", expected, attempts, got)
+		}
+	}
+}
+
+func TestUserIsLocked(t *testing.T) {
+	now := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+	user := User{}
+	if user.IsLocked(now) {
+		t.Fatal("Expected users without lock to be able to log in")
+	}
+	until := now.Add(time.Minute)
+	user.LockedUntil = &until
+	if !user.IsLocked(now) {
+		t.Fatal("Expected user to be locked before the lock expires")
+	}
+	if user.IsLocked(until) {
+		t.Fatal("Expected lock to end at its expiration time")
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import "strings"
+
+type Language string
+
+const (
+	LanguageSpanish Language = "es"
+	LanguageEnglish Language = "en"
+	// DefaultLanguage is used when the client does not ask for a supported one
+	DefaultLanguage = LanguageSpanish
+)
+
+// ParseLanguage reads a language tag such as "en-US" or an Accept-Language header,
+// the first supported language wins and DefaultLanguage is the fallback
+func ParseLanguage(value string) Language {
+	for _, part := range strings.Split(value, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		switch Language(base) {
+		case LanguageSpanish, LanguageEnglish:
+			return Language(base)
+		}
+	}
+	return DefaultLanguage
+}
+
+type MailTemplate string
+
+const (
+	MailTemplatePasswordReset     MailTemplate = "password_reset"
+	MailTemplateEmailVerification MailTemplate = "email_verification"
+	MailTemplateInvitation        MailTemplate = "invitation"
+)
+
+// MailMessage is a templated message before rendering
+type MailMessage struct {
+	Template MailTemplate
+	Language Language
+	To       string
+	// Values available to the template, such as Name and Link
+	Data map[string]string
+}
+
+// Mail is a rendered message ready to send, HTML is optional
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
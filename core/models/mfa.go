@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+const (
+	// MFAChallengeTTL is how long the second step of a login can wait
+	MFAChallengeTTL = 5 * time.Minute
+	// MFAChallengePurpose marks the signed challenge so it is never accepted as an access token
+	MFAChallengePurpose = "mfa"
+	// MFARecoveryCodeCount is the number of recovery codes handed out on activation
+	MFARecoveryCodeCount = 10
+)
+
+// RecoveryCode is the hash of a single use code replacing the authenticator app
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAChallenge is returned by the login instead of a session when the user has MFA enabled
+type MFAChallenge struct {
+	Token     string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"-"`
+}
+
+// LoginResult holds either the logged in user or the challenge of its second factor
+type LoginResult struct {
+	User      *UserData
+	Challenge *MFAChallenge
+}
+
+// MFAEnrollment is the secret to load in the authenticator app, MFA stays disabled until a code is verified
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFARecoveryCodes are shown once, only their hashes are stored
+type MFARecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// VerifyMFA completes a login with the code of the authenticator app or a recovery code
+type VerifyMFA struct {
+	Token string `json:"mfa_token"`
+	Code  string `json:"code"`
+	// Address of the client, recorded in the audit log
+	IP string `json:"-"`
+}
+
+func (v *VerifyMFA) Validate() *SystemError {
+	if v.Token == "" || v.Code == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "mfa_token and code are required", struct{}{})
+	}
+	return nil
+}
+
+// MFACode confirms an MFA operation of the authenticated user
+type MFACode struct {
+	Username string `json:"-"`
+	Code     string `json:"code"`
+	// Current password, only required to disable MFA
+	Password string `json:"password"`
+	IP       string `json:"-"`
+}
+
+func (m *MFACode) Validate() *SystemError {
+	if m.Username == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
+	}
+	if m.Code == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "code is required", struct{}{})
+	}
+	return nil
+}
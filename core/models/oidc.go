@@ -0,0 +1,98 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// OIDCFlowPurpose marks the signed login flow so it is never accepted as an access token
+	OIDCFlowPurpose = "oidc"
+	// OIDCFlowTTL is how long the user has to sign in at the provider
+	OIDCFlowTTL = 10 * time.Minute
+)
+
+// OIDCIdentity is the user authenticated by the OpenID Connect provider, read from its ID token
+type OIDCIdentity struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"given_name"`
+	LastName      string   `json:"family_name"`
+	Groups        []string `json:"groups"`
+}
+
+// ExternalID identifies the identity across providers, it is stored in User.ExternalID on the first login
+func (i *OIDCIdentity) ExternalID() string {
+	return i.Issuer + "|" + i.Subject
+}
+
+// OIDCGroupRole grants the role named Role to the members of Group
+type OIDCGroupRole struct {
+	Group string
+	Role  string
+}
+
+// ParseOIDCGroupRoles reads mappings such as "hr-admins=Admin,staff=User", the first matching group wins
+func ParseOIDCGroupRoles(value string) ([]OIDCGroupRole, *SystemError) {
+	mappings := []OIDCGroupRole{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || role == "" {
+			return nil, NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid group mapping "+pair+", use group=Role", struct{}{})
+		}
+		mappings = append(mappings, OIDCGroupRole{Group: group, Role: role})
+	}
+	return mappings, nil
+}
+
+// OIDCSettings decide how identities of the provider become users
+type OIDCSettings struct {
+	// Create the users signing in for the first time, otherwise only existing emails can sign in
+	AutoProvision bool
+	// Name of the role of users no group mapping applies to, empty for no role
+	DefaultRole string
+	GroupRoles  []OIDCGroupRole
+}
+
+// RoleFor returns the role mapped to the first configured group the identity belongs to
+func (s *OIDCSettings) RoleFor(groups []string) (string, bool) {
+	member := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		member[group] = true
+	}
+	for _, mapping := range s.GroupRoles {
+		if member[mapping.Group] {
+			return mapping.Role, true
+		}
+	}
+	return "", false
+}
+
+// OIDCAuthorization starts a login: the client sends the user to URL and keeps Flow to complete it
+type OIDCAuthorization struct {
+	URL string `json:"authorization_url"`
+	// Signed state, nonce and PKCE verifier of this login, never sent to the provider
+	Flow      string `json:"flow"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// OIDCCallback completes a login with the parameters the provider redirected to the client with
+type OIDCCallback struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+	Flow  string `json:"flow"`
+	IP    string `json:"-"`
+}
+
+func (oc *OIDCCallback) Validate() *SystemError {
+	if oc.Code == "" || oc.State == "" || oc.Flow == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "code, state and flow are required", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestParseOIDCGroupRoles(t *testing.T) {
+	mappings, err := ParseOIDCGroupRoles(" hr-admins = Admin, staff=User ,")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mappings) != 2 || mappings[0] != (OIDCGroupRole{Group: "hr-admins", Role: "Admin"}) || mappings[1].Role != "User" {
+		t.Fatalf("Unexpected mappings %+v", mappings)
+	}
+	if mappings, err := ParseOIDCGroupRoles(""); err != nil || len(mappings) != 0 {
+		t.Fatalf("Expected no mappings, got %+v %v", mappings, err)
+	}
+	if _, err := ParseOIDCGroupRoles("hr-admins"); err == nil {
+		t.Fatal("Expected a mapping without role to be refused")
+	}
+}
+
+func TestOIDCSettingsRoleForFirstMatchingGroup(t *testing.T) {
+	settings := OIDCSettings{GroupRoles: []OIDCGroupRole{{Group: "hr-admins", Role: "Admin"}, {Group: "staff", Role: "User"}}}
+	if role, ok := settings.RoleFor([]string{"staff", "hr-admins"}); !ok || role != "Admin" {
+		t.Fatalf("Expected the first configured mapping, got %q %v", role, ok)
+	}
+	if _, ok := settings.RoleFor([]string{"contractors"}); ok {
+		t.Fatal("Expected no role for unmapped groups")
+	}
+}
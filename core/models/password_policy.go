@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// PasswordMaxBytes is the longest password bcrypt can hash
+const PasswordMaxBytes = 72
+
+type PasswordRule string
+
+const (
+	PasswordRuleMinLength PasswordRule = "min_length"
+	PasswordRuleMaxLength PasswordRule = "max_length"
+	PasswordRuleUppercase PasswordRule = "uppercase"
+	PasswordRuleLowercase PasswordRule = "lowercase"
+	PasswordRuleDigit     PasswordRule = "digit"
+	PasswordRuleSymbol    PasswordRule = "symbol"
+	PasswordRuleBreached  PasswordRule = "breached"
+	PasswordRuleReused    PasswordRule = "reused"
+)
+
+// PasswordPolicy holds the rules new passwords must follow
+type PasswordPolicy struct {
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	// Number of previous passwords that cannot be reused
+	HistorySize int `json:"history_size"`
+	// Passwords older than this must be changed at the next login, zero disables expiry
+	MaxAge time.Duration `json:"-"`
+}
+
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		HistorySize:  5,
+		MaxAge:       90 * 24 * time.Hour,
+	}
+}
+
+// Expired reports whether a password changed at the given time has to be changed
+func (p PasswordPolicy) Expired(changedAt *time.Time, now time.Time) bool {
+	return p.MaxAge > 0 && changedAt != nil && !now.Before(changedAt.Add(p.MaxAge))
+}
+
+// PasswordViolation is a rule of the policy a password does not follow
+type PasswordViolation struct {
+	Rule    PasswordRule `json:"rule"`
+	Message string       `json:"message"`
+}
+
+// PasswordPolicyDetails is set in SystemError.Details when a password is refused
+type PasswordPolicyDetails struct {
+	Violations []PasswordViolation `json:"violations"`
+	Policy     PasswordPolicy      `json:"policy"`
+}
+
+func NewPasswordPolicyError(policy PasswordPolicy, violations []PasswordViolation) *SystemError {
+	return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "La contraseña no cumple la política de seguridad", PasswordPolicyDetails{
+		Violations: violations,
+		Policy:     policy,
+	})
+}
+
+// PasswordHistory is a previous password hash of a user
+type PasswordHistory struct {
+	ID           string
+	UserID       string
+	PasswordHash string
+	CreatedAt    time.Time
+}
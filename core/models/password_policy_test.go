@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPasswordPolicyExpired(t *testing.T) {
+	now := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+	policy := PasswordPolicy{MaxAge: 90 * 24 * time.Hour}
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-90 * 24 * time.Hour)
+	if policy.Expired(&recent, now) {
+		t.Fatal("Expected recent password not to expire")
+	}
+	if !policy.Expired(&old, now) {
+		t.Fatal("Expected password to expire after MaxAge")
+	}
+	if policy.Expired(nil, now) {
+		t.Fatal("Expected passwords without change date not to expire")
+	}
+	policy.MaxAge = 0
+	if policy.Expired(&old, now) {
+		t.Fatal("Expected zero MaxAge to disable expiry")
+	}
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// PayPeriod is the range of days paid by a payroll run
+type PayPeriod struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	PayDate   time.Time `json:"pay_date"`
+}
+
+var payPeriodSearchFields = SearchFields{
+	"ID":        {Column: "id", Filterable: true, Sortable: true},
+	"Name":      {Column: "name", Filterable: true, Sortable: true},
+	"StartDate": {Column: "start_date", Filterable: true, Sortable: true},
+	"EndDate":   {Column: "end_date", Filterable: true, Sortable: true},
+	"PayDate":   {Column: "pay_date", Filterable: true, Sortable: true},
+}
+
+// SearchFields lists the fields pay periods can be filtered and sorted on
+func (PayPeriod) SearchFields() SearchFields {
+	return payPeriodSearchFields
+}
+
+func (p *PayPeriod) Validate() *SystemError {
+	if p.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	if p.StartDate.IsZero() || p.EndDate.IsZero() || p.PayDate.IsZero() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "start, end and pay dates are required", struct{}{})
+	}
+	if p.EndDate.Before(p.StartDate) {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "end date cannot be before start date", struct{}{})
+	}
+	if p.EndDate.Sub(p.StartDate) > MaxTimesheetDays*24*time.Hour {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a pay period cannot exceed a quarter", struct{}{})
+	}
+	if p.PayDate.Before(p.StartDate) {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "pay date cannot be before the start of the period", struct{}{})
+	}
+	return nil
+}
+
+// Days returns the calendar days of the period
+func (p *PayPeriod) Days() int {
+	return int(truncateDay(p.EndDate).Sub(truncateDay(p.StartDate)).Hours()/24) + 1
+}
+
+// PeriodsPerYear returns how many periods like this one a year holds, used to annualize its pay.
+// Calendar months, half months and quarters are 12, 24 and 4 whatever their days,
+// other periods divide the year by their days (52 weekly, 26 biweekly).
+func (p *PayPeriod) PeriodsPerYear() int {
+	start, end := truncateDay(p.StartDate), truncateDay(p.EndDate)
+	lastDay := func(months int) time.Time {
+		return time.Date(start.Year(), start.Month()+time.Month(months)+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	switch {
+	case start.Day() == 1 && end.Equal(lastDay(0)):
+		return 12
+	case start.Day() == 1 && end.Equal(time.Date(start.Year(), start.Month(), 15, 0, 0, 0, 0, time.UTC)),
+		start.Day() == 16 && end.Equal(lastDay(0)):
+		return 24
+	case start.Day() == 1 && end.Equal(lastDay(2)):
+		return 4
+	}
+	return max(1, int(math.Round(365/float64(p.Days()))))
+}
+
+type PayrollStatus string
+
+const (
+	// PayrollStatusDraft runs can be recalculated or deleted
+	PayrollStatusDraft PayrollStatus = "draft"
+	// PayrollStatusFinalized runs are locked, their payslips cannot change anymore
+	PayrollStatusFinalized PayrollStatus = "finalized"
+	// PayrollStatusPaid runs have been paid to the employees
+	PayrollStatusPaid PayrollStatus = "paid"
+)
+
+// CanTransitionTo reports whether a run in this status can move to next
+func (s PayrollStatus) CanTransitionTo(next PayrollStatus) bool {
+	switch s {
+	case PayrollStatusDraft:
+		return next == PayrollStatusFinalized
+	case PayrollStatusFinalized:
+		return next == PayrollStatusPaid
+	}
+	return false
+}
+
+// IsLocked reports whether the run and its payslips can no longer be modified
+func (s PayrollStatus) IsLocked() bool {
+	return s != PayrollStatusDraft
+}
+
+// PayrollRun is the calculation of the payslips of every employee for a pay period
+type PayrollRun struct {
+	ID              string        `json:"id"`
+	PayPeriodID     string        `json:"pay_period_id"`
+	Status          PayrollStatus `json:"status"`
+	EmployeeCount   int           `json:"employee_count"`
+	TotalGross      float64       `json:"total_gross"`
+	TotalDeductions float64       `json:"total_deductions"`
+	TotalNet        float64       `json:"total_net"`
+	CalculatedAt    time.Time     `json:"calculated_at"`
+	FinalizedAt     *time.Time    `json:"finalized_at"`
+	PaidAt          *time.Time    `json:"paid_at"`
+}
+
+var payrollRunSearchFields = SearchFields{
+	"ID":            {Column: "id", Filterable: true, Sortable: true},
+	"PayPeriodID":   {Column: "pay_period_id", Filterable: true, Sortable: true},
+	"Status":        {Column: "status", Filterable: true, Sortable: true},
+	"EmployeeCount": {Column: "employee_count", Filterable: true, Sortable: true},
+	"TotalGross":    {Column: "total_gross", Filterable: true, Sortable: true},
+	"TotalNet":      {Column: "total_net", Filterable: true, Sortable: true},
+	"CalculatedAt":  {Column: "calculated_at", Filterable: true, Sortable: true},
+	"FinalizedAt":   {Column: "finalized_at", Filterable: true, Sortable: true},
+	"PaidAt":        {Column: "paid_at", Filterable: true, Sortable: true},
+}
+
+// SearchFields lists the fields payroll runs can be filtered and sorted on
+func (PayrollRun) SearchFields() SearchFields {
+	return payrollRunSearchFields
+}
+
+// AddPayslip accumulates the payslip amounts into the run totals
+func (r *PayrollRun) AddPayslip(payslip Payslip) {
+	r.EmployeeCount++
+	r.TotalGross = RoundAmount(r.TotalGross + payslip.GrossPay)
+	r.TotalDeductions = RoundAmount(r.TotalDeductions + payslip.TotalDeductions)
+	r.TotalNet = RoundAmount(r.TotalNet + payslip.NetPay)
+}
+
+// CalculatePayrollRun asks for the (re)calculation of the draft run of a pay period
+type CalculatePayrollRun struct {
+	PayPeriodID string `json:"pay_period_id"`
+}
+
+// ChangePayrollRunStatus moves a run forward in its lifecycle
+type ChangePayrollRunStatus struct {
+	RunID  string        `json:"run_id"`
+	Status PayrollStatus `json:"-"`
+}
+
+type PayslipLineKind string
+
+const (
+	PayslipLineKindEarning   PayslipLineKind = "earning"
+	PayslipLineKindDeduction PayslipLineKind = "deduction"
+)
+
+// PayslipLine is one earning or deduction of a payslip
+type PayslipLine struct {
+	Code        string          `json:"code"`
+	Description string          `json:"description"`
+	Kind        PayslipLineKind `json:"kind"`
+	Amount      float64         `json:"amount"`
+}
+
+// Payslip is the pay of one employee for one payroll run
+type Payslip struct {
+	ID              string        `json:"id"`
+	PayrollRunID    string        `json:"payroll_run_id"`
+	PayPeriodID     string        `json:"pay_period_id"`
+	EmployeeID      string        `json:"employee_id"`
+	PositionID      string        `json:"position_id"`
+	Currency        string        `json:"currency"`
+	BaseSalary      float64       `json:"base_salary"`
+	WorkedDays      int           `json:"worked_days"`
+	OvertimeMinutes int           `json:"overtime_minutes"`
+	GrossPay        float64       `json:"gross_pay"`
+	TotalDeductions float64       `json:"total_deductions"`
+	NetPay          float64       `json:"net_pay"`
+	Lines           []PayslipLine `json:"lines"`
+	// PeriodsPerYear of the pay period, set while calculating for the rules that annualize the pay
+	PeriodsPerYear int `json:"-"`
+}
+
+var payslipSearchFields = SearchFields{
+	"ID":           {Column: "id", Filterable: true, Sortable: true},
+	"PayrollRunID": {Column: "payroll_run_id", Filterable: true, Sortable: true},
+	"PayPeriodID":  {Column: "pay_period_id", Filterable: true, Sortable: true},
+	"EmployeeID":   {Column: "employee_id", Filterable: true, Sortable: true},
+	"PositionID":   {Column: "position_id", Filterable: true, Nullable: true},
+	"Currency":     {Column: "currency", Filterable: true},
+	"GrossPay":     {Column: "gross_pay", Filterable: true, Sortable: true},
+	"NetPay":       {Column: "net_pay", Filterable: true, Sortable: true},
+}
+
+// SearchFields lists the fields payslips can be filtered and sorted on
+func (Payslip) SearchFields() SearchFields {
+	return payslipSearchFields
+}
+
+// AddLine appends a line and keeps the totals up to date, zero amounts are ignored
+func (p *Payslip) AddLine(line PayslipLine) {
+	line.Amount = RoundAmount(line.Amount)
+	if line.Amount == 0 {
+		return
+	}
+	p.Lines = append(p.Lines, line)
+	if line.Kind == PayslipLineKindEarning {
+		p.GrossPay = RoundAmount(p.GrossPay + line.Amount)
+	} else {
+		p.TotalDeductions = RoundAmount(p.TotalDeductions + line.Amount)
+	}
+	p.NetPay = RoundAmount(p.GrossPay - p.TotalDeductions)
+}
+
+// RoundAmount rounds a monetary amount to cents
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type BankFileFormat string
+
+const (
+	BankFileFormatCSV        BankFileFormat = "csv"
+	BankFileFormatFixedWidth BankFileFormat = "fixed_width"
+)
+
+// IsValid reports whether the format is one of the supported bank file formats
+func (f BankFileFormat) IsValid() bool {
+	return f == BankFileFormatCSV || f == BankFileFormatFixedWidth
+}
+
+// Extension returns the file extension used for downloads
+func (f BankFileFormat) Extension() string {
+	if f == BankFileFormatFixedWidth {
+		return "txt"
+	}
+	return "csv"
+}
+
+// PayslipDocument gathers the data printed on a payslip
+type PayslipDocument struct {
+	CompanyName    string    `json:"company_name"`
+	Payslip        Payslip   `json:"payslip"`
+	Employee       Employee  `json:"employee"`
+	Period         PayPeriod `json:"period"`
+	PositionName   string    `json:"position_name"`
+	DepartmentName string    `json:"department_name"`
+}
+
+// BankTransfer is the payment of the net pay of one employee
+type BankTransfer struct {
+	EmployeeNumber string  `json:"employee_number"`
+	EmployeeName   string  `json:"employee_name"`
+	NationalID     string  `json:"national_id"`
+	BankName       string  `json:"bank_name"`
+	BankAccount    string  `json:"bank_account"`
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+}
+
+// BankTransferBatch is the set of transfers that pays a payroll run
+type BankTransferBatch struct {
+	CompanyName string         `json:"company_name"`
+	RunID       string         `json:"run_id"`
+	PeriodName  string         `json:"period_name"`
+	PayDate     time.Time      `json:"pay_date"`
+	Transfers   []BankTransfer `json:"transfers"`
+}
+
+// Total returns the sum of the transfers of the batch
+func (b *BankTransferBatch) Total() float64 {
+	var total float64
+	for _, transfer := range b.Transfers {
+		total = RoundAmount(total + transfer.Amount)
+	}
+	return total
+}
+
+// ExportBankFile asks for the bank file of a payroll run
+type ExportBankFile struct {
+	RunID  string         `json:"run_id"`
+	Format BankFileFormat `json:"format"`
+}
+
+func (e *ExportBankFile) Validate() *SystemError {
+	if e.RunID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "run id is required", struct{}{})
+	}
+	if !e.Format.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "invalid bank file format", struct{}{})
+	}
+	return nil
+}
@@ -4,8 +4,6 @@ type Permission struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	RoleId      string `json:"role_id"`
-	Role        Role   `json:"role"`
 }
 
 const (
@@ -14,14 +12,39 @@ const (
 	PermissionEditEmployees         = "edit_employees"
 	PermissionViewEmployees         = "view_employees"
 	PermissionViewMenuDepartments   = "view_menu_departments"
+	PermissionEditDepartments       = "edit_departments"
 	PermissionViewMenuPosition      = "view_menu_position"
+	PermissionEditPositions         = "edit_positions"
 	PermissionViewMenuAttendance    = "view_menu_attendance"
+	PermissionManageAttendance      = "manage_attendance"
 	PermissionViewMenuPayroll       = "view_menu_payroll"
+	PermissionManagePayroll         = "manage_payroll"
 	PermissionViewMenuLeaveRequests = "view_menu_leave_requests"
+	PermissionManageLeaves          = "manage_leaves"
 	PermissionViewMenuSettings      = "view_menu_settings"
 	PermissionAllAccess             = "all_access"
 	PermissionViewRoles             = "view_roles"
 	PermissionEditRoles             = "edit_roles"
 	PermissionEditUsers             = "edit_users"
 	PermissionViewUsers             = "view_users"
+	PermissionExportPayroll         = "export_payroll"
+	PermissionManageAPIKeys         = "manage_api_keys"
 )
+
+// HasPermission reports whether the permissions grant every required permission,
+// all_access grants everything
+func HasPermission(permissions []Permission, required ...string) bool {
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		if permission.Name == PermissionAllAccess {
+			return true
+		}
+		granted[permission.Name] = true
+	}
+	for _, name := range required {
+		if !granted[name] {
+			return false
+		}
+	}
+	return true
+}
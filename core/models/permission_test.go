@@ -0,0 +1,19 @@
+package models
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	granted := []Permission{{Name: PermissionViewUsers}, {Name: PermissionViewRoles}}
+	if !HasPermission(granted, PermissionViewUsers, PermissionViewRoles) {
+		t.Fatal("Expected granted permissions to be accepted")
+	}
+	if HasPermission(granted, PermissionViewUsers, PermissionEditUsers) {
+		t.Fatal("Expected every required permission to be needed")
+	}
+	if !HasPermission([]Permission{{Name: PermissionAllAccess}}, PermissionEditRoles) {
+		t.Fatal("Expected all_access to grant every permission")
+	}
+	if HasPermission(nil, PermissionViewUsers) {
+		t.Fatal("Expected users without role to be rejected")
+	}
+}
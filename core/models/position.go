@@ -3,11 +3,96 @@ package models
 // Position model
 type Position struct {
 	// ID of the position
-	ID string
+	ID string `json:"id"`
 	// Name of the position
-	Name string
+	Name string `json:"name"`
+	// Description of the position
+	Description string `json:"description"`
 	// ID of the department
-	DepartmentID string
+	DepartmentID string `json:"department_id"`
 	// Department of the position
-	Department *Department
+	Department *Department `json:"department,omitempty"`
+	// Lower bound of the salary band
+	MinSalary float64 `json:"min_salary"`
+	// Upper bound of the salary band
+	MaxSalary float64 `json:"max_salary"`
+	// ISO 4217 currency code of the salary band
+	Currency string `json:"currency"`
+	// Number of employees budgeted for the position, 0 means unlimited
+	Headcount int `json:"headcount"`
+}
+
+var positionSearchFields = SearchFields{
+	"ID":           {Column: "id", Filterable: true, Sortable: true},
+	"Name":         {Column: "name", Filterable: true, Sortable: true},
+	"DepartmentID": {Column: "department_id", Filterable: true, Sortable: true},
+	"MinSalary":    {Column: "min_salary", Filterable: true, Sortable: true},
+	"MaxSalary":    {Column: "max_salary", Filterable: true, Sortable: true},
+	"Currency":     {Column: "currency", Filterable: true, Sortable: true},
+	"Headcount":    {Column: "headcount", Filterable: true, Sortable: true},
+}
+
+// SearchFields lists the fields positions can be filtered and sorted on
+func (Position) SearchFields() SearchFields {
+	return positionSearchFields
+}
+
+type CreatePosition struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	DepartmentID string  `json:"department_id"`
+	MinSalary    float64 `json:"min_salary"`
+	MaxSalary    float64 `json:"max_salary"`
+	Currency     string  `json:"currency"`
+	Headcount    int     `json:"headcount"`
+}
+
+func (cp *CreatePosition) ToPosition() *Position {
+	return &Position{
+		Name:         cp.Name,
+		Description:  cp.Description,
+		DepartmentID: cp.DepartmentID,
+		MinSalary:    cp.MinSalary,
+		MaxSalary:    cp.MaxSalary,
+		Currency:     cp.Currency,
+		Headcount:    cp.Headcount,
+	}
+}
+
+func (cp *CreatePosition) Validate() *SystemError {
+	return cp.ToPosition().validateFields()
+}
+
+func (p *Position) Validate() *SystemError {
+	if p.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	return p.validateFields()
+}
+
+// IsSalaryInBand reports whether the salary falls inside the position salary band
+func (p *Position) IsSalaryInBand(salary float64) bool {
+	return salary >= p.MinSalary && salary <= p.MaxSalary
+}
+
+func (p *Position) validateFields() *SystemError {
+	if p.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	if p.DepartmentID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "department is required", struct{}{})
+	}
+	if p.MinSalary < 0 || p.MaxSalary < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "salary band cannot be negative", struct{}{})
+	}
+	if p.MinSalary > p.MaxSalary {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "minimum salary cannot be greater than maximum salary", struct{}{})
+	}
+	if len(p.Currency) != 3 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "currency must be a 3 letter ISO 4217 code", struct{}{})
+	}
+	if p.Headcount < 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "headcount cannot be negative", struct{}{})
+	}
+	return nil
 }
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type RegistrationMode string
+
+const (
+	// RegistrationModeDisabled only lets administrators create users
+	RegistrationModeDisabled RegistrationMode = "disabled"
+	// RegistrationModeInvite lets people register with an invitation issued by an administrator
+	RegistrationModeInvite RegistrationMode = "invite"
+	// RegistrationModeOpen lets anyone register with the default role, invitations keep working
+	RegistrationModeOpen RegistrationMode = "open"
+)
+
+// IsValid reports whether the mode is one of the known registration modes
+func (m RegistrationMode) IsValid() bool {
+	switch m {
+	case RegistrationModeDisabled, RegistrationModeInvite, RegistrationModeOpen:
+		return true
+	}
+	return false
+}
+
+// InvitationTTL is how long an invitation can be accepted
+const InvitationTTL = 7 * 24 * time.Hour
+
+// IsValid reports whether the type is one of the known user types
+func (t UserType) IsValid() bool {
+	switch t {
+	case UserTypeAdmin, UserTypeNormal:
+		return true
+	}
+	return false
+}
+
+// RegistrationSettings decide who can register and with which role
+type RegistrationSettings struct {
+	Mode RegistrationMode
+	// Name of the role of users registered without invitation
+	DefaultRole string
+}
+
+// Invitation lets the owner of Email register with the type and role chosen by the administrator.
+// Only the SHA-256 of the mailed token is stored.
+type Invitation struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	RoleID    string     `json:"roleId"`
+	Type      UserType   `json:"type"`
+	TokenHash string     `json:"-"`
+	InvitedBy string     `json:"invitedBy"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+	RevokedAt *time.Time `json:"revokedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Usable reports whether the invitation can still be accepted at the given time
+func (i *Invitation) Usable(now time.Time) bool {
+	return i.UsedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// CreateInvitation is sent by an administrator to invite someone by email
+type CreateInvitation struct {
+	Email    string   `json:"email"`
+	RoleID   string   `json:"roleId"`
+	Type     UserType `json:"type"`
+	Language Language `json:"language"`
+	// Username of the administrator, set by the server
+	Actor string `json:"-"`
+	// Page of the client receiving the token, set by the server
+	AcceptURL string `json:"-"`
+}
+
+func (ci *CreateInvitation) Validate() *SystemError {
+	if !strings.Contains(ci.Email, "@") {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "a valid email is required", struct{}{})
+	}
+	if ci.Type == "" {
+		ci.Type = UserTypeNormal
+	}
+	if !ci.Type.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "type must be admin or normal", struct{}{})
+	}
+	return nil
+}
+
+// RevokeInvitation cancels an invitation not accepted yet
+type RevokeInvitation struct {
+	ID    string `json:"id"`
+	Actor string `json:"-"`
+}
+
+func (ri *RevokeInvitation) Validate() *SystemError {
+	if ri.ID == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "id is required", struct{}{})
+	}
+	return nil
+}
+
+// RegisterUser is the public registration, the type and the role are never taken from the caller
+type RegisterUser struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Ignored when registering with an invitation, the invited email is used
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	LastName string `json:"lastName"`
+	Picture  string `json:"picture"`
+	// Token of the invitation, required in RegistrationModeInvite
+	InvitationToken string `json:"invitationToken"`
+}
+
+func (ru *RegisterUser) Validate() *SystemError {
+	if ru.Username == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
+	}
+	if ru.Password == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "password is required", struct{}{})
+	}
+	if ru.Email == "" && ru.InvitationToken == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "email is required", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvitationUsable(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	invitation := Invitation{ExpiresAt: now.Add(InvitationTTL)}
+	if !invitation.Usable(now) {
+		t.Fatal("Expected a fresh invitation to be usable")
+	}
+	if invitation.Usable(now.Add(InvitationTTL)) {
+		t.Fatal("Expected an expired invitation to be refused")
+	}
+	revoked := invitation
+	revoked.RevokedAt = &now
+	if revoked.Usable(now) {
+		t.Fatal("Expected a revoked invitation to be refused")
+	}
+	invitation.UsedAt = &now
+	if invitation.Usable(now) {
+		t.Fatal("Expected an accepted invitation to be refused")
+	}
+}
+
+func TestCreateInvitationValidate(t *testing.T) {
+	invitation := CreateInvitation{Email: "ana@example.com"}
+	if err := invitation.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if invitation.Type != UserTypeNormal {
+		t.Fatalf("Expected the normal type by default, got %q", invitation.Type)
+	}
+	if err := (&CreateInvitation{Email: "ana@example.com", Type: "root"}).Validate(); err == nil {
+		t.Fatal("Expected an unknown type to be refused")
+	}
+	if err := (&CreateInvitation{Email: "ana"}).Validate(); err == nil {
+		t.Fatal("Expected an invalid email to be refused")
+	}
+}
+
+func TestCreateUserRefusesUnknownType(t *testing.T) {
+	user := CreateUser{Username: "ana", Password: "secret", Email: "ana@example.com", Type: "root"}
+	if err := user.Validate(); err == nil {
+		t.Fatal("Expected an unknown type to be refused")
+	}
+	user.Type = UserTypeAdmin
+	if err := user.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestRegistrationModeIsValid(t *testing.T) {
+	for _, mode := range []RegistrationMode{RegistrationModeDisabled, RegistrationModeInvite, RegistrationModeOpen} {
+		if !mode.IsValid() {
+			t.Fatalf("Expected %q to be valid", mode)
+		}
+	}
+	if RegistrationMode("public").IsValid() {
+		t.Fatal("Expected an unknown mode to be refused")
+	}
+}
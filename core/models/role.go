@@ -7,18 +7,34 @@ type Role struct {
 	Name        string       `json:"name"`
 	Description string       `json:"description"`
 	Permissions []Permission `json:"permissions"`
+	// Users of the role must enable MFA before using the application
+	RequireMFA bool `json:"require_mfa"`
+}
+
+var roleSearchFields = SearchFields{
+	"ID":          {Column: "id", Filterable: true, Sortable: true},
+	"Name":        {Column: "name", Filterable: true, Sortable: true},
+	"Description": {Column: "description", Filterable: true},
+	"RequireMFA":  {Column: "require_mfa", Filterable: true},
+}
+
+// SearchFields lists the fields roles can be filtered and sorted on
+func (Role) SearchFields() SearchFields {
+	return roleSearchFields
 }
 
 type CreateRole struct {
 	Name        string       `json:"name"`
 	Description string       `json:"description"`
 	Permissions []Permission `json:"permissions"`
+	RequireMFA  bool         `json:"require_mfa"`
 }
 
 type RoleItem struct {
 	ID          string           `json:"id"`
 	Name        string           `json:"name"`
 	Permissions []PermissionView `json:"permissions"`
+	RequireMFA  bool             `json:"require_mfa"`
 }
 
 func (r *Role) ToRoleItem() *RoleItem {
@@ -30,5 +46,6 @@ func (r *Role) ToRoleItem() *RoleItem {
 		ID:          r.ID,
 		Name:        r.Name,
 		Permissions: rolesList,
+		RequireMFA:  r.RequireMFA,
 	}
 }
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchKind is the kind of record a search result points to
+type SearchKind string
+
+const (
+	SearchKindUser     SearchKind = "user"
+	SearchKindEmployee SearchKind = "employee"
+)
+
+const (
+	// SearchMinLength is the shortest query searched, shorter ones match nearly everyone
+	SearchMinLength = 2
+	// SearchMaxLength bounds the queries searched
+	SearchMaxLength = 100
+	// SearchDefaultLimit is the number of results answered when the search sets no limit
+	SearchDefaultLimit = 20
+	// SearchMaxLimit bounds the results of a search
+	SearchMaxLimit = 50
+)
+
+// PeopleSearch finds users by name, last name, username and email, and employees by name, last name,
+// email, employee number and department. Matching ignores case and accents, "jose garcia" finds
+// José García, and words may be incomplete, "gar" finds García.
+type PeopleSearch struct {
+	Query string `json:"query"`
+	// Kinds narrows the search to users or employees, both when empty
+	Kinds []SearchKind `json:"kinds,omitempty"`
+	Limit int          `json:"limit"`
+	// Permissions of the caller, users need view_users and employees view_employees
+	ActorPermissions []Permission `json:"-"`
+}
+
+func (s *PeopleSearch) Validate() *SystemError {
+	s.Query = strings.TrimSpace(s.Query)
+	length := utf8.RuneCountInString(s.Query)
+	if length < SearchMinLength || length > SearchMaxLength {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "query must have between "+strconv.Itoa(SearchMinLength)+" and "+strconv.Itoa(SearchMaxLength)+" characters", struct{}{})
+	}
+	if len(SearchTerms(s.Query)) == 0 {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "query needs letters or digits", struct{}{})
+	}
+	for _, kind := range s.Kinds {
+		if kind != SearchKindUser && kind != SearchKindEmployee {
+			return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "unknown kind "+string(kind)+", use user or employee", struct{}{})
+		}
+	}
+	if s.Limit < 0 || s.Limit > SearchMaxLimit {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "limit must be between 1 and "+strconv.Itoa(SearchMaxLimit), struct{}{})
+	}
+	return nil
+}
+
+// GetLimit returns the number of results requested, SearchDefaultLimit when none is set
+func (s *PeopleSearch) GetLimit() int {
+	if s.Limit <= 0 {
+		return SearchDefaultLimit
+	}
+	return s.Limit
+}
+
+// Searches reports whether the search includes kind, every kind does when none is set
+func (s *PeopleSearch) Searches(kind SearchKind) bool {
+	if len(s.Kinds) == 0 {
+		return true
+	}
+	for _, k := range s.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchTerms splits a query into lowercase words, anything but letters and digits separates them
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchResult is a user or employee found by a PeopleSearch, results are sorted by Rank, best first
+type SearchResult struct {
+	Kind           SearchKind `json:"kind"`
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	LastName       string     `json:"last_name"`
+	Email          string     `json:"email"`
+	Username       string     `json:"username,omitempty"`
+	EmployeeNumber string     `json:"employee_number,omitempty"`
+	Department     string     `json:"department,omitempty"`
+	Rank           float64    `json:"rank"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "strings"
+
+// SearchField exposes a field of an entity to search queries
+type SearchField struct {
+	// Column of the field, the only name of it that reaches SQL
+	Column     string
+	Filterable bool
+	Sortable   bool
+	// The column can be empty even though the field is not a pointer, as the IDs of optional relations
+	Nullable bool
+}
+
+// SearchFields is the allow-list of the fields search queries can use, by Go field name
+type SearchFields map[string]SearchField
+
+// Searchable entities declare the fields their listings can filter and sort on,
+// filters of entities that are not searchable are refused
+type Searchable interface {
+	SearchFields() SearchFields
+}
+
+// Lookup returns the Go name and the definition of the field named key, in any case
+func (s SearchFields) Lookup(key string) (string, SearchField, bool) {
+	if field, ok := s[key]; ok {
+		return key, field, true
+	}
+	for name, field := range s {
+		if strings.EqualFold(name, key) {
+			return name, field, true
+		}
+	}
+	return "", SearchField{}, false
+}
+
+// SearchFieldsOf returns the allow-list of the entity, empty when it is not searchable
+func SearchFieldsOf(entity any) SearchFields {
+	if searchable, ok := entity.(Searchable); ok {
+		return searchable.SearchFields()
+	}
+	return SearchFields{}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPeopleSearchValidate(t *testing.T) {
+	search := PeopleSearch{Query: "  José García "}
+	if err := search.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err.Message)
+	}
+	if search.Query != "José García" || search.GetLimit() != SearchDefaultLimit {
+		t.Fatalf("Expected the query trimmed and the default limit, got %q %d", search.Query, search.GetLimit())
+	}
+	if !search.Searches(SearchKindUser) || !search.Searches(SearchKindEmployee) {
+		t.Fatal("Expected a search without kinds to search every kind")
+	}
+
+	invalid := map[string]PeopleSearch{
+		"too short":       {Query: "a"},
+		"too long":        {Query: strings.Repeat("a", SearchMaxLength+1)},
+		"without words":   {Query: "@@ --"},
+		"unknown kind":    {Query: "ana", Kinds: []SearchKind{"role"}},
+		"limit too large": {Query: "ana", Limit: SearchMaxLimit + 1},
+	}
+	for name, search := range invalid {
+		if err := search.Validate(); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+}
+
+func TestSearchTermsKeepLettersAndDigits(t *testing.T) {
+	terms := SearchTerms("Núñez, ANA-María & E0042:*")
+	expected := []string{"núñez", "ana", "maría", "e0042"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("Expected %v, got %v", expected, terms)
+	}
+}
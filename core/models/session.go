@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+const (
+	// AccessTokenTTL is the lifetime of the JWT sent on every request
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is the lifetime of a refresh token, every refresh issues a new one
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// RefreshToken is a server side session, only the hash of the token handed to the client is stored.
+// Every refresh revokes the token and issues its replacement in the same family.
+type RefreshToken struct {
+	ID string `json:"id"`
+	// ID of the user owning the session
+	UserID string `json:"user_id"`
+	// ID shared by the tokens descending from the same login
+	FamilyID string `json:"family_id"`
+	// SHA-256 of the token
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	// ID of the token issued when this one was refreshed
+	ReplacedByID string    `json:"replaced_by_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RefreshTokenState string
+
+const (
+	RefreshTokenStateActive  RefreshTokenState = "active"
+	RefreshTokenStateExpired RefreshTokenState = "expired"
+	// RefreshTokenStateReused means a revoked token was presented again, the token was stolen or replayed
+	RefreshTokenStateReused RefreshTokenState = "reused"
+)
+
+// State returns whether the token can still be exchanged at the given time
+func (t *RefreshToken) State(now time.Time) RefreshTokenState {
+	if t.RevokedAt != nil {
+		return RefreshTokenStateReused
+	}
+	if !now.Before(t.ExpiresAt) {
+		return RefreshTokenStateExpired
+	}
+	return RefreshTokenStateActive
+}
+
+// RevokedToken is an access token denied before its expiration
+type RevokedToken struct {
+	// JWT ID of the access token
+	JTI string `json:"jti"`
+	// Expiration of the access token, the entry is useless afterwards
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokenPair is returned on login and refresh
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// Lifetime of the access token in seconds
+	ExpiresIn int `json:"expires_in"`
+}
+
+type RefreshSession struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshSession) Validate() *SystemError {
+	if r.RefreshToken == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "refresh token is required", struct{}{})
+	}
+	return nil
+}
+
+// Session is the result of a login or refresh, the access token is signed by the API
+type Session struct {
+	User         UserData
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// Logout ends the session of the access token and, when given, the refresh token family
+type Logout struct {
+	RefreshToken string    `json:"refresh_token"`
+	JTI          string    `json:"-"`
+	ExpiresAt    time.Time `json:"-"`
+	Username     string    `json:"-"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenState(t *testing.T) {
+	now := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+	token := RefreshToken{ExpiresAt: now.Add(time.Hour)}
+	if token.State(now) != RefreshTokenStateActive {
+		t.Fatal("Expected unexpired tokens to be active")
+	}
+	if token.State(now.Add(time.Hour)) != RefreshTokenStateExpired {
+		t.Fatal("Expected tokens to expire at their expiration time")
+	}
+	revokedAt := now.Add(-time.Minute)
+	token.RevokedAt = &revokedAt
+	if token.State(now) != RefreshTokenStateReused {
+		t.Fatal("Expected presenting a revoked token to be detected as reuse")
+	}
+}
@@ -17,6 +17,7 @@ const (
 const (
 	SystemErrorCodeInternal   SystemErrorCode = 500
 	SystemErrorCodeValidation SystemErrorCode = 400
+	SystemErrorCodeAuth       SystemErrorCode = 401
 	SystemErrorCodeMigration  SystemErrorCode = 404
 	SystemErrorCodeNone       SystemErrorCode = 0
 )
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type UserType string
 
 const (
@@ -17,14 +19,56 @@ type User struct {
 	Type     UserType
 	Active   bool
 	Picture  string
-	Role     string
+	// ID of the role granting the permissions of the user, empty when the user has no role
+	RoleID string
+	// Consecutive failed logins, reset by a successful login or an unlock
+	FailedLoginAttempts int
+	// The user cannot log in before this time
+	LockedUntil *time.Time
+	// Last password change, checked against PasswordPolicy.MaxAge
+	PasswordChangedAt *time.Time
+	// The user can only change its password until it does so
+	MustChangePassword bool
+	// TOTP secret, set on enrollment and only used once MFAEnabled
+	MFASecret  string
+	MFAEnabled bool
+	// Last TOTP time step accepted, a code cannot be used twice
+	MFALastStep int64
+	// When the current Email was verified, nil until the user follows the verification link
+	EmailVerifiedAt *time.Time
+	// OIDCIdentity.ExternalID of the single sign-on identity linked to the user
+	ExternalID string
+	// The user can only sign in through single sign-on
+	LocalLoginDisabled bool
+}
+
+var userSearchFields = SearchFields{
+	"ID":                 {Column: "id", Filterable: true, Sortable: true},
+	"Username":           {Column: "username", Filterable: true, Sortable: true},
+	"Name":               {Column: "name", Filterable: true, Sortable: true},
+	"LastName":           {Column: "last_name", Filterable: true, Sortable: true},
+	"Email":              {Column: "email", Filterable: true, Sortable: true},
+	"Type":               {Column: "type", Filterable: true, Sortable: true},
+	"Active":             {Column: "active", Filterable: true, Sortable: true},
+	"RoleID":             {Column: "role_id", Filterable: true, Nullable: true},
+	"LockedUntil":        {Column: "locked_until", Filterable: true, Sortable: true},
+	"PasswordChangedAt":  {Column: "password_changed_at", Filterable: true, Sortable: true},
+	"MustChangePassword": {Column: "must_change_password", Filterable: true},
+	"MFAEnabled":         {Column: "mfa_enabled", Filterable: true},
+	"EmailVerifiedAt":    {Column: "email_verified_at", Filterable: true, Sortable: true},
+	"LocalLoginDisabled": {Column: "local_login_disabled", Filterable: true},
+}
+
+// SearchFields lists the fields users can be filtered and sorted on
+func (User) SearchFields() SearchFields {
+	return userSearchFields
 }
 
 type CreateUser struct {
 	Username string
 	Password string
 	Email    string
-	Role     string
+	RoleID   string
 	Name     string
 	LastName string
 	Type     UserType
@@ -39,8 +83,10 @@ type ModifyUser struct {
 	Password string
 	Email    string
 	Type     UserType
-	Role     string
+	RoleID   string
 	Picture  string
+	// Refuse the password login of the user, it signs in through single sign-on
+	LocalLoginDisabled bool
 }
 
 type UserData struct {
@@ -51,13 +97,63 @@ type UserData struct {
 	Email    string   `json:"email"`
 	Type     UserType `json:"type"`
 	Picture  string   `json:"picture"`
-	Role     string   `json:"role"`
-	Active   bool     `json:"active"`
+	RoleID   string   `json:"roleId"`
+	// Name of the role, only loaded when the role is resolved
+	Role string `json:"role"`
+	// Names of the permissions granted by the role, only loaded when the role is resolved
+	Permissions []string `json:"permissions,omitempty"`
+	Active      bool     `json:"active"`
+	// The password expired, the session only allows changing it
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+	MFAEnabled         bool `json:"mfaEnabled"`
+	// The role requires MFA and the user has not enabled it, the session only allows enrolling
+	MFASetupRequired bool `json:"mfaSetupRequired,omitempty"`
+	EmailVerified    bool `json:"emailVerified"`
+	// The user signs in through single sign-on only
+	LocalLoginDisabled bool `json:"localLoginDisabled"`
+}
+
+// UpdateProfile holds the fields users can change on their own account
+type UpdateProfile struct {
+	// Username of the authenticated user
+	Username        string `json:"-"`
+	Name            string `json:"name"`
+	LastName        string `json:"lastName"`
+	Picture         string `json:"picture"`
+	CurrentPassword string `json:"currentPassword"`
+	// New password, empty keeps the current one
+	NewPassword string `json:"newPassword"`
+}
+
+func (up *UpdateProfile) Validate() *SystemError {
+	if up.Username == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
+	}
+	if up.Name == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "name is required", struct{}{})
+	}
+	if up.LastName == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "last name is required", struct{}{})
+	}
+	if up.NewPassword != "" && up.CurrentPassword == "" {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "current password is required", struct{}{})
+	}
+	return nil
+}
+
+// ApplyTo copies the profile into the user, the password is encoded by the use case
+func (up *UpdateProfile) ApplyTo(user *User) *User {
+	user.Name = up.Name
+	user.LastName = up.LastName
+	user.Picture = up.Picture
+	return user
 }
 
 type LoginUser struct {
 	Username string
 	Password string
+	// Address of the client, recorded in the audit log
+	IP string `json:"-"`
 }
 
 func (u *User) ToUserData() *UserData {
@@ -69,7 +165,13 @@ func (u *User) ToUserData() *UserData {
 		Email:    u.Email,
 		Type:     u.Type,
 		Picture:  u.Picture,
-		Role:     u.Role,
+		RoleID:   u.RoleID,
+		Active:   u.Active,
+
+		MustChangePassword: u.MustChangePassword,
+		MFAEnabled:         u.MFAEnabled,
+		EmailVerified:      u.EmailVerifiedAt != nil,
+		LocalLoginDisabled: u.LocalLoginDisabled,
 	}
 }
 
@@ -82,22 +184,23 @@ func (cu *CreateUser) ToUser() *User {
 		Name:     cu.Name,
 		LastName: cu.LastName,
 		Picture:  cu.Picture,
-		Role:     cu.Role,
+		RoleID:   cu.RoleID,
 		Active:   true,
 	}
 }
 
 func (mu *ModifyUser) ToUser() *User {
 	return &User{
-		ID:       mu.ID,
-		Username: mu.Username,
-		Name:     mu.Name,
-		LastName: mu.LastName,
-		Password: mu.Password,
-		Email:    mu.Email,
-		Type:     mu.Type,
-		Role:     mu.Role,
-		Picture:  mu.Picture,
+		ID:                 mu.ID,
+		Username:           mu.Username,
+		Name:               mu.Name,
+		LastName:           mu.LastName,
+		Password:           mu.Password,
+		Email:              mu.Email,
+		Type:               mu.Type,
+		RoleID:             mu.RoleID,
+		Picture:            mu.Picture,
+		LocalLoginDisabled: mu.LocalLoginDisabled,
 	}
 }
 
@@ -114,6 +217,9 @@ func (cu *CreateUser) Validate() *SystemError {
 	if cu.Type == "" {
 		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "type is required", struct{}{})
 	}
+	if !cu.Type.IsValid() {
+		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "type must be admin or normal", struct{}{})
+	}
 	return nil
 }
 
@@ -124,9 +230,6 @@ func (mu *ModifyUser) Validate() *SystemError {
 	if mu.Username == "" {
 		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "username is required", struct{}{})
 	}
-	if mu.Password == "" {
-		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "password is required", struct{}{})
-	}
 	if mu.Email == "" {
 		return NewSystemError(SystemErrorCodeValidation, SystemErrorTypeValidation, SystemErrorLevelError, "email is required", struct{}{})
 	}
@@ -141,3 +244,16 @@ func (mu *ModifyUser) Validate() *SystemError {
 	}
 	return nil
 }
+
+// WithRole loads the name and the permission names of the role into the user data
+func (ud *UserData) WithRole(role *Role, permissions []Permission) *UserData {
+	if role != nil {
+		ud.Role = role.Name
+		ud.MFASetupRequired = role.RequireMFA && !ud.MFAEnabled
+	}
+	ud.Permissions = make([]string, len(permissions))
+	for i, permission := range permissions {
+		ud.Permissions[i] = permission.Name
+	}
+	return ud
+}
@@ -0,0 +1,285 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+const (
+	// keyScheme starts every key so leaked keys are easy to recognize in logs and scanners
+	keyScheme = "hrms_"
+	// keyPrefixBytes identify the key in listings, keySecretBytes are its entropy
+	keyPrefixBytes = 4
+	keySecretBytes = 32
+)
+
+// HashKey returns the value stored for an API key
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// newKey returns a key of the form hrms_<prefix>_<secret> and its prefix
+func newKey() (string, string, *models.SystemError) {
+	prefix := make([]byte, keyPrefixBytes)
+	secret := make([]byte, keySecretBytes)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", internalError("could not generate api key")
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", internalError("could not generate api key")
+	}
+	shown := keyScheme + hex.EncodeToString(prefix)
+	return shown + "_" + base64.RawURLEncoding.EncodeToString(secret), shown, nil
+}
+
+// CreateServiceAccountUseCase registers a machine caller, it gets no access until a key is created
+type CreateServiceAccountUseCase struct {
+	accountContract contracts.ServiceAccountContract
+	request         contracts.IGenericRequest[models.CreateServiceAccount]
+}
+
+func NewCreateServiceAccountUseCase(accountContract contracts.ServiceAccountContract, request contracts.IGenericRequest[models.CreateServiceAccount]) *CreateServiceAccountUseCase {
+	return &CreateServiceAccountUseCase{accountContract: accountContract, request: request}
+}
+
+func (u *CreateServiceAccountUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.accountContract.GetOnce("name", request.Name); err == nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La cuenta de servicio ya existe", struct{}{})
+	}
+	return nil
+}
+
+func (u *CreateServiceAccountUseCase) Execute() (*models.ServiceAccount, *models.SystemError) {
+	request := u.request.Build()
+	account, err := u.accountContract.Create(models.ServiceAccount{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedBy:   request.Actor,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+type ListServiceAccountsUseCase struct {
+	accountContract contracts.ServiceAccountContract
+}
+
+func NewListServiceAccountsUseCase(accountContract contracts.ServiceAccountContract) *ListServiceAccountsUseCase {
+	return &ListServiceAccountsUseCase{accountContract: accountContract}
+}
+
+func (u *ListServiceAccountsUseCase) Validate() *models.SystemError {
+	return nil
+}
+
+func (u *ListServiceAccountsUseCase) Execute() ([]models.ServiceAccount, *models.SystemError) {
+	return u.accountContract.All()
+}
+
+// CreateAPIKeyUseCase issues a key scoped to permissions the creator holds, the key is returned only once
+type CreateAPIKeyUseCase struct {
+	keyContract        contracts.APIKeyContract
+	accountContract    contracts.ServiceAccountContract
+	permissionContract contracts.PermissionContract
+	auditContract      contracts.AuditContract
+	request            contracts.IGenericRequest[models.CreateAPIKey]
+}
+
+func NewCreateAPIKeyUseCase(
+	keyContract contracts.APIKeyContract,
+	accountContract contracts.ServiceAccountContract,
+	permissionContract contracts.PermissionContract,
+	auditContract contracts.AuditContract,
+	request contracts.IGenericRequest[models.CreateAPIKey],
+) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{
+		keyContract:        keyContract,
+		accountContract:    accountContract,
+		permissionContract: permissionContract,
+		auditContract:      auditContract,
+		request:            request,
+	}
+}
+
+func (u *CreateAPIKeyUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.accountContract.GetOnce("id", request.ServiceAccountID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La cuenta de servicio no existe", struct{}{})
+	}
+	known, err := u.permissionContract.GetAll()
+	if err != nil {
+		return err
+	}
+	// HasPermission would let all_access match unknown names
+	names := make(map[string]bool, len(known))
+	for _, permission := range known {
+		names[permission.Name] = true
+	}
+	for _, name := range request.Permissions {
+		if !names[name] {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Permiso desconocido: "+name, struct{}{})
+		}
+	}
+	if !models.HasPermission(request.ActorPermissions, request.Permissions...) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "No puede otorgar permisos que no tiene", struct{}{})
+	}
+	return nil
+}
+
+func (u *CreateAPIKeyUseCase) Execute() (*models.CreatedAPIKey, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	key, prefix, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	created, err := u.keyContract.Create(models.APIKey{
+		ServiceAccountID: request.ServiceAccountID,
+		Name:             request.Name,
+		Prefix:           prefix,
+		KeyHash:          HashKey(key),
+		Permissions:      request.Permissions,
+		ExpiresAt:        now.Add(request.TTL()),
+		CreatedBy:        request.Actor,
+		CreatedAt:        now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionAPIKeyCreated,
+		Actor:     request.Actor,
+		Subject:   prefix,
+		Details:   "service_account_id=" + request.ServiceAccountID + " permissions=" + strings.Join(request.Permissions, ","),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIKey{APIKey: created, Key: key}, nil
+}
+
+type ListAPIKeysUseCase struct {
+	keyContract contracts.APIKeyContract
+	request     contracts.IGenericRequest[models.ListAPIKeys]
+}
+
+func NewListAPIKeysUseCase(keyContract contracts.APIKeyContract, request contracts.IGenericRequest[models.ListAPIKeys]) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{keyContract: keyContract, request: request}
+}
+
+func (u *ListAPIKeysUseCase) Validate() *models.SystemError {
+	if u.request.Build().ServiceAccountID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "serviceAccountId is required", struct{}{})
+	}
+	return nil
+}
+
+func (u *ListAPIKeysUseCase) Execute() ([]models.APIKey, *models.SystemError) {
+	return u.keyContract.ByServiceAccount(u.request.Build().ServiceAccountID)
+}
+
+type RevokeAPIKeyUseCase struct {
+	keyContract   contracts.APIKeyContract
+	auditContract contracts.AuditContract
+	request       contracts.IGenericRequest[models.RevokeAPIKey]
+}
+
+func NewRevokeAPIKeyUseCase(keyContract contracts.APIKeyContract, auditContract contracts.AuditContract, request contracts.IGenericRequest[models.RevokeAPIKey]) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{keyContract: keyContract, auditContract: auditContract, request: request}
+}
+
+func (u *RevokeAPIKeyUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *RevokeAPIKeyUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	key, err := u.keyContract.GetOnce("id", request.ID)
+	if err != nil {
+		return notFound()
+	}
+	revoked, err := u.keyContract.Revoke(key.ID, now)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return notFound()
+	}
+	return u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionAPIKeyRevoked,
+		Actor:     request.Actor,
+		Subject:   key.Prefix,
+		IP:        request.IP,
+		Details:   "service_account_id=" + key.ServiceAccountID,
+		CreatedAt: now,
+	})
+}
+
+// AuthenticateAPIKeyUseCase resolves the service account and the permissions of a key sent in models.APIKeyHeader
+type AuthenticateAPIKeyUseCase struct {
+	keyContract     contracts.APIKeyContract
+	accountContract contracts.ServiceAccountContract
+	key             string
+}
+
+func NewAuthenticateAPIKeyUseCase(keyContract contracts.APIKeyContract, accountContract contracts.ServiceAccountContract, key string) *AuthenticateAPIKeyUseCase {
+	return &AuthenticateAPIKeyUseCase{keyContract: keyContract, accountContract: accountContract, key: key}
+}
+
+func (u *AuthenticateAPIKeyUseCase) Validate() *models.SystemError {
+	if !strings.HasPrefix(u.key, keyScheme) {
+		return invalidKey()
+	}
+	return nil
+}
+
+func (u *AuthenticateAPIKeyUseCase) Execute() (*models.APIKeyIdentity, *models.SystemError) {
+	now := time.Now().UTC()
+	key, err := u.keyContract.GetOnce("key_hash", HashKey(u.key))
+	if err != nil || !key.Usable(now) {
+		return nil, invalidKey()
+	}
+	account, err := u.accountContract.GetOnce("id", key.ServiceAccountID)
+	if err != nil {
+		return nil, invalidKey()
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= models.APIKeyLastUsedPrecision {
+		if err := u.keyContract.Touch(key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+	return &models.APIKeyIdentity{ServiceAccount: *account, Key: *key}, nil
+}
+
+func invalidKey() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Clave de API inválida o expirada", struct{}{})
+}
+
+func notFound() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La clave no existe o ya fue revocada", struct{}{})
+}
+
+func internalError(message string) *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, message, struct{}{})
+}
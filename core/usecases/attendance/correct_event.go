@@ -0,0 +1,72 @@
+package attendance
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/employees"
+)
+
+// CorrectAttendanceEventUsecase lets a manager fix the time or type of an event, keeping the original timestamp and the reason.
+// Only the direct manager of the employee or the head of its department can correct an event.
+type CorrectAttendanceEventUsecase struct {
+	repo               contracts.AttendanceContract
+	employeeContract   contracts.EmployeeContract
+	departmentContract contracts.DepartmentContract
+	request            contracts.IGenericRequest[models.CorrectAttendanceEvent]
+}
+
+func NewCorrectAttendanceEventUsecase(repo contracts.AttendanceContract, employeeContract contracts.EmployeeContract, departmentContract contracts.DepartmentContract, request contracts.IGenericRequest[models.CorrectAttendanceEvent]) *CorrectAttendanceEventUsecase {
+	return &CorrectAttendanceEventUsecase{repo: repo, employeeContract: employeeContract, departmentContract: departmentContract, request: request}
+}
+
+func (u *CorrectAttendanceEventUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.Timestamp.After(time.Now().Add(clockSkew)) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "timestamp cannot be in the future", struct{}{})
+	}
+	event, err := u.repo.GetOnce("id", request.EventID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "event not found", struct{}{})
+	}
+	employee, err := u.employeeContract.GetOnce("id", event.EmployeeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if request.CorrectedByID == employee.ID {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employees cannot correct their own attendance", struct{}{})
+	}
+	if !employees.IsManagedBy(*employee, request.CorrectedByID, u.departmentContract) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only the manager of the employee can correct its attendance", struct{}{})
+	}
+	return nil
+}
+
+func (u *CorrectAttendanceEventUsecase) Execute() (*models.AttendanceEvent, *models.SystemError) {
+	request := u.request.Build()
+	event, err := u.repo.GetOnce("id", request.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.Corrected {
+		original := event.Timestamp
+		event.OriginalTimestamp = &original
+	}
+	event.Corrected = true
+	event.Timestamp = request.Timestamp.UTC()
+	if request.Type != "" {
+		event.Type = request.Type
+	}
+	event.CorrectionReason = request.Reason
+	event.CorrectedByID = request.CorrectedByID
+
+	updated, err := u.repo.Update(event.ID, *event)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
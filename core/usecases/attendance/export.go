@@ -0,0 +1,89 @@
+package attendance
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// exportPageSize is the number of employees loaded per page while exporting
+const exportPageSize = 100
+
+// ExportTimesheetsUsecase builds the timesheets of one or every active employee for a period, ready to be exported.
+type ExportTimesheetsUsecase struct {
+	repo             contracts.AttendanceContract
+	employeeContract contracts.EmployeeContract
+	scheduleContract contracts.WorkScheduleContract
+	request          contracts.IGenericRequest[models.TimesheetQuery]
+}
+
+func NewExportTimesheetsUsecase(repo contracts.AttendanceContract, employeeContract contracts.EmployeeContract, scheduleContract contracts.WorkScheduleContract, request contracts.IGenericRequest[models.TimesheetQuery]) *ExportTimesheetsUsecase {
+	return &ExportTimesheetsUsecase{repo: repo, employeeContract: employeeContract, scheduleContract: scheduleContract, request: request}
+}
+
+func (u *ExportTimesheetsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.EmployeeID != "" {
+		if _, err := u.employeeContract.GetOnce("id", request.EmployeeID); err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+		}
+	}
+	if _, err := resolveSchedule(u.scheduleContract, request.ScheduleID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *ExportTimesheetsUsecase) Execute() ([]models.DailyTimesheet, *models.SystemError) {
+	request := u.request.Build()
+	schedule, err := resolveSchedule(u.scheduleContract, request.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	employeeIDs, err := u.employeeIDs(request.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := eventWindow(schedule, request.From, request.To)
+	events, err := u.repo.GetEventsBetween(request.EmployeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	eventsByEmployee := map[string][]models.AttendanceEvent{}
+	for _, event := range events {
+		eventsByEmployee[event.EmployeeID] = append(eventsByEmployee[event.EmployeeID], event)
+	}
+
+	var timesheets []models.DailyTimesheet
+	for _, employeeID := range employeeIDs {
+		timesheets = append(timesheets, schedule.BuildTimesheets(employeeID, eventsByEmployee[employeeID], request.From, request.To)...)
+	}
+	return timesheets, nil
+}
+
+// employeeIDs returns the requested employee or every active employee
+func (u *ExportTimesheetsUsecase) employeeIDs(employeeID string) ([]string, *models.SystemError) {
+	if employeeID != "" {
+		return []string{employeeID}, nil
+	}
+	var ids []string
+	for page := 1; ; page++ {
+		paginatedData, err := u.employeeContract.GetByFilter(models.SearchQuery{
+			Filters:    models.Filters{{Key: "Status", Value: string(models.EmploymentStatusActive)}},
+			Pagination: models.Pagination{Page: page, Limit: exportPageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, employee := range paginatedData.Rows {
+			ids = append(ids, employee.ID)
+		}
+		if page >= paginatedData.TotalPages {
+			return ids, nil
+		}
+	}
+}
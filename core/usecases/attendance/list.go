@@ -0,0 +1,25 @@
+package attendance
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type ListAttendanceEventsUsecase struct {
+	repo    contracts.AttendanceContract
+	request contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListAttendanceEventsUsecase(repo contracts.AttendanceContract, request contracts.IGenericRequest[models.SearchQuery]) *ListAttendanceEventsUsecase {
+	return &ListAttendanceEventsUsecase{repo: repo, request: request}
+}
+
+func (u *ListAttendanceEventsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.AttendanceEvent{})
+}
+
+func (u *ListAttendanceEventsUsecase) Execute() (*models.PaginatedResponse[models.AttendanceEvent], *models.SystemError) {
+	query := u.request.Build()
+	return u.repo.GetByFilter(query)
+}
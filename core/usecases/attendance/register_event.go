@@ -0,0 +1,103 @@
+package attendance
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// clockSkew is the tolerance accepted for timestamps sent by kiosks and integrations
+const clockSkew = time.Minute
+
+// RegisterClockEventUsecase records a clock-in or clock-out of an employee.
+// Web events always use the server time, trusted kiosk and api events may carry the time they were recorded.
+//
+// Example Usage:
+//
+//	request := contracts.NewGenericRequest(models.RegisterClockEvent{
+//		EmployeeID: "employee-id",
+//		Type:       models.ClockEventTypeIn,
+//		Source:     models.ClockSourceWeb,
+//	})
+//	useCase := attendance.NewRegisterClockEventUsecase(attendanceRepo, employeeRepo, request)
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	event, err := useCase.Execute()
+type RegisterClockEventUsecase struct {
+	repo             contracts.AttendanceContract
+	employeeContract contracts.EmployeeContract
+	request          contracts.IGenericRequest[models.RegisterClockEvent]
+}
+
+func NewRegisterClockEventUsecase(repo contracts.AttendanceContract, employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.RegisterClockEvent]) *RegisterClockEventUsecase {
+	return &RegisterClockEventUsecase{repo: repo, employeeContract: employeeContract, request: request}
+}
+
+// Validate ensures the employee is active and that the event keeps the in/out sequence consistent.
+func (u *RegisterClockEventUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.EmployeeID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is required", struct{}{})
+	}
+	employee, err := u.employeeContract.GetOnce("id", request.EmployeeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if employee.Status != models.EmploymentStatusActive {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only active employees can clock", struct{}{})
+	}
+
+	timestamp := u.timestamp()
+	if timestamp.After(time.Now().Add(clockSkew)) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "timestamp cannot be in the future", struct{}{})
+	}
+
+	last, err := u.repo.GetLastEvent(request.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if last != nil && !timestamp.After(last.Timestamp) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "timestamp must be after the last registered event", struct{}{})
+	}
+	switch request.Type {
+	case models.ClockEventTypeIn:
+		if last != nil && last.Type == models.ClockEventTypeIn {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is already clocked in", struct{}{})
+		}
+	case models.ClockEventTypeOut:
+		if last == nil || last.Type == models.ClockEventTypeOut {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is not clocked in", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *RegisterClockEventUsecase) Execute() (*models.AttendanceEvent, *models.SystemError) {
+	request := u.request.Build()
+	event, err := u.repo.Create(models.AttendanceEvent{
+		EmployeeID: request.EmployeeID,
+		Type:       request.Type,
+		Source:     request.Source,
+		Timestamp:  u.timestamp(),
+		Note:       request.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// timestamp returns the instant of the event, the server time unless a trusted device sent its own.
+// The web value is truncated to the second so Validate and Execute agree on it.
+func (u *RegisterClockEventUsecase) timestamp() time.Time {
+	request := u.request.Build()
+	if request.Trusted && request.Source != models.ClockSourceWeb && request.Timestamp != nil {
+		return request.Timestamp.UTC()
+	}
+	return time.Now().UTC().Truncate(time.Second)
+}
@@ -0,0 +1,63 @@
+package attendance
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// SaveWorkScheduleUsecase creates a work schedule, or updates it when the request carries an ID.
+type SaveWorkScheduleUsecase struct {
+	scheduleContract contracts.WorkScheduleContract
+	request          contracts.IGenericRequest[models.WorkSchedule]
+}
+
+func NewSaveWorkScheduleUsecase(scheduleContract contracts.WorkScheduleContract, request contracts.IGenericRequest[models.WorkSchedule]) *SaveWorkScheduleUsecase {
+	return &SaveWorkScheduleUsecase{scheduleContract: scheduleContract, request: request}
+}
+
+func (u *SaveWorkScheduleUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.ID != "" {
+		if _, err := u.scheduleContract.GetOnce("id", request.ID); err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "work schedule not found", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *SaveWorkScheduleUsecase) Execute() (*models.WorkSchedule, *models.SystemError) {
+	request := u.request.Build()
+	var schedule models.WorkSchedule
+	var err *models.SystemError
+	if request.ID == "" {
+		schedule, err = u.scheduleContract.Create(request)
+	} else {
+		schedule, err = u.scheduleContract.Update(request.ID, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+type ListWorkSchedulesUsecase struct {
+	scheduleContract contracts.WorkScheduleContract
+	request          contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListWorkSchedulesUsecase(scheduleContract contracts.WorkScheduleContract, request contracts.IGenericRequest[models.SearchQuery]) *ListWorkSchedulesUsecase {
+	return &ListWorkSchedulesUsecase{scheduleContract: scheduleContract, request: request}
+}
+
+func (u *ListWorkSchedulesUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.WorkSchedule{})
+}
+
+func (u *ListWorkSchedulesUsecase) Execute() (*models.PaginatedResponse[models.WorkSchedule], *models.SystemError) {
+	query := u.request.Build()
+	return u.scheduleContract.GetByFilter(query)
+}
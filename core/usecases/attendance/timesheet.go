@@ -0,0 +1,78 @@
+package attendance
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// GetTimesheetUsecase builds the daily timesheets of an employee evaluated against a work schedule.
+type GetTimesheetUsecase struct {
+	repo             contracts.AttendanceContract
+	employeeContract contracts.EmployeeContract
+	scheduleContract contracts.WorkScheduleContract
+	request          contracts.IGenericRequest[models.TimesheetQuery]
+}
+
+func NewGetTimesheetUsecase(repo contracts.AttendanceContract, employeeContract contracts.EmployeeContract, scheduleContract contracts.WorkScheduleContract, request contracts.IGenericRequest[models.TimesheetQuery]) *GetTimesheetUsecase {
+	return &GetTimesheetUsecase{repo: repo, employeeContract: employeeContract, scheduleContract: scheduleContract, request: request}
+}
+
+func (u *GetTimesheetUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.EmployeeID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is required", struct{}{})
+	}
+	if _, err := u.employeeContract.GetOnce("id", request.EmployeeID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if _, err := resolveSchedule(u.scheduleContract, request.ScheduleID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *GetTimesheetUsecase) Execute() ([]models.DailyTimesheet, *models.SystemError) {
+	request := u.request.Build()
+	schedule, err := resolveSchedule(u.scheduleContract, request.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	from, to := eventWindow(schedule, request.From, request.To)
+	events, err := u.repo.GetEventsBetween(request.EmployeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.BuildTimesheets(request.EmployeeID, events, request.From, request.To), nil
+}
+
+// resolveSchedule returns the requested schedule or the default one when scheduleID is empty
+func resolveSchedule(scheduleContract contracts.WorkScheduleContract, scheduleID string) (*models.WorkSchedule, *models.SystemError) {
+	if scheduleID == "" {
+		schedule, err := scheduleContract.GetDefault()
+		if err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "no default work schedule configured", struct{}{})
+		}
+		return schedule, nil
+	}
+	schedule, err := scheduleContract.GetOnce("id", scheduleID)
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "work schedule not found", struct{}{})
+	}
+	return schedule, nil
+}
+
+// eventWindow widens the requested range to whole days in the schedule timezone,
+// plus one extra day so clock-outs of night shifts started on the last day are included
+func eventWindow(schedule *models.WorkSchedule, from, to time.Time) (time.Time, time.Time) {
+	loc := schedule.Location()
+	from = from.In(loc)
+	to = to.In(loc)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 2)
+	return start, end
+}
@@ -0,0 +1,33 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type CreateDepartmentUsecase struct {
+	repo             contracts.DepartmentContract
+	employeeContract contracts.EmployeeContract
+	request          contracts.IGenericRequest[models.CreateDepartment]
+}
+
+func NewCreateDepartmentUsecase(repo contracts.DepartmentContract, employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.CreateDepartment]) *CreateDepartmentUsecase {
+	return &CreateDepartmentUsecase{repo: repo, employeeContract: employeeContract, request: request}
+}
+
+func (u *CreateDepartmentUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return validateDepartment(*request.ToDepartment(), u.repo, u.employeeContract)
+}
+
+func (u *CreateDepartmentUsecase) Execute() (*models.Department, *models.SystemError) {
+	request := u.request.Build()
+	department, err := u.repo.Create(*request.ToDepartment())
+	if err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
@@ -0,0 +1,67 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// DeleteDepartmentUsecase removes a department that is no longer in use.
+// Departments that still have sub-departments, positions or employees assigned cannot be deleted.
+type DeleteDepartmentUsecase struct {
+	repo             contracts.DepartmentContract
+	employeeContract contracts.EmployeeContract
+	positionContract contracts.PositionContract
+	departmentID     string
+}
+
+func NewDeleteDepartmentUsecase(repo contracts.DepartmentContract, employeeContract contracts.EmployeeContract, positionContract contracts.PositionContract, departmentID string) *DeleteDepartmentUsecase {
+	return &DeleteDepartmentUsecase{repo: repo, employeeContract: employeeContract, positionContract: positionContract, departmentID: departmentID}
+}
+
+func (u *DeleteDepartmentUsecase) Validate() *models.SystemError {
+	if u.departmentID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Department ID is required", nil)
+	}
+	if _, err := u.repo.GetOnce("id", u.departmentID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department not found", nil)
+	}
+
+	children, err := u.repo.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "ParentID", Value: u.departmentID}},
+	})
+	if err != nil {
+		return err
+	}
+	if children.TotalRows > 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department still has sub-departments", nil)
+	}
+
+	positions, err := u.positionContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "DepartmentID", Value: u.departmentID}},
+	})
+	if err != nil {
+		return err
+	}
+	if positions.TotalRows > 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department still has positions", nil)
+	}
+
+	employees, err := u.employeeContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "DepartmentID", Value: u.departmentID}},
+	})
+	if err != nil {
+		return err
+	}
+	if employees.TotalRows > 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department still has employees", nil)
+	}
+	return nil
+}
+
+func (u *DeleteDepartmentUsecase) Execute() *models.SystemError {
+	_, err := u.repo.Delete(u.departmentID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, err.Error(), nil)
+	}
+	return nil
+}
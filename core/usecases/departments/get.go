@@ -0,0 +1,26 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type GetDepartmentUsecase struct {
+	repo         contracts.DepartmentContract
+	departmentID string
+}
+
+func NewGetDepartmentUsecase(repo contracts.DepartmentContract, departmentID string) *GetDepartmentUsecase {
+	return &GetDepartmentUsecase{repo: repo, departmentID: departmentID}
+}
+
+func (u *GetDepartmentUsecase) Validate() *models.SystemError {
+	if u.departmentID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Department ID is required", nil)
+	}
+	return nil
+}
+
+func (u *GetDepartmentUsecase) Execute() (*models.Department, *models.SystemError) {
+	return u.repo.GetOnce("id", u.departmentID)
+}
@@ -0,0 +1,25 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type ListDepartmentsUsecase struct {
+	repo    contracts.DepartmentContract
+	request contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListDepartmentsUsecase(repo contracts.DepartmentContract, request contracts.IGenericRequest[models.SearchQuery]) *ListDepartmentsUsecase {
+	return &ListDepartmentsUsecase{repo: repo, request: request}
+}
+
+func (u *ListDepartmentsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.Department{})
+}
+
+func (u *ListDepartmentsUsecase) Execute() (*models.PaginatedResponse[models.Department], *models.SystemError) {
+	query := u.request.Build()
+	return u.repo.GetByFilter(query)
+}
@@ -0,0 +1,36 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type UpdateDepartmentUsecase struct {
+	repo             contracts.DepartmentContract
+	employeeContract contracts.EmployeeContract
+	request          contracts.IGenericRequest[models.Department]
+}
+
+func NewUpdateDepartmentUsecase(repo contracts.DepartmentContract, employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.Department]) *UpdateDepartmentUsecase {
+	return &UpdateDepartmentUsecase{repo: repo, employeeContract: employeeContract, request: request}
+}
+
+func (u *UpdateDepartmentUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.repo.GetOnce("id", request.ID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department not found", struct{}{})
+	}
+	return validateDepartment(request, u.repo, u.employeeContract)
+}
+
+func (u *UpdateDepartmentUsecase) Execute() (*models.Department, *models.SystemError) {
+	request := u.request.Build()
+	department, err := u.repo.Update(request.ID, request)
+	if err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
@@ -0,0 +1,59 @@
+package departments
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// maxHierarchyDepth bounds the walk up the department tree when looking for cycles
+const maxHierarchyDepth = 50
+
+// validateDepartment checks name uniqueness, the parent hierarchy and the head of department
+func validateDepartment(
+	department models.Department,
+	departmentContract contracts.DepartmentContract,
+	employeeContract contracts.EmployeeContract,
+) *models.SystemError {
+	paginatedData, err := departmentContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{
+			{
+				Key:   "Name",
+				Value: department.Name,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range paginatedData.Rows {
+		if row.ID != department.ID {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department already exists", struct{}{})
+		}
+	}
+
+	parentID := department.ParentID
+	for depth := 0; parentID != ""; depth++ {
+		if department.ID != "" && parentID == department.ID {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "parent department creates a cycle", struct{}{})
+		}
+		if depth >= maxHierarchyDepth {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department hierarchy is too deep", struct{}{})
+		}
+		parent, err := departmentContract.GetOnce("id", parentID)
+		if err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "parent department not found", struct{}{})
+		}
+		parentID = parent.ParentID
+	}
+
+	if department.HeadEmployeeID != "" {
+		head, err := employeeContract.GetOnce("id", department.HeadEmployeeID)
+		if err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "head of department not found", struct{}{})
+		}
+		if head.Status == models.EmploymentStatusTerminated {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "head of department is terminated", struct{}{})
+		}
+	}
+	return nil
+}
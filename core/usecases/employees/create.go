@@ -0,0 +1,67 @@
+package employees
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// CreateEmployeeUseCase handles the registration of a new employee.
+// It validates the employee data and its references (user, manager, department, position) before persisting it.
+//
+// Example Usage:
+//
+//	request := contracts.NewGenericRequest(models.CreateEmployee{
+//		EmployeeNumber: "E-001",
+//		FirstName:      "John",
+//		LastName:       "Doe",
+//		NationalID:     "001-0000000-1",
+//		HireDate:       time.Now(),
+//	})
+//	useCase := employees.NewCreateEmployeeUseCase(employeeRepo, userRepo, departmentRepo, positionRepo, request)
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	employee, err := useCase.Execute()
+type CreateEmployeeUseCase struct {
+	employeeContract   contracts.EmployeeContract
+	userContract       contracts.UserContract
+	departmentContract contracts.DepartmentContract
+	positionContract   contracts.PositionContract
+	request            contracts.IGenericRequest[models.CreateEmployee]
+}
+
+// NewCreateEmployeeUseCase creates a new instance of CreateEmployeeUseCase.
+func NewCreateEmployeeUseCase(
+	employeeContract contracts.EmployeeContract,
+	userContract contracts.UserContract,
+	departmentContract contracts.DepartmentContract,
+	positionContract contracts.PositionContract,
+	request contracts.IGenericRequest[models.CreateEmployee],
+) *CreateEmployeeUseCase {
+	return &CreateEmployeeUseCase{
+		employeeContract:   employeeContract,
+		userContract:       userContract,
+		departmentContract: departmentContract,
+		positionContract:   positionContract,
+		request:            request,
+	}
+}
+
+// Validate ensures the employee data is complete and that its references exist.
+func (u *CreateEmployeeUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return validateReferences(*request.ToEmployee(), "", u.employeeContract, u.userContract, u.departmentContract, u.positionContract)
+}
+
+// Execute persists the new employee with an active status.
+func (u *CreateEmployeeUseCase) Execute() (*models.Employee, *models.SystemError) {
+	request := u.request.Build()
+	employee, err := u.employeeContract.Create(*request.ToEmployee())
+	if err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
@@ -0,0 +1,30 @@
+package employees
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type GetEmployeeUseCase struct {
+	employeeContract contracts.EmployeeContract
+	employeeID       string
+}
+
+func NewGetEmployeeUseCase(employeeContract contracts.EmployeeContract, employeeID string) *GetEmployeeUseCase {
+	return &GetEmployeeUseCase{employeeContract: employeeContract, employeeID: employeeID}
+}
+
+func (u *GetEmployeeUseCase) Validate() *models.SystemError {
+	if u.employeeID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Employee ID is required", nil)
+	}
+	return nil
+}
+
+func (u *GetEmployeeUseCase) Execute() (*models.Employee, *models.SystemError) {
+	employee, err := u.employeeContract.GetOnce("id", u.employeeID)
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", nil)
+	}
+	return employee, nil
+}
@@ -0,0 +1,46 @@
+package employees
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// GetCurrentEmployeeUseCase resolves the employee linked to an authenticated username.
+type GetCurrentEmployeeUseCase struct {
+	employeeContract contracts.EmployeeContract
+	userContract     contracts.UserContract
+	username         string
+}
+
+func NewGetCurrentEmployeeUseCase(employeeContract contracts.EmployeeContract, userContract contracts.UserContract, username string) *GetCurrentEmployeeUseCase {
+	return &GetCurrentEmployeeUseCase{employeeContract: employeeContract, userContract: userContract, username: username}
+}
+
+func (u *GetCurrentEmployeeUseCase) Validate() *models.SystemError {
+	if u.username == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "username is required", nil)
+	}
+	return nil
+}
+
+func (u *GetCurrentEmployeeUseCase) Execute() (*models.Employee, *models.SystemError) {
+	users, err := u.userContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "Username", Value: u.username}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(users.Rows) == 0 {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "user not found", nil)
+	}
+	employees, err := u.employeeContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "UserID", Value: users.Rows[0].ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(employees.Rows) == 0 {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the user is not linked to an employee", nil)
+	}
+	return &employees.Rows[0], nil
+}
@@ -0,0 +1,35 @@
+package employees
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// ListEmployeesUseCase handles the retrieval of employees based on filters and pagination.
+type ListEmployeesUseCase struct {
+	employeeContract contracts.EmployeeContract
+	request          contracts.IGenericRequest[models.SearchQuery]
+}
+
+// NewListEmployeesUseCase creates a new instance of ListEmployeesUseCase.
+func NewListEmployeesUseCase(employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.SearchQuery]) *ListEmployeesUseCase {
+	return &ListEmployeesUseCase{
+		employeeContract: employeeContract,
+		request:          request,
+	}
+}
+
+// Validate ensures that the request filters match the Employee model.
+func (u *ListEmployeesUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(models.Employee{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute returns the page of employees matching the filters.
+func (u *ListEmployeesUseCase) Execute() (*models.PaginatedResponse[models.Employee], *models.SystemError) {
+	query := u.request.Build()
+	return u.employeeContract.GetByFilter(query)
+}
@@ -0,0 +1,96 @@
+package employees
+
+import (
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// ModifyEmployeeUseCase handles the modification of an existing employee.
+// Terminated employees cannot be modified and termination must go through TerminateEmployeeUseCase.
+// The bank account the salary is paid into only changes for callers with manage_payroll, every change is audited.
+type ModifyEmployeeUseCase struct {
+	employeeContract   contracts.EmployeeContract
+	userContract       contracts.UserContract
+	departmentContract contracts.DepartmentContract
+	positionContract   contracts.PositionContract
+	auditContract      contracts.AuditContract
+	request            contracts.IGenericRequest[models.ModifyEmployee]
+}
+
+// NewModifyEmployeeUseCase creates a new instance of ModifyEmployeeUseCase.
+func NewModifyEmployeeUseCase(
+	employeeContract contracts.EmployeeContract,
+	userContract contracts.UserContract,
+	departmentContract contracts.DepartmentContract,
+	positionContract contracts.PositionContract,
+	auditContract contracts.AuditContract,
+	request contracts.IGenericRequest[models.ModifyEmployee],
+) *ModifyEmployeeUseCase {
+	return &ModifyEmployeeUseCase{
+		employeeContract:   employeeContract,
+		userContract:       userContract,
+		departmentContract: departmentContract,
+		positionContract:   positionContract,
+		auditContract:      auditContract,
+		request:            request,
+	}
+}
+
+// Validate ensures the employee exists, is still employed and that the new references are valid.
+func (u *ModifyEmployeeUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	existing, err := u.employeeContract.GetOnce("id", request.ID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if existing.Status == models.EmploymentStatusTerminated {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "terminated employees cannot be modified", struct{}{})
+	}
+	if request.ChangesBankAccount(existing) && !models.HasPermission(request.ActorPermissions, models.PermissionManagePayroll) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "changing the bank account requires the "+models.PermissionManagePayroll+" permission", struct{}{})
+	}
+	previousPositionID := existing.PositionID
+	return validateReferences(*request.ApplyTo(existing), previousPositionID, u.employeeContract, u.userContract, u.departmentContract, u.positionContract)
+}
+
+// Execute applies the changes over the stored employee and persists it.
+func (u *ModifyEmployeeUseCase) Execute() (*models.Employee, *models.SystemError) {
+	request := u.request.Build()
+	existing, err := u.employeeContract.GetOnce("id", request.ID)
+	if err != nil {
+		return nil, err
+	}
+	changesBankAccount := request.ChangesBankAccount(existing)
+	previous := maskAccount(existing.BankName, existing.BankAccount)
+	employee, err := u.employeeContract.Update(request.ID, *request.ApplyTo(existing))
+	if err != nil {
+		return nil, err
+	}
+	if changesBankAccount {
+		if err := u.auditContract.Record(models.AuditEntry{
+			Action:    models.AuditActionBankAccountChanged,
+			Actor:     request.Actor,
+			Subject:   employee.EmployeeNumber,
+			IP:        request.IP,
+			Details:   "employee_id=" + employee.ID + " from=" + previous + " to=" + maskAccount(employee.BankName, employee.BankAccount),
+			CreatedAt: time.Now().UTC(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &employee, nil
+}
+
+// maskAccount keeps the bank and the last four digits of the account, enough to tell accounts apart in the audit log
+func maskAccount(bank, account string) string {
+	if len(account) > 4 {
+		account = strings.Repeat("*", len(account)-4) + account[len(account)-4:]
+	}
+	return "\"" + strings.TrimSpace(bank+" "+account) + "\""
+}
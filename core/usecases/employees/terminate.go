@@ -0,0 +1,81 @@
+package employees
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// TerminateEmployeeUseCase ends the employment of an employee.
+// The employee record is kept for history, and the linked user account (if any) is deactivated
+// and signed out of every session.
+type TerminateEmployeeUseCase struct {
+	employeeContract contracts.EmployeeContract
+	userContract     contracts.UserContract
+	refreshContract  contracts.RefreshTokenContract
+	request          contracts.IGenericRequest[models.TerminateEmployee]
+}
+
+// NewTerminateEmployeeUseCase creates a new instance of TerminateEmployeeUseCase.
+func NewTerminateEmployeeUseCase(employeeContract contracts.EmployeeContract, userContract contracts.UserContract, refreshContract contracts.RefreshTokenContract, request contracts.IGenericRequest[models.TerminateEmployee]) *TerminateEmployeeUseCase {
+	return &TerminateEmployeeUseCase{
+		employeeContract: employeeContract,
+		userContract:     userContract,
+		refreshContract:  refreshContract,
+		request:          request,
+	}
+}
+
+// Validate ensures the employee exists, is not already terminated and the termination date is after the hire date.
+func (u *TerminateEmployeeUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	employee, err := u.employeeContract.GetOnce("id", request.ID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if employee.Status == models.EmploymentStatusTerminated {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is already terminated", struct{}{})
+	}
+	if request.TerminationDate.Before(employee.HireDate) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "termination date cannot be before the hire date", struct{}{})
+	}
+	return nil
+}
+
+// Execute marks the employee as terminated, deactivates its user account and revokes its refresh tokens.
+func (u *TerminateEmployeeUseCase) Execute() (*models.Employee, *models.SystemError) {
+	request := u.request.Build()
+	employee, err := u.employeeContract.GetOnce("id", request.ID)
+	if err != nil {
+		return nil, err
+	}
+	terminationDate := request.TerminationDate
+	employee.Status = models.EmploymentStatusTerminated
+	employee.TerminationDate = &terminationDate
+	employee.TerminationReason = request.Reason
+
+	updated, err := u.employeeContract.Update(employee.ID, *employee)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.UserID != "" {
+		user, err := u.userContract.GetOnce("id", updated.UserID)
+		if err == nil && user.Active {
+			user.Active = false
+			// The password is kept by the repository when left empty
+			user.Password = ""
+			if _, err := u.userContract.Update(user.ID, *user); err != nil {
+				return nil, err
+			}
+			if err := u.refreshContract.RevokeByUser(user.ID, time.Now().UTC()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &updated, nil
+}
@@ -0,0 +1,169 @@
+package employees
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// maxManagerDepth bounds the walk up the management chain when looking for cycles
+const maxManagerDepth = 50
+
+// isTaken reports whether another employee (different from excludeID) already uses value in the field key
+func isTaken(employeeContract contracts.EmployeeContract, key string, value any, excludeID string) (bool, *models.SystemError) {
+	paginatedData, err := employeeContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{
+			{
+				Key:   key,
+				Value: value,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, row := range paginatedData.Rows {
+		if row.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateReferences checks that every optional reference of the employee points to an existing record.
+// previousPositionID is the position held before the change, so the headcount budget is only checked on new assignments.
+func validateReferences(
+	employee models.Employee,
+	previousPositionID string,
+	employeeContract contracts.EmployeeContract,
+	userContract contracts.UserContract,
+	departmentContract contracts.DepartmentContract,
+	positionContract contracts.PositionContract,
+) *models.SystemError {
+	taken, err := isTaken(employeeContract, "EmployeeNumber", employee.EmployeeNumber, employee.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee number already exists", struct{}{})
+	}
+
+	taken, err = isTaken(employeeContract, "NationalID", employee.NationalID, employee.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "national id already exists", struct{}{})
+	}
+
+	if employee.UserID != "" {
+		if _, err := userContract.GetOnce("id", employee.UserID); err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "user not found", struct{}{})
+		}
+		taken, err := isTaken(employeeContract, "UserID", employee.UserID, employee.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "user is already linked to another employee", struct{}{})
+		}
+	}
+
+	if employee.DepartmentID != "" {
+		if _, err := departmentContract.GetOnce("id", employee.DepartmentID); err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department not found", struct{}{})
+		}
+	}
+
+	if employee.PositionID != "" {
+		if err := validatePosition(employee, previousPositionID, employeeContract, positionContract); err != nil {
+			return err
+		}
+	}
+
+	if employee.ManagerID != "" {
+		return validateManager(employee, employeeContract)
+	}
+	return nil
+}
+
+// validatePosition checks that the position exists, belongs to the employee department, pays the salary
+// of the employee inside its band and has headcount available
+func validatePosition(employee models.Employee, previousPositionID string, employeeContract contracts.EmployeeContract, positionContract contracts.PositionContract) *models.SystemError {
+	position, err := positionContract.GetOnce("id", employee.PositionID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position not found", struct{}{})
+	}
+	if employee.DepartmentID != "" && position.DepartmentID != employee.DepartmentID {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position does not belong to the department", struct{}{})
+	}
+	if !position.IsSalaryInBand(employee.Salary) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "salary is outside the salary band of the position", struct{}{})
+	}
+	if position.Headcount == 0 || employee.PositionID == previousPositionID {
+		return nil
+	}
+	assigned, err := CountActiveInPosition(employeeContract, employee.PositionID)
+	if err != nil {
+		return err
+	}
+	if assigned >= int64(position.Headcount) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position headcount budget is exhausted", struct{}{})
+	}
+	return nil
+}
+
+// validateManager checks that the manager exists, is not terminated and that assigning it does not create a cycle
+func validateManager(employee models.Employee, employeeContract contracts.EmployeeContract) *models.SystemError {
+	managerID := employee.ManagerID
+	for depth := 0; managerID != ""; depth++ {
+		if employee.ID != "" && managerID == employee.ID {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "manager assignment creates a cycle", struct{}{})
+		}
+		if depth >= maxManagerDepth {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "management chain is too deep", struct{}{})
+		}
+		manager, err := employeeContract.GetOnce("id", managerID)
+		if err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "manager not found", struct{}{})
+		}
+		if depth == 0 && manager.Status == models.EmploymentStatusTerminated {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "manager is terminated", struct{}{})
+		}
+		managerID = manager.ManagerID
+	}
+	return nil
+}
+
+// CountActiveInPosition returns how many non terminated employees currently hold the position
+func CountActiveInPosition(employeeContract contracts.EmployeeContract, positionID string) (int64, *models.SystemError) {
+	var assigned int64
+	for _, status := range []models.EmploymentStatus{models.EmploymentStatusActive, models.EmploymentStatusOnLeave, models.EmploymentStatusSuspended} {
+		paginatedData, err := employeeContract.GetByFilter(models.SearchQuery{
+			Filters: models.Filters{
+				{Key: "PositionID", Value: positionID},
+				{Key: "Status", Value: string(status)},
+			},
+			Pagination: models.Pagination{Page: 1, Limit: 1},
+		})
+		if err != nil {
+			return 0, err
+		}
+		assigned += paginatedData.TotalRows
+	}
+	return assigned, nil
+}
+
+// IsManagedBy reports whether managerID is the direct manager of the employee or the head of its department
+func IsManagedBy(employee models.Employee, managerID string, departmentContract contracts.DepartmentContract) bool {
+	if managerID == "" || managerID == employee.ID {
+		return false
+	}
+	if employee.ManagerID == managerID {
+		return true
+	}
+	if employee.DepartmentID == "" {
+		return false
+	}
+	department, err := departmentContract.GetOnce("id", employee.DepartmentID)
+	return err == nil && department.HeadEmployeeID == managerID
+}
@@ -0,0 +1,63 @@
+package leaves
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// GetLeaveBalancesUsecase returns the balance of every active leave type for an employee in a year.
+type GetLeaveBalancesUsecase struct {
+	repo              contracts.LeaveRequestContract
+	leaveTypeContract contracts.LeaveTypeContract
+	employeeContract  contracts.EmployeeContract
+	request           contracts.IGenericRequest[models.LeaveBalanceQuery]
+}
+
+func NewGetLeaveBalancesUsecase(repo contracts.LeaveRequestContract, leaveTypeContract contracts.LeaveTypeContract, employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.LeaveBalanceQuery]) *GetLeaveBalancesUsecase {
+	return &GetLeaveBalancesUsecase{repo: repo, leaveTypeContract: leaveTypeContract, employeeContract: employeeContract, request: request}
+}
+
+func (u *GetLeaveBalancesUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if request.EmployeeID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee is required", struct{}{})
+	}
+	if request.Year < 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid year", struct{}{})
+	}
+	if _, err := u.employeeContract.GetOnce("id", request.EmployeeID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	return nil
+}
+
+func (u *GetLeaveBalancesUsecase) Execute() ([]models.LeaveBalance, *models.SystemError) {
+	request := u.request.Build()
+	year := request.Year
+	if year == 0 {
+		year = time.Now().UTC().Year()
+	}
+	employee, err := u.employeeContract.GetOnce("id", request.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	leaveTypes, err := u.leaveTypeContract.GetByFilter(models.SearchQuery{
+		Filters:    models.Filters{{Key: "Active", Value: true}},
+		Pagination: models.Pagination{Page: 1, Limit: maxLeaveTypes},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]models.LeaveBalance, 0, len(leaveTypes.Rows))
+	for _, leaveType := range leaveTypes.Rows {
+		balance, err := buildBalance(u.repo, leaveType, *employee, year)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
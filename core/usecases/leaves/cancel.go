@@ -0,0 +1,70 @@
+package leaves
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/employees"
+)
+
+// CancelLeaveRequestUsecase cancels a pending request, or an approved one that has not started yet.
+// The employee who owns the request and its manager can cancel it.
+type CancelLeaveRequestUsecase struct {
+	repo               contracts.LeaveRequestContract
+	employeeContract   contracts.EmployeeContract
+	departmentContract contracts.DepartmentContract
+	request            contracts.IGenericRequest[models.CancelLeaveRequest]
+}
+
+func NewCancelLeaveRequestUsecase(repo contracts.LeaveRequestContract, employeeContract contracts.EmployeeContract, departmentContract contracts.DepartmentContract, request contracts.IGenericRequest[models.CancelLeaveRequest]) *CancelLeaveRequestUsecase {
+	return &CancelLeaveRequestUsecase{repo: repo, employeeContract: employeeContract, departmentContract: departmentContract, request: request}
+}
+
+func (u *CancelLeaveRequestUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	leaveRequest, err := u.repo.GetOnce("id", request.RequestID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave request not found", struct{}{})
+	}
+	if !leaveRequest.Status.CanTransitionTo(models.LeaveStatusCancelled) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the request can no longer be cancelled", struct{}{})
+	}
+	if leaveRequest.Status == models.LeaveStatusApproved && !time.Now().UTC().Before(leaveRequest.StartDate) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "an approved leave cannot be cancelled once it has started", struct{}{})
+	}
+	if leaveRequest.EmployeeID == request.CancelledBy {
+		return nil
+	}
+	employee, err := u.employeeContract.GetOnce("id", leaveRequest.EmployeeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if !employees.IsManagedBy(*employee, request.CancelledBy, u.departmentContract) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only the employee or its manager can cancel the request", struct{}{})
+	}
+	return nil
+}
+
+func (u *CancelLeaveRequestUsecase) Execute() (*models.LeaveRequest, *models.SystemError) {
+	request := u.request.Build()
+	leaveRequest, err := u.repo.GetOnce("id", request.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	from := leaveRequest.Status
+	leaveRequest.Status = models.LeaveStatusCancelled
+	if request.Reason != "" {
+		leaveRequest.DecisionNote = request.Reason
+	}
+
+	// a request decided or cancelled since it was read keeps its status
+	updated, err := u.repo.Transition(*leaveRequest, from)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
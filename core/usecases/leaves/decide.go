@@ -0,0 +1,92 @@
+package leaves
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/employees"
+)
+
+// DecideLeaveRequestUsecase approves or rejects a pending request.
+// Only the direct manager of the employee or the head of its department can decide.
+type DecideLeaveRequestUsecase struct {
+	repo               contracts.LeaveRequestContract
+	leaveTypeContract  contracts.LeaveTypeContract
+	employeeContract   contracts.EmployeeContract
+	departmentContract contracts.DepartmentContract
+	request            contracts.IGenericRequest[models.DecideLeaveRequest]
+}
+
+func NewDecideLeaveRequestUsecase(repo contracts.LeaveRequestContract, leaveTypeContract contracts.LeaveTypeContract, employeeContract contracts.EmployeeContract, departmentContract contracts.DepartmentContract, request contracts.IGenericRequest[models.DecideLeaveRequest]) *DecideLeaveRequestUsecase {
+	return &DecideLeaveRequestUsecase{repo: repo, leaveTypeContract: leaveTypeContract, employeeContract: employeeContract, departmentContract: departmentContract, request: request}
+}
+
+func (u *DecideLeaveRequestUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	leaveRequest, err := u.repo.GetOnce("id", request.RequestID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave request not found", struct{}{})
+	}
+	if !leaveRequest.Status.CanTransitionTo(request.Status()) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only pending requests can be approved or rejected", struct{}{})
+	}
+	employee, err := u.employeeContract.GetOnce("id", leaveRequest.EmployeeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if !employees.IsManagedBy(*employee, request.ApproverID, u.departmentContract) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only the manager of the employee can decide its leave requests", struct{}{})
+	}
+	if request.Decision == models.LeaveDecisionReject {
+		return nil
+	}
+
+	// the situation may have changed since the request was submitted
+	overlap, err := findOverlap(u.repo, employee.ID, leaveRequest.StartDate, leaveRequest.EndDate, []models.LeaveStatus{models.LeaveStatusApproved}, leaveRequest.ID)
+	if err != nil {
+		return err
+	}
+	if overlap != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the request overlaps an approved leave", struct{}{})
+	}
+	leaveType, err := u.leaveTypeContract.GetOnce("id", leaveRequest.LeaveTypeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave type not found", struct{}{})
+	}
+	if leaveType.TracksBalance() {
+		balance, err := buildBalance(u.repo, *leaveType, *employee, leaveRequest.StartDate.Year())
+		if err != nil {
+			return err
+		}
+		// the request itself is counted as pending in the balance
+		if balance.Accrued-balance.Used < leaveRequest.Days {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "insufficient leave balance", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *DecideLeaveRequestUsecase) Execute() (*models.LeaveRequest, *models.SystemError) {
+	request := u.request.Build()
+	leaveRequest, err := u.repo.GetOnce("id", request.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	from := leaveRequest.Status
+	leaveRequest.Status = request.Status()
+	leaveRequest.ApproverID = request.ApproverID
+	leaveRequest.DecisionNote = request.Note
+	leaveRequest.DecidedAt = &now
+
+	// a request decided or cancelled since it was read is not decided again
+	updated, err := u.repo.Transition(*leaveRequest, from)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
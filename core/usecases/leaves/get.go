@@ -0,0 +1,26 @@
+package leaves
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type GetLeaveRequestUsecase struct {
+	repo      contracts.LeaveRequestContract
+	requestID string
+}
+
+func NewGetLeaveRequestUsecase(repo contracts.LeaveRequestContract, requestID string) *GetLeaveRequestUsecase {
+	return &GetLeaveRequestUsecase{repo: repo, requestID: requestID}
+}
+
+func (u *GetLeaveRequestUsecase) Validate() *models.SystemError {
+	if u.requestID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Leave request ID is required", nil)
+	}
+	return nil
+}
+
+func (u *GetLeaveRequestUsecase) Execute() (*models.LeaveRequest, *models.SystemError) {
+	return u.repo.GetOnce("id", u.requestID)
+}
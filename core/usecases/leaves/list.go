@@ -0,0 +1,25 @@
+package leaves
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type ListLeaveRequestsUsecase struct {
+	repo    contracts.LeaveRequestContract
+	request contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListLeaveRequestsUsecase(repo contracts.LeaveRequestContract, request contracts.IGenericRequest[models.SearchQuery]) *ListLeaveRequestsUsecase {
+	return &ListLeaveRequestsUsecase{repo: repo, request: request}
+}
+
+func (u *ListLeaveRequestsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.LeaveRequest{})
+}
+
+func (u *ListLeaveRequestsUsecase) Execute() (*models.PaginatedResponse[models.LeaveRequest], *models.SystemError) {
+	query := u.request.Build()
+	return u.repo.GetByFilter(query)
+}
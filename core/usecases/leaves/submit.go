@@ -0,0 +1,101 @@
+package leaves
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// SubmitLeaveRequestUsecase creates a pending leave request once it passes the type, overlap and balance checks.
+//
+// Example Usage:
+//
+//	request := contracts.NewGenericRequest(models.SubmitLeaveRequest{
+//		EmployeeID:  "employee-id",
+//		LeaveTypeID: "leave-type-id",
+//		StartDate:   start,
+//		EndDate:     end,
+//	})
+//	useCase := leaves.NewSubmitLeaveRequestUsecase(leaveRequestRepo, leaveTypeRepo, employeeRepo, scheduleRepo, request)
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	leaveRequest, err := useCase.Execute()
+type SubmitLeaveRequestUsecase struct {
+	repo              contracts.LeaveRequestContract
+	leaveTypeContract contracts.LeaveTypeContract
+	employeeContract  contracts.EmployeeContract
+	scheduleContract  contracts.WorkScheduleContract
+	request           contracts.IGenericRequest[models.SubmitLeaveRequest]
+}
+
+func NewSubmitLeaveRequestUsecase(repo contracts.LeaveRequestContract, leaveTypeContract contracts.LeaveTypeContract, employeeContract contracts.EmployeeContract, scheduleContract contracts.WorkScheduleContract, request contracts.IGenericRequest[models.SubmitLeaveRequest]) *SubmitLeaveRequestUsecase {
+	return &SubmitLeaveRequestUsecase{repo: repo, leaveTypeContract: leaveTypeContract, employeeContract: employeeContract, scheduleContract: scheduleContract, request: request}
+}
+
+func (u *SubmitLeaveRequestUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	employee, err := u.employeeContract.GetOnce("id", request.EmployeeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employee not found", struct{}{})
+	}
+	if employee.Status == models.EmploymentStatusTerminated {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "terminated employees cannot request leave", struct{}{})
+	}
+	leaveType, err := u.leaveTypeContract.GetOnce("id", request.LeaveTypeID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave type not found", struct{}{})
+	}
+	if !leaveType.Active {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave type is not active", struct{}{})
+	}
+
+	leaveRequest := request.ToLeaveRequest()
+	if leaveRequest.StartDate.Before(employee.HireDate) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave cannot start before the hire date", struct{}{})
+	}
+	days, err := countDays(u.scheduleContract, leaveRequest.StartDate, leaveRequest.EndDate)
+	if err != nil {
+		return err
+	}
+	if days == 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the request does not include any working day", struct{}{})
+	}
+
+	overlap, err := findOverlap(u.repo, employee.ID, leaveRequest.StartDate, leaveRequest.EndDate, []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusApproved}, "")
+	if err != nil {
+		return err
+	}
+	if overlap != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the request overlaps another leave request", struct{}{})
+	}
+
+	if leaveType.TracksBalance() {
+		balance, err := buildBalance(u.repo, *leaveType, *employee, leaveRequest.StartDate.Year())
+		if err != nil {
+			return err
+		}
+		if balance.Available < days {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "insufficient leave balance", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *SubmitLeaveRequestUsecase) Execute() (*models.LeaveRequest, *models.SystemError) {
+	request := u.request.Build()
+	leaveRequest := request.ToLeaveRequest()
+	days, err := countDays(u.scheduleContract, leaveRequest.StartDate, leaveRequest.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	leaveRequest.Days = days
+
+	created, err := u.repo.Create(leaveRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
@@ -0,0 +1,74 @@
+package leaves
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// SaveLeaveTypeUsecase creates a leave type, or updates it when the request carries an ID.
+type SaveLeaveTypeUsecase struct {
+	leaveTypeContract contracts.LeaveTypeContract
+	request           contracts.IGenericRequest[models.LeaveType]
+}
+
+func NewSaveLeaveTypeUsecase(leaveTypeContract contracts.LeaveTypeContract, request contracts.IGenericRequest[models.LeaveType]) *SaveLeaveTypeUsecase {
+	return &SaveLeaveTypeUsecase{leaveTypeContract: leaveTypeContract, request: request}
+}
+
+func (u *SaveLeaveTypeUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.ID != "" {
+		if _, err := u.leaveTypeContract.GetOnce("id", request.ID); err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave type not found", struct{}{})
+		}
+	}
+	existing, err := u.leaveTypeContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{{Key: "Code", Value: request.Code}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range existing.Rows {
+		if row.ID != request.ID {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "leave type code already exists", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *SaveLeaveTypeUsecase) Execute() (*models.LeaveType, *models.SystemError) {
+	request := u.request.Build()
+	var leaveType models.LeaveType
+	var err *models.SystemError
+	if request.ID == "" {
+		leaveType, err = u.leaveTypeContract.Create(request)
+	} else {
+		leaveType, err = u.leaveTypeContract.Update(request.ID, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leaveType, nil
+}
+
+type ListLeaveTypesUsecase struct {
+	leaveTypeContract contracts.LeaveTypeContract
+	request           contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListLeaveTypesUsecase(leaveTypeContract contracts.LeaveTypeContract, request contracts.IGenericRequest[models.SearchQuery]) *ListLeaveTypesUsecase {
+	return &ListLeaveTypesUsecase{leaveTypeContract: leaveTypeContract, request: request}
+}
+
+func (u *ListLeaveTypesUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.LeaveType{})
+}
+
+func (u *ListLeaveTypesUsecase) Execute() (*models.PaginatedResponse[models.LeaveType], *models.SystemError) {
+	query := u.request.Build()
+	return u.leaveTypeContract.GetByFilter(query)
+}
@@ -0,0 +1,71 @@
+package leaves
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// maxLeaveTypes bounds the leave types loaded when building balances
+const maxLeaveTypes = 100
+
+// countDays returns the working days between start and end according to the default work schedule
+func countDays(scheduleContract contracts.WorkScheduleContract, start, end time.Time) (float64, *models.SystemError) {
+	schedule, err := scheduleContract.GetDefault()
+	if err != nil {
+		return 0, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "no default work schedule configured", struct{}{})
+	}
+	return schedule.CountLeaveDays(start, end), nil
+}
+
+// buildBalance computes the balance of a leave type for an employee in a year from the accrual policy and its requests
+func buildBalance(repo contracts.LeaveRequestContract, leaveType models.LeaveType, employee models.Employee, year int) (models.LeaveBalance, *models.SystemError) {
+	balance := models.LeaveBalance{
+		EmployeeID:  employee.ID,
+		LeaveTypeID: leaveType.ID,
+		Code:        leaveType.Code,
+		Name:        leaveType.Name,
+		Year:        year,
+		Tracked:     leaveType.TracksBalance(),
+	}
+
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	asOf := time.Now().UTC()
+	if asOf.After(yearEnd) {
+		asOf = yearEnd
+	}
+	balance.Accrued = leaveType.AccruedDays(employee.HireDate, asOf)
+
+	requests, err := repo.GetOverlapping(employee.ID, yearStart, yearEnd, []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusApproved})
+	if err != nil {
+		return balance, err
+	}
+	for _, request := range requests {
+		if request.LeaveTypeID != leaveType.ID {
+			continue
+		}
+		if request.Status == models.LeaveStatusApproved {
+			balance.Used += request.Days
+		} else {
+			balance.Pending += request.Days
+		}
+	}
+	balance.Available = balance.Accrued - balance.Used - balance.Pending
+	return balance, nil
+}
+
+// findOverlap returns the first request of the employee in the given statuses overlapping [start, end], ignoring excludeID
+func findOverlap(repo contracts.LeaveRequestContract, employeeID string, start, end time.Time, statuses []models.LeaveStatus, excludeID string) (*models.LeaveRequest, *models.SystemError) {
+	requests, err := repo.GetOverlapping(employeeID, start, end, statuses)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.ID != excludeID {
+			return &request, nil
+		}
+	}
+	return nil, nil
+}
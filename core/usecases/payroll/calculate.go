@@ -0,0 +1,178 @@
+package payroll
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/attendance"
+)
+
+// employeePageSize is the number of employees loaded at once while calculating a run
+const employeePageSize = 100
+
+// CalculatePayrollRunUsecase computes the draft run of a pay period, creating it the first time.
+// Calculating again replaces every payslip of the draft, finalized and paid runs are locked.
+// The payslips are computed first and stored with the totals of the run in a single transaction.
+//
+// Example Usage:
+//
+//	request := contracts.NewGenericRequest(models.CalculatePayrollRun{PayPeriodID: "period-id"})
+//	useCase := payroll.NewCalculatePayrollRunUsecase(runRepo, periodRepo, employeeRepo, positionRepo, attendanceRepo, scheduleRepo, payroll.DefaultRules(), request)
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	run, err := useCase.Execute()
+type CalculatePayrollRunUsecase struct {
+	runContract        contracts.PayrollRunContract
+	periodContract     contracts.PayPeriodContract
+	employeeContract   contracts.EmployeeContract
+	positionContract   contracts.PositionContract
+	attendanceContract contracts.AttendanceContract
+	scheduleContract   contracts.WorkScheduleContract
+	rules              []contracts.PayrollRuleContract
+	request            contracts.IGenericRequest[models.CalculatePayrollRun]
+}
+
+func NewCalculatePayrollRunUsecase(
+	runContract contracts.PayrollRunContract,
+	periodContract contracts.PayPeriodContract,
+	employeeContract contracts.EmployeeContract,
+	positionContract contracts.PositionContract,
+	attendanceContract contracts.AttendanceContract,
+	scheduleContract contracts.WorkScheduleContract,
+	rules []contracts.PayrollRuleContract,
+	request contracts.IGenericRequest[models.CalculatePayrollRun],
+) *CalculatePayrollRunUsecase {
+	return &CalculatePayrollRunUsecase{
+		runContract:        runContract,
+		periodContract:     periodContract,
+		employeeContract:   employeeContract,
+		positionContract:   positionContract,
+		attendanceContract: attendanceContract,
+		scheduleContract:   scheduleContract,
+		rules:              rules,
+		request:            request,
+	}
+}
+
+func (u *CalculatePayrollRunUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if request.PayPeriodID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "pay period is required", struct{}{})
+	}
+	if _, err := u.periodContract.GetOnce("id", request.PayPeriodID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "pay period not found", struct{}{})
+	}
+	run, err := findRunByPeriod(u.runContract, request.PayPeriodID)
+	if err != nil {
+		return err
+	}
+	if run != nil && run.Status.IsLocked() {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the payroll run of the period is "+string(run.Status)+" and cannot be recalculated", struct{}{})
+	}
+	if _, err := u.scheduleContract.GetDefault(); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "no default work schedule configured", struct{}{})
+	}
+	return nil
+}
+
+func (u *CalculatePayrollRunUsecase) Execute() (*models.PayrollRun, *models.SystemError) {
+	request := u.request.Build()
+	period, err := u.periodContract.GetOnce("id", request.PayPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := u.scheduleContract.GetDefault()
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := findRunByPeriod(u.runContract, period.ID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		run = &models.PayrollRun{PayPeriodID: period.ID, Status: models.PayrollStatusDraft}
+	}
+
+	payslips := []models.Payslip{}
+	totals := models.PayrollRun{}
+	positions := map[string]*models.Position{}
+	for page := 1; ; page++ {
+		paginatedData, err := u.employeeContract.GetByFilter(models.SearchQuery{
+			Pagination: models.Pagination{Page: page, Limit: employeePageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, employee := range paginatedData.Rows {
+			if employee.PositionID == "" || !isEmployedDuring(employee, *period) {
+				continue
+			}
+			position, ok := positions[employee.PositionID]
+			if !ok {
+				position, err = u.positionContract.GetOnce("id", employee.PositionID)
+				if err != nil {
+					return nil, err
+				}
+				positions[employee.PositionID] = position
+			}
+			payslip, err := u.calculate(employee, *position, *period, *schedule)
+			if err != nil {
+				return nil, err
+			}
+			payslips = append(payslips, payslip)
+			totals.AddPayslip(payslip)
+		}
+		if page >= paginatedData.TotalPages {
+			break
+		}
+	}
+
+	run.EmployeeCount = totals.EmployeeCount
+	run.TotalGross = totals.TotalGross
+	run.TotalDeductions = totals.TotalDeductions
+	run.TotalNet = totals.TotalNet
+	run.CalculatedAt = time.Now().UTC()
+	saved, err := u.runContract.SaveCalculation(*run, payslips)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (u *CalculatePayrollRunUsecase) calculate(employee models.Employee, position models.Position, period models.PayPeriod, schedule models.WorkSchedule) (models.Payslip, *models.SystemError) {
+	timesheetUseCase := attendance.NewGetTimesheetUsecase(u.attendanceContract, u.employeeContract, u.scheduleContract, contracts.NewGenericRequest(models.TimesheetQuery{
+		EmployeeID: employee.ID,
+		From:       period.StartDate,
+		To:         period.EndDate,
+		ScheduleID: schedule.ID,
+	}))
+	timesheets, err := timesheetUseCase.Execute()
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	return calculatePayslip(payslipInput{
+		employee:   employee,
+		position:   position,
+		period:     period,
+		schedule:   schedule,
+		timesheets: timesheets,
+	}, u.rules)
+}
+
+// findRunByPeriod returns the run of a pay period, nil when the period was never calculated
+func findRunByPeriod(runContract contracts.PayrollRunContract, periodID string) (*models.PayrollRun, *models.SystemError) {
+	paginatedData, err := runContract.GetByFilter(models.SearchQuery{
+		Filters:    models.Filters{{Key: "PayPeriodID", Value: periodID}},
+		Pagination: models.Pagination{Page: 1, Limit: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(paginatedData.Rows) == 0 {
+		return nil, nil
+	}
+	return &paginatedData.Rows[0], nil
+}
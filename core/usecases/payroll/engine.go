@@ -0,0 +1,131 @@
+package payroll
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+const (
+	// OvertimeMultiplier is applied to the hourly rate for every overtime hour
+	OvertimeMultiplier = 1.5
+	// weeksPerMonth converts weekly scheduled hours into monthly hours
+	weeksPerMonth = 52.0 / 12.0
+)
+
+// payslipInput gathers everything needed to compute the payslip of one employee
+type payslipInput struct {
+	employee   models.Employee
+	position   models.Position
+	period     models.PayPeriod
+	schedule   models.WorkSchedule
+	timesheets []models.DailyTimesheet
+}
+
+// calculatePayslip computes the payslip of an employee.
+// The salary of the employee is a monthly amount, the period pays the share of the month it covers
+// and only the days the employee was employed.
+func calculatePayslip(input payslipInput, rules []contracts.PayrollRuleContract) (models.Payslip, *models.SystemError) {
+	salary := monthlySalary(input.employee, input.position)
+	payslip := models.Payslip{
+		PayPeriodID: input.period.ID,
+		EmployeeID:  input.employee.ID,
+		PositionID:  input.position.ID,
+		Currency:    input.position.Currency,
+		BaseSalary:  salary,
+		WorkedDays:  employedDays(input.employee, input.period),
+		// the income of the period is annualized with its own frequency
+		PeriodsPerYear: input.period.PeriodsPerYear(),
+	}
+
+	monthDays := daysInMonth(input.period.StartDate)
+	payslip.AddLine(models.PayslipLine{
+		Code:        "BASE",
+		Description: "Base salary",
+		Kind:        models.PayslipLineKindEarning,
+		Amount:      salary * float64(payslip.WorkedDays) / float64(monthDays),
+	})
+
+	for _, timesheet := range input.timesheets {
+		payslip.OvertimeMinutes += timesheet.OvertimeMinutes
+	}
+	if hourlyRate := hourlyRate(salary, input.schedule); hourlyRate > 0 {
+		payslip.AddLine(models.PayslipLine{
+			Code:        "OVERTIME",
+			Description: "Overtime",
+			Kind:        models.PayslipLineKindEarning,
+			Amount:      float64(payslip.OvertimeMinutes) / 60 * hourlyRate * OvertimeMultiplier,
+		})
+	}
+
+	for _, kind := range []models.PayslipLineKind{models.PayslipLineKindEarning, models.PayslipLineKindDeduction} {
+		for _, rule := range rules {
+			if rule.Kind() != kind {
+				continue
+			}
+			line, err := rule.Calculate(payslip)
+			if err != nil {
+				return payslip, err
+			}
+			if line == nil {
+				continue
+			}
+			if line.Code == "" {
+				line.Code = rule.Code()
+			}
+			line.Kind = kind
+			payslip.AddLine(*line)
+		}
+	}
+	return payslip, nil
+}
+
+// monthlySalary is the salary of the employee, employees stored before salaries were recorded
+// are paid the lower bound of the band of their position
+func monthlySalary(employee models.Employee, position models.Position) float64 {
+	if employee.Salary == 0 {
+		return position.MinSalary
+	}
+	return employee.Salary
+}
+
+// employedDays returns the days of the period between the hire date and the termination date of the employee
+func employedDays(employee models.Employee, period models.PayPeriod) int {
+	start := dayOf(period.StartDate)
+	end := dayOf(period.EndDate)
+	if hire := dayOf(employee.HireDate); hire.After(start) {
+		start = hire
+	}
+	if employee.TerminationDate != nil {
+		if termination := dayOf(*employee.TerminationDate); termination.Before(end) {
+			end = termination
+		}
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// isEmployedDuring reports whether the employee worked at least one day of the period
+func isEmployedDuring(employee models.Employee, period models.PayPeriod) bool {
+	return employedDays(employee, period) > 0
+}
+
+// hourlyRate converts a monthly salary into an hourly rate using the hours of the work schedule
+func hourlyRate(monthlySalary float64, schedule models.WorkSchedule) float64 {
+	monthlyHours := float64(schedule.ScheduledMinutes()*len(schedule.WorkDays)) / 60 * weeksPerMonth
+	if monthlyHours <= 0 {
+		return 0
+	}
+	return monthlySalary / monthlyHours
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,181 @@
+package payroll
+
+import (
+	"testing"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+func TestTaxBracketRule(t *testing.T) {
+	rule := NewTaxBracketRule("TAX", "Income tax", 12, []TaxBracket{
+		{UpTo: 0, Rate: 0.3},
+		{UpTo: 12000, Rate: 0},
+		{UpTo: 24000, Rate: 0.1},
+	})
+	cases := []struct {
+		gross    float64
+		expected float64
+	}{
+		{gross: 1000, expected: 0},
+		{gross: 1500, expected: 50},
+		{gross: 3000, expected: 100 + 300},
+	}
+	for _, c := range cases {
+		line, err := rule.Calculate(models.Payslip{GrossPay: c.gross})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if models.RoundAmount(line.Amount) != c.expected {
+			t.Errorf("gross %v: expected tax %v, got %v", c.gross, c.expected, line.Amount)
+		}
+	}
+}
+
+func TestCalculatePayslip(t *testing.T) {
+	schedule := models.WorkSchedule{
+		StartTime:    "09:00",
+		EndTime:      "17:00",
+		WorkDays:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		BreakMinutes: 0,
+	}
+	period := models.PayPeriod{
+		ID:        "period",
+		StartDate: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+	}
+	position := models.Position{ID: "position", MinSalary: 3000, Currency: "USD"}
+	rules := []contracts.PayrollRuleContract{
+		NewSocialSecurityRule("SS", "Social security", 0.1, 0),
+		NewFixedAllowanceRule("TRANSPORT", "Transport", 100),
+	}
+
+	// hired in the middle of the month with two hours of overtime
+	employee := models.Employee{ID: "employee", HireDate: time.Date(2025, time.April, 16, 0, 0, 0, 0, time.UTC)}
+	timesheets := []models.DailyTimesheet{{OvertimeMinutes: 120}}
+	payslip, err := calculatePayslip(payslipInput{employee: employee, position: position, period: period, schedule: schedule, timesheets: timesheets}, rules)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if payslip.WorkedDays != 15 {
+		t.Fatalf("Expected 15 worked days, got %d", payslip.WorkedDays)
+	}
+	// base 3000 * 15 / 30, overtime 3000 / (40h * 52 / 12) * 2h * 1.5, transport 100
+	expectedGross := models.RoundAmount(1500 + models.RoundAmount(3000/(40*52.0/12)*2*1.5) + 100)
+	if payslip.GrossPay != expectedGross {
+		t.Fatalf("Expected gross %v, got %v", expectedGross, payslip.GrossPay)
+	}
+	// deductions run after every earning, so the allowance is part of the contribution base
+	if payslip.TotalDeductions != models.RoundAmount(expectedGross*0.1) {
+		t.Fatalf("Expected deductions on the whole gross, got %v", payslip.TotalDeductions)
+	}
+	if payslip.NetPay != models.RoundAmount(payslip.GrossPay-payslip.TotalDeductions) {
+		t.Fatalf("Unexpected net pay %v", payslip.NetPay)
+	}
+	if last := payslip.Lines[len(payslip.Lines)-1]; last.Code != "SS" {
+		t.Fatalf("Expected the deduction to be the last line, got %s", last.Code)
+	}
+}
+
+func TestCalculatePayslipPaysTheSalaryOfTheEmployee(t *testing.T) {
+	schedule := models.WorkSchedule{
+		StartTime: "08:00",
+		EndTime:   "16:00",
+		WorkDays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+	period := models.PayPeriod{
+		ID:        "period",
+		StartDate: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+	}
+	position := models.Position{ID: "position", MinSalary: 3000, MaxSalary: 5000, Currency: "USD"}
+	timesheets := []models.DailyTimesheet{{OvertimeMinutes: 60}}
+
+	employee := models.Employee{ID: "employee", HireDate: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), Salary: 4000}
+	payslip, err := calculatePayslip(payslipInput{employee: employee, position: position, period: period, schedule: schedule, timesheets: timesheets}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// base 4000, overtime 4000 / (40h * 52 / 12) * 1h * 1.5
+	expectedGross := models.RoundAmount(4000 + models.RoundAmount(4000/(40*52.0/12)*1.5))
+	if payslip.BaseSalary != 4000 || payslip.GrossPay != expectedGross {
+		t.Fatalf("Expected the salary of the employee, got base %v and gross %v", payslip.BaseSalary, payslip.GrossPay)
+	}
+
+	// employees stored without a salary are paid the lower bound of the band
+	employee.Salary = 0
+	payslip, err = calculatePayslip(payslipInput{employee: employee, position: position, period: period, schedule: schedule}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if payslip.BaseSalary != 3000 || payslip.GrossPay != 3000 {
+		t.Fatalf("Expected the lower bound of the band, got base %v and gross %v", payslip.BaseSalary, payslip.GrossPay)
+	}
+}
+
+func TestPayrollStatusLifecycle(t *testing.T) {
+	if !models.PayrollStatusDraft.CanTransitionTo(models.PayrollStatusFinalized) || !models.PayrollStatusFinalized.CanTransitionTo(models.PayrollStatusPaid) {
+		t.Fatal("Expected draft -> finalized -> paid to be allowed")
+	}
+	if models.PayrollStatusDraft.CanTransitionTo(models.PayrollStatusPaid) || models.PayrollStatusPaid.CanTransitionTo(models.PayrollStatusDraft) {
+		t.Fatal("Expected runs not to skip or go back in the lifecycle")
+	}
+	if models.PayrollStatusDraft.IsLocked() || !models.PayrollStatusFinalized.IsLocked() {
+		t.Fatal("Expected only draft runs to be unlocked")
+	}
+}
+
+func TestPeriodsPerYear(t *testing.T) {
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		start, end time.Time
+		expected   int
+	}{
+		{start: day(time.April, 1), end: day(time.April, 30), expected: 12},
+		{start: day(time.February, 1), end: day(time.February, 28), expected: 12},
+		{start: day(time.April, 10), end: day(time.May, 9), expected: 12},
+		{start: day(time.April, 1), end: day(time.April, 15), expected: 24},
+		{start: day(time.February, 16), end: day(time.February, 28), expected: 24},
+		{start: day(time.April, 7), end: day(time.April, 20), expected: 26},
+		{start: day(time.April, 7), end: day(time.April, 13), expected: 52},
+		{start: day(time.January, 1), end: day(time.March, 31), expected: 4},
+	}
+	for _, c := range cases {
+		period := models.PayPeriod{StartDate: c.start, EndDate: c.end}
+		if got := period.PeriodsPerYear(); got != c.expected {
+			t.Errorf("%s - %s: expected %d periods per year, got %d", c.start.Format(time.DateOnly), c.end.Format(time.DateOnly), c.expected, got)
+		}
+	}
+}
+
+func TestCalculatePayslipAnnualizesTheTaxOfABiweeklyPeriod(t *testing.T) {
+	period := models.PayPeriod{
+		ID:        "period",
+		StartDate: time.Date(2025, time.April, 7, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC),
+	}
+	employee := models.Employee{ID: "employee", HireDate: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	position := models.Position{ID: "position", MinSalary: 3000, Currency: "USD"}
+	rules := []contracts.PayrollRuleContract{
+		NewTaxBracketRule("TAX", "Income tax", 0, []TaxBracket{
+			{UpTo: 24000, Rate: 0},
+			{UpTo: 0, Rate: 0.1},
+		}),
+	}
+
+	payslip, err := calculatePayslip(payslipInput{employee: employee, position: position, period: period}, rules)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// base 3000 * 14 / 30 = 1400 a fortnight, 36400 a year: 12400 taxed at 10%, withheld over 26 fortnights
+	if payslip.GrossPay != 1400 {
+		t.Fatalf("Expected gross 1400, got %v", payslip.GrossPay)
+	}
+	if expected := models.RoundAmount(12400 * 0.1 / 26); payslip.TotalDeductions != expected {
+		t.Fatalf("Expected tax %v, got %v", expected, payslip.TotalDeductions)
+	}
+}
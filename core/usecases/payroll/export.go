@@ -0,0 +1,212 @@
+package payroll
+
+import (
+	"strings"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// payslipPageSize is the number of payslips loaded at once while building a bank file
+const payslipPageSize = 100
+
+// ExportPayslipUsecase renders the payslip of an employee as a PDF.
+// Only payslips of finalized or paid runs can be exported, drafts may still change.
+//
+// Example Usage:
+//
+//	useCase := payroll.NewExportPayslipUsecase(payslipRepo, runRepo, periodRepo, employeeRepo, positionRepo, departmentRepo, exporter, "HEX-HRMS", "payslip-id")
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	pdf, err := useCase.Execute()
+type ExportPayslipUsecase struct {
+	payslipContract    contracts.PayslipContract
+	runContract        contracts.PayrollRunContract
+	periodContract     contracts.PayPeriodContract
+	employeeContract   contracts.EmployeeContract
+	positionContract   contracts.PositionContract
+	departmentContract contracts.DepartmentContract
+	exportContract     contracts.PayrollExportContract
+	companyName        string
+	payslipID          string
+}
+
+func NewExportPayslipUsecase(
+	payslipContract contracts.PayslipContract,
+	runContract contracts.PayrollRunContract,
+	periodContract contracts.PayPeriodContract,
+	employeeContract contracts.EmployeeContract,
+	positionContract contracts.PositionContract,
+	departmentContract contracts.DepartmentContract,
+	exportContract contracts.PayrollExportContract,
+	companyName string,
+	payslipID string,
+) *ExportPayslipUsecase {
+	return &ExportPayslipUsecase{
+		payslipContract:    payslipContract,
+		runContract:        runContract,
+		periodContract:     periodContract,
+		employeeContract:   employeeContract,
+		positionContract:   positionContract,
+		departmentContract: departmentContract,
+		exportContract:     exportContract,
+		companyName:        companyName,
+		payslipID:          payslipID,
+	}
+}
+
+func (u *ExportPayslipUsecase) Validate() *models.SystemError {
+	if u.payslipID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Payslip ID is required", struct{}{})
+	}
+	payslip, err := u.payslipContract.GetOnce("id", u.payslipID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "payslip not found", struct{}{})
+	}
+	return validateExportableRun(u.runContract, payslip.PayrollRunID)
+}
+
+func (u *ExportPayslipUsecase) Execute() ([]byte, *models.SystemError) {
+	payslip, err := u.payslipContract.GetOnce("id", u.payslipID)
+	if err != nil {
+		return nil, err
+	}
+	period, err := u.periodContract.GetOnce("id", payslip.PayPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	employee, err := u.employeeContract.GetOnce("id", payslip.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	document := models.PayslipDocument{
+		CompanyName: u.companyName,
+		Payslip:     *payslip,
+		Employee:    *employee,
+		Period:      *period,
+	}
+	// the position and department may have been renamed or removed since the run, they are informative only
+	if payslip.PositionID != "" {
+		if position, err := u.positionContract.GetOnce("id", payslip.PositionID); err == nil {
+			document.PositionName = position.Name
+		}
+	}
+	if employee.DepartmentID != "" {
+		if department, err := u.departmentContract.GetOnce("id", employee.DepartmentID); err == nil {
+			document.DepartmentName = department.Name
+		}
+	}
+	return u.exportContract.RenderPayslipPDF(document)
+}
+
+// ExportBankFileUsecase generates the file finance uploads to the bank to pay the net pay of a run.
+// The run must be finalized or paid and every paid employee needs a bank account.
+type ExportBankFileUsecase struct {
+	runContract      contracts.PayrollRunContract
+	periodContract   contracts.PayPeriodContract
+	payslipContract  contracts.PayslipContract
+	employeeContract contracts.EmployeeContract
+	exportContract   contracts.PayrollExportContract
+	companyName      string
+	request          contracts.IGenericRequest[models.ExportBankFile]
+}
+
+func NewExportBankFileUsecase(
+	runContract contracts.PayrollRunContract,
+	periodContract contracts.PayPeriodContract,
+	payslipContract contracts.PayslipContract,
+	employeeContract contracts.EmployeeContract,
+	exportContract contracts.PayrollExportContract,
+	companyName string,
+	request contracts.IGenericRequest[models.ExportBankFile],
+) *ExportBankFileUsecase {
+	return &ExportBankFileUsecase{
+		runContract:      runContract,
+		periodContract:   periodContract,
+		payslipContract:  payslipContract,
+		employeeContract: employeeContract,
+		exportContract:   exportContract,
+		companyName:      companyName,
+		request:          request,
+	}
+}
+
+func (u *ExportBankFileUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return validateExportableRun(u.runContract, request.RunID)
+}
+
+func (u *ExportBankFileUsecase) Execute() ([]byte, *models.SystemError) {
+	request := u.request.Build()
+	run, err := u.runContract.GetOnce("id", request.RunID)
+	if err != nil {
+		return nil, err
+	}
+	period, err := u.periodContract.GetOnce("id", run.PayPeriodID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := models.BankTransferBatch{
+		CompanyName: u.companyName,
+		RunID:       run.ID,
+		PeriodName:  period.Name,
+		PayDate:     period.PayDate,
+	}
+	var missingAccounts []string
+	for page := 1; ; page++ {
+		paginatedData, err := u.payslipContract.GetByFilter(models.SearchQuery{
+			Filters:    models.Filters{{Key: "PayrollRunID", Value: run.ID}},
+			Pagination: models.Pagination{Page: page, Limit: payslipPageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, payslip := range paginatedData.Rows {
+			if payslip.NetPay <= 0 {
+				continue
+			}
+			employee, err := u.employeeContract.GetOnce("id", payslip.EmployeeID)
+			if err != nil {
+				return nil, err
+			}
+			if employee.BankAccount == "" {
+				missingAccounts = append(missingAccounts, employee.EmployeeNumber)
+				continue
+			}
+			batch.Transfers = append(batch.Transfers, models.BankTransfer{
+				EmployeeNumber: employee.EmployeeNumber,
+				EmployeeName:   employee.FullName(),
+				NationalID:     employee.NationalID,
+				BankName:       employee.BankName,
+				BankAccount:    employee.BankAccount,
+				Amount:         payslip.NetPay,
+				Currency:       payslip.Currency,
+			})
+		}
+		if page >= paginatedData.TotalPages {
+			break
+		}
+	}
+	if len(missingAccounts) > 0 {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "employees without bank account: "+strings.Join(missingAccounts, ", "), missingAccounts)
+	}
+	return u.exportContract.GenerateBankFile(batch, request.Format)
+}
+
+// validateExportableRun checks that the run exists and is locked
+func validateExportableRun(runContract contracts.PayrollRunContract, runID string) *models.SystemError {
+	run, err := runContract.GetOnce("id", runID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "payroll run not found", struct{}{})
+	}
+	if !run.Status.IsLocked() {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only finalized or paid runs can be exported", struct{}{})
+	}
+	return nil
+}
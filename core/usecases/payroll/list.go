@@ -0,0 +1,64 @@
+package payroll
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type ListPayrollRunsUsecase struct {
+	runContract contracts.PayrollRunContract
+	request     contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListPayrollRunsUsecase(runContract contracts.PayrollRunContract, request contracts.IGenericRequest[models.SearchQuery]) *ListPayrollRunsUsecase {
+	return &ListPayrollRunsUsecase{runContract: runContract, request: request}
+}
+
+func (u *ListPayrollRunsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.PayrollRun{})
+}
+
+func (u *ListPayrollRunsUsecase) Execute() (*models.PaginatedResponse[models.PayrollRun], *models.SystemError) {
+	query := u.request.Build()
+	return u.runContract.GetByFilter(query)
+}
+
+type ListPayslipsUsecase struct {
+	payslipContract contracts.PayslipContract
+	request         contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListPayslipsUsecase(payslipContract contracts.PayslipContract, request contracts.IGenericRequest[models.SearchQuery]) *ListPayslipsUsecase {
+	return &ListPayslipsUsecase{payslipContract: payslipContract, request: request}
+}
+
+func (u *ListPayslipsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.Payslip{})
+}
+
+func (u *ListPayslipsUsecase) Execute() (*models.PaginatedResponse[models.Payslip], *models.SystemError) {
+	query := u.request.Build()
+	return u.payslipContract.GetByFilter(query)
+}
+
+type GetPayslipUsecase struct {
+	payslipContract contracts.PayslipContract
+	payslipID       string
+}
+
+func NewGetPayslipUsecase(payslipContract contracts.PayslipContract, payslipID string) *GetPayslipUsecase {
+	return &GetPayslipUsecase{payslipContract: payslipContract, payslipID: payslipID}
+}
+
+func (u *GetPayslipUsecase) Validate() *models.SystemError {
+	if u.payslipID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Payslip ID is required", nil)
+	}
+	return nil
+}
+
+func (u *GetPayslipUsecase) Execute() (*models.Payslip, *models.SystemError) {
+	return u.payslipContract.GetOnce("id", u.payslipID)
+}
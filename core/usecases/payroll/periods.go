@@ -0,0 +1,63 @@
+package payroll
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// CreatePayPeriodUsecase registers a pay period, periods cannot overlap each other.
+type CreatePayPeriodUsecase struct {
+	periodContract contracts.PayPeriodContract
+	request        contracts.IGenericRequest[models.PayPeriod]
+}
+
+func NewCreatePayPeriodUsecase(periodContract contracts.PayPeriodContract, request contracts.IGenericRequest[models.PayPeriod]) *CreatePayPeriodUsecase {
+	return &CreatePayPeriodUsecase{periodContract: periodContract, request: request}
+}
+
+func (u *CreatePayPeriodUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	overlapping, err := u.periodContract.GetOverlapping(dayOf(request.StartDate), dayOf(request.EndDate))
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the period overlaps the pay period "+overlapping[0].Name, struct{}{})
+	}
+	return nil
+}
+
+func (u *CreatePayPeriodUsecase) Execute() (*models.PayPeriod, *models.SystemError) {
+	request := u.request.Build()
+	request.ID = ""
+	request.StartDate = dayOf(request.StartDate)
+	request.EndDate = dayOf(request.EndDate)
+	request.PayDate = dayOf(request.PayDate)
+	period, err := u.periodContract.Create(request)
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+type ListPayPeriodsUsecase struct {
+	periodContract contracts.PayPeriodContract
+	request        contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListPayPeriodsUsecase(periodContract contracts.PayPeriodContract, request contracts.IGenericRequest[models.SearchQuery]) *ListPayPeriodsUsecase {
+	return &ListPayPeriodsUsecase{periodContract: periodContract, request: request}
+}
+
+func (u *ListPayPeriodsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.PayPeriod{})
+}
+
+func (u *ListPayPeriodsUsecase) Execute() (*models.PaginatedResponse[models.PayPeriod], *models.SystemError) {
+	query := u.request.Build()
+	return u.periodContract.GetByFilter(query)
+}
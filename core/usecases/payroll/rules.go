@@ -0,0 +1,129 @@
+package payroll
+
+import (
+	"math"
+	"sort"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// FixedAllowanceRule adds the same earning to every payslip (transport, meals...)
+type FixedAllowanceRule struct {
+	RuleCode    string
+	Description string
+	Amount      float64
+}
+
+func NewFixedAllowanceRule(code, description string, amount float64) *FixedAllowanceRule {
+	return &FixedAllowanceRule{RuleCode: code, Description: description, Amount: amount}
+}
+
+func (r *FixedAllowanceRule) Code() string { return r.RuleCode }
+
+func (r *FixedAllowanceRule) Kind() models.PayslipLineKind { return models.PayslipLineKindEarning }
+
+func (r *FixedAllowanceRule) Calculate(payslip models.Payslip) (*models.PayslipLine, *models.SystemError) {
+	return &models.PayslipLine{Code: r.RuleCode, Description: r.Description, Kind: models.PayslipLineKindEarning, Amount: r.Amount}, nil
+}
+
+// SocialSecurityRule deducts a rate of the gross pay, the contribution base is capped by Ceiling when it is greater than zero
+type SocialSecurityRule struct {
+	RuleCode    string
+	Description string
+	Rate        float64
+	Ceiling     float64
+}
+
+func NewSocialSecurityRule(code, description string, rate, ceiling float64) *SocialSecurityRule {
+	return &SocialSecurityRule{RuleCode: code, Description: description, Rate: rate, Ceiling: ceiling}
+}
+
+func (r *SocialSecurityRule) Code() string { return r.RuleCode }
+
+func (r *SocialSecurityRule) Kind() models.PayslipLineKind { return models.PayslipLineKindDeduction }
+
+func (r *SocialSecurityRule) Calculate(payslip models.Payslip) (*models.PayslipLine, *models.SystemError) {
+	base := payslip.GrossPay
+	if r.Ceiling > 0 && base > r.Ceiling {
+		base = r.Ceiling
+	}
+	return &models.PayslipLine{Code: r.RuleCode, Description: r.Description, Kind: models.PayslipLineKindDeduction, Amount: base * r.Rate}, nil
+}
+
+// TaxBracket taxes the income above the previous bracket and up to UpTo, a zero UpTo means no upper limit
+type TaxBracket struct {
+	UpTo float64
+	Rate float64
+}
+
+// TaxBracketRule withholds a progressive income tax.
+// Brackets are annual, the taxable income of the payslip (gross minus the deductions applied before this rule)
+// is annualized with the periods per year of its pay period and the resulting tax is divided back.
+// A PeriodsPerYear greater than zero overrides the one of the period.
+type TaxBracketRule struct {
+	RuleCode       string
+	Description    string
+	Brackets       []TaxBracket
+	PeriodsPerYear int
+}
+
+func NewTaxBracketRule(code, description string, periodsPerYear int, brackets []TaxBracket) *TaxBracketRule {
+	sorted := append([]TaxBracket(nil), brackets...)
+	// the open ended bracket always goes last
+	limit := func(b TaxBracket) float64 {
+		if b.UpTo == 0 {
+			return math.Inf(1)
+		}
+		return b.UpTo
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return limit(sorted[i]) < limit(sorted[j]) })
+	return &TaxBracketRule{RuleCode: code, Description: description, Brackets: sorted, PeriodsPerYear: periodsPerYear}
+}
+
+func (r *TaxBracketRule) Code() string { return r.RuleCode }
+
+func (r *TaxBracketRule) Kind() models.PayslipLineKind { return models.PayslipLineKindDeduction }
+
+func (r *TaxBracketRule) Calculate(payslip models.Payslip) (*models.PayslipLine, *models.SystemError) {
+	periodsPerYear := payslip.PeriodsPerYear
+	if r.PeriodsPerYear > 0 {
+		periodsPerYear = r.PeriodsPerYear
+	}
+	if periodsPerYear <= 0 {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "tax rule "+r.RuleCode+" requires the number of periods per year", struct{}{})
+	}
+	annual := (payslip.GrossPay - payslip.TotalDeductions) * float64(periodsPerYear)
+	var tax, lower float64
+	for _, bracket := range r.Brackets {
+		if annual <= lower {
+			break
+		}
+		upper := annual
+		if bracket.UpTo > 0 && bracket.UpTo < upper {
+			upper = bracket.UpTo
+		}
+		tax += (upper - lower) * bracket.Rate
+		if bracket.UpTo == 0 {
+			break
+		}
+		lower = bracket.UpTo
+	}
+	return &models.PayslipLine{Code: r.RuleCode, Description: r.Description, Kind: models.PayslipLineKindDeduction, Amount: tax / float64(periodsPerYear)}, nil
+}
+
+// DefaultRules returns the rules used when the server does not configure its own.
+// They follow the Dominican Republic scheme (TSS pension and health contributions and ISR withholding),
+// replace them to match other jurisdictions. ISR is annualized with the frequency of each pay period.
+func DefaultRules() []contracts.PayrollRuleContract {
+	return []contracts.PayrollRuleContract{
+		NewSocialSecurityRule("AFP", "Pension fund contribution", 0.0287, 0),
+		NewSocialSecurityRule("SFS", "Family health insurance contribution", 0.0304, 0),
+		NewTaxBracketRule("ISR", "Income tax withholding", 0, []TaxBracket{
+			{UpTo: 416220, Rate: 0},
+			{UpTo: 624329, Rate: 0.15},
+			{UpTo: 867123, Rate: 0.20},
+			{UpTo: 0, Rate: 0.25},
+		}),
+	}
+}
@@ -0,0 +1,89 @@
+package payroll
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// ChangePayrollRunStatusUsecase moves a run through draft -> finalized -> paid.
+// Finalizing locks the run and its payslips.
+type ChangePayrollRunStatusUsecase struct {
+	runContract contracts.PayrollRunContract
+	request     contracts.IGenericRequest[models.ChangePayrollRunStatus]
+}
+
+func NewChangePayrollRunStatusUsecase(runContract contracts.PayrollRunContract, request contracts.IGenericRequest[models.ChangePayrollRunStatus]) *ChangePayrollRunStatusUsecase {
+	return &ChangePayrollRunStatusUsecase{runContract: runContract, request: request}
+}
+
+func (u *ChangePayrollRunStatusUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if request.RunID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "run id is required", struct{}{})
+	}
+	run, err := u.runContract.GetOnce("id", request.RunID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "payroll run not found", struct{}{})
+	}
+	if !run.Status.CanTransitionTo(request.Status) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "a "+string(run.Status)+" run cannot be marked as "+string(request.Status), struct{}{})
+	}
+	if request.Status == models.PayrollStatusFinalized && run.EmployeeCount == 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the run has no payslips", struct{}{})
+	}
+	return nil
+}
+
+func (u *ChangePayrollRunStatusUsecase) Execute() (*models.PayrollRun, *models.SystemError) {
+	request := u.request.Build()
+	run, err := u.runContract.GetOnce("id", request.RunID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	from := run.Status
+	run.Status = request.Status
+	switch request.Status {
+	case models.PayrollStatusFinalized:
+		run.FinalizedAt = &now
+	case models.PayrollStatusPaid:
+		run.PaidAt = &now
+	}
+	// a run recalculated or moved since it was read is not changed
+	updated, err := u.runContract.ChangeStatus(*run, from)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeletePayrollRunUsecase discards a draft run and its payslips.
+type DeletePayrollRunUsecase struct {
+	runContract contracts.PayrollRunContract
+	runID       string
+}
+
+func NewDeletePayrollRunUsecase(runContract contracts.PayrollRunContract, runID string) *DeletePayrollRunUsecase {
+	return &DeletePayrollRunUsecase{runContract: runContract, runID: runID}
+}
+
+func (u *DeletePayrollRunUsecase) Validate() *models.SystemError {
+	if u.runID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "run id is required", struct{}{})
+	}
+	run, err := u.runContract.GetOnce("id", u.runID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "payroll run not found", struct{}{})
+	}
+	if run.Status.IsLocked() {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "only draft runs can be deleted", struct{}{})
+	}
+	return nil
+}
+
+func (u *DeletePayrollRunUsecase) Execute() *models.SystemError {
+	// a run finalized since Validate read it is not deleted
+	return u.runContract.DeleteDraft(u.runID)
+}
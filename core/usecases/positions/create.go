@@ -0,0 +1,33 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type CreatePositionUsecase struct {
+	repo               contracts.PositionContract
+	departmentContract contracts.DepartmentContract
+	request            contracts.IGenericRequest[models.CreatePosition]
+}
+
+func NewCreatePositionUsecase(repo contracts.PositionContract, departmentContract contracts.DepartmentContract, request contracts.IGenericRequest[models.CreatePosition]) *CreatePositionUsecase {
+	return &CreatePositionUsecase{repo: repo, departmentContract: departmentContract, request: request}
+}
+
+func (u *CreatePositionUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return validatePosition(*request.ToPosition(), u.repo, u.departmentContract)
+}
+
+func (u *CreatePositionUsecase) Execute() (*models.Position, *models.SystemError) {
+	request := u.request.Build()
+	position, err := u.repo.Create(*request.ToPosition())
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
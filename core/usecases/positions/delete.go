@@ -0,0 +1,42 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/employees"
+)
+
+type DeletePositionUsecase struct {
+	repo             contracts.PositionContract
+	employeeContract contracts.EmployeeContract
+	positionID       string
+}
+
+func NewDeletePositionUsecase(repo contracts.PositionContract, employeeContract contracts.EmployeeContract, positionID string) *DeletePositionUsecase {
+	return &DeletePositionUsecase{repo: repo, employeeContract: employeeContract, positionID: positionID}
+}
+
+func (u *DeletePositionUsecase) Validate() *models.SystemError {
+	if u.positionID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Position ID is required", nil)
+	}
+	if _, err := u.repo.GetOnce("id", u.positionID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position not found", nil)
+	}
+	assigned, err := employees.CountActiveInPosition(u.employeeContract, u.positionID)
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position still has employees", nil)
+	}
+	return nil
+}
+
+func (u *DeletePositionUsecase) Execute() *models.SystemError {
+	_, err := u.repo.Delete(u.positionID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, err.Error(), nil)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type GetPositionUsecase struct {
+	repo       contracts.PositionContract
+	positionID string
+}
+
+func NewGetPositionUsecase(repo contracts.PositionContract, positionID string) *GetPositionUsecase {
+	return &GetPositionUsecase{repo: repo, positionID: positionID}
+}
+
+func (u *GetPositionUsecase) Validate() *models.SystemError {
+	if u.positionID == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Position ID is required", nil)
+	}
+	return nil
+}
+
+func (u *GetPositionUsecase) Execute() (*models.Position, *models.SystemError) {
+	return u.repo.GetOnce("id", u.positionID)
+}
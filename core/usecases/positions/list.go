@@ -0,0 +1,25 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type ListPositionsUsecase struct {
+	repo    contracts.PositionContract
+	request contracts.IGenericRequest[models.SearchQuery]
+}
+
+func NewListPositionsUsecase(repo contracts.PositionContract, request contracts.IGenericRequest[models.SearchQuery]) *ListPositionsUsecase {
+	return &ListPositionsUsecase{repo: repo, request: request}
+}
+
+func (u *ListPositionsUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate(models.Position{})
+}
+
+func (u *ListPositionsUsecase) Execute() (*models.PaginatedResponse[models.Position], *models.SystemError) {
+	query := u.request.Build()
+	return u.repo.GetByFilter(query)
+}
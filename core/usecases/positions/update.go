@@ -0,0 +1,53 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	"hrms.local/core/usecases/employees"
+)
+
+type UpdatePositionUsecase struct {
+	repo               contracts.PositionContract
+	departmentContract contracts.DepartmentContract
+	employeeContract   contracts.EmployeeContract
+	request            contracts.IGenericRequest[models.Position]
+}
+
+func NewUpdatePositionUsecase(repo contracts.PositionContract, departmentContract contracts.DepartmentContract, employeeContract contracts.EmployeeContract, request contracts.IGenericRequest[models.Position]) *UpdatePositionUsecase {
+	return &UpdatePositionUsecase{repo: repo, departmentContract: departmentContract, employeeContract: employeeContract, request: request}
+}
+
+func (u *UpdatePositionUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	existing, err := u.repo.GetOnce("id", request.ID)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position not found", struct{}{})
+	}
+	if err := validatePosition(request, u.repo, u.departmentContract); err != nil {
+		return err
+	}
+
+	assigned, err := employees.CountActiveInPosition(u.employeeContract, request.ID)
+	if err != nil {
+		return err
+	}
+	if assigned > 0 && existing.DepartmentID != request.DepartmentID {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "cannot move a position with employees to another department", struct{}{})
+	}
+	if request.Headcount > 0 && assigned > int64(request.Headcount) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "headcount cannot be lower than the employees assigned to the position", struct{}{})
+	}
+	return nil
+}
+
+func (u *UpdatePositionUsecase) Execute() (*models.Position, *models.SystemError) {
+	request := u.request.Build()
+	position, err := u.repo.Update(request.ID, request)
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
@@ -0,0 +1,29 @@
+package positions
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// validatePosition checks that the department exists and that the name is unique inside it
+func validatePosition(position models.Position, positionContract contracts.PositionContract, departmentContract contracts.DepartmentContract) *models.SystemError {
+	if _, err := departmentContract.GetOnce("id", position.DepartmentID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "department not found", struct{}{})
+	}
+
+	paginatedData, err := positionContract.GetByFilter(models.SearchQuery{
+		Filters: models.Filters{
+			{Key: "DepartmentID", Value: position.DepartmentID},
+			{Key: "Name", Value: position.Name},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range paginatedData.Rows {
+		if row.ID != position.ID {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "position already exists in the department", struct{}{})
+		}
+	}
+	return nil
+}
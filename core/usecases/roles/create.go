@@ -34,6 +34,7 @@ func (u *CreateRoleUsecase) Execute() (*models.RoleItem, *models.SystemError) {
 		Name:        request.Name,
 		Description: request.Description,
 		Permissions: request.Permissions,
+		RequireMFA:  request.RequireMFA,
 	}
 	createdRole, err := u.repo.Create(role)
 	if err != nil {
@@ -6,18 +6,23 @@ import (
 )
 
 type DeleteRoleUsecase struct {
-	repo   contracts.RoleContract
-	roleID string
+	repo         contracts.RoleContract
+	userContract contracts.UserContract
+	roleID       string
 }
 
-func NewDeleteRoleUsecase(repo contracts.RoleContract, roleID string) *DeleteRoleUsecase {
-	return &DeleteRoleUsecase{repo: repo, roleID: roleID}
+func NewDeleteRoleUsecase(repo contracts.RoleContract, userContract contracts.UserContract, roleID string) *DeleteRoleUsecase {
+	return &DeleteRoleUsecase{repo: repo, userContract: userContract, roleID: roleID}
 }
 
 func (u *DeleteRoleUsecase) Validate() *models.SystemError {
 	if u.roleID == "" {
 		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Role ID is required", nil)
 	}
+	// roles are soft deleted, so the foreign key of the users cannot protect them
+	if _, err := u.userContract.GetOnce("role_id", u.roleID); err == nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Role is assigned to users", nil)
+	}
 	return nil
 }
 
@@ -15,8 +15,8 @@ func NewListRolesUsecase(repo contracts.RoleContract, request *contracts.Generic
 }
 
 func (u *ListRolesUsecase) Validate() *models.SystemError {
-	// No specific validation needed for list
-	return nil
+	request := u.request.Build()
+	return request.Validate(models.Role{})
 }
 
 func (u *ListRolesUsecase) Execute() (*models.PaginatedResponse[models.Role], *models.SystemError) {
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// searchPermissions is the permission needed to find each kind of record
+var searchPermissions = map[models.SearchKind]string{
+	models.SearchKindUser:     models.PermissionViewUsers,
+	models.SearchKindEmployee: models.PermissionViewEmployees,
+}
+
+// SearchPeopleUseCase finds users and employees, only the kinds the caller may view are searched
+type SearchPeopleUseCase struct {
+	searchContract contracts.SearchContract
+	request        contracts.IGenericRequest[models.PeopleSearch]
+}
+
+func NewSearchPeopleUseCase(searchContract contracts.SearchContract, request contracts.IGenericRequest[models.PeopleSearch]) *SearchPeopleUseCase {
+	return &SearchPeopleUseCase{searchContract: searchContract, request: request}
+}
+
+func (u *SearchPeopleUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if len(viewableKinds(request)) == 0 {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Permiso denegado", struct{}{})
+	}
+	return nil
+}
+
+func (u *SearchPeopleUseCase) Execute() ([]models.SearchResult, *models.SystemError) {
+	request := u.request.Build()
+	request.Kinds = viewableKinds(request)
+	if len(request.Kinds) == 0 {
+		return []models.SearchResult{}, nil
+	}
+	return u.searchContract.Search(request)
+}
+
+// viewableKinds returns the kinds requested that the caller may view
+func viewableKinds(request models.PeopleSearch) []models.SearchKind {
+	kinds := []models.SearchKind{}
+	for _, kind := range []models.SearchKind{models.SearchKindUser, models.SearchKindEmployee} {
+		if request.Searches(kind) && models.HasPermission(request.ActorPermissions, searchPermissions[kind]) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
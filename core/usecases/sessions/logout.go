@@ -0,0 +1,57 @@
+package sessions
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// LogoutUsecase denies the access token of the request until it expires and revokes the refresh tokens.
+// With a refresh token only its family is revoked, other devices stay logged in;
+// without one every session of the user is revoked.
+type LogoutUsecase struct {
+	refreshContract  contracts.RefreshTokenContract
+	denylistContract contracts.TokenDenylistContract
+	userContract     contracts.UserContract
+	request          contracts.IGenericRequest[models.Logout]
+}
+
+func NewLogoutUsecase(refreshContract contracts.RefreshTokenContract, denylistContract contracts.TokenDenylistContract, userContract contracts.UserContract, request contracts.IGenericRequest[models.Logout]) *LogoutUsecase {
+	return &LogoutUsecase{
+		refreshContract:  refreshContract,
+		denylistContract: denylistContract,
+		userContract:     userContract,
+		request:          request,
+	}
+}
+
+func (u *LogoutUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if request.JTI == "" || request.Username == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the access token has no session", struct{}{})
+	}
+	return nil
+}
+
+func (u *LogoutUsecase) Execute() *models.SystemError {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	if _, err := u.denylistContract.Revoke(models.RevokedToken{JTI: request.JTI, ExpiresAt: request.ExpiresAt}); err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return err
+	}
+
+	if request.RefreshToken != "" {
+		token, err := u.refreshContract.GetOnce("token_hash", HashRefreshToken(request.RefreshToken))
+		// tokens of other users are ignored, the caller cannot end sessions it does not own
+		if err == nil && token.UserID == user.ID {
+			return u.refreshContract.RevokeFamily(token.FamilyID, now)
+		}
+		return nil
+	}
+	return u.refreshContract.RevokeByUser(user.ID, now)
+}
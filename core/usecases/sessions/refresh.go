@@ -0,0 +1,88 @@
+package sessions
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// RefreshSessionUsecase exchanges a refresh token for a new one of the same family.
+// Presenting a token that was already exchanged revokes the whole family, the token
+// was stolen or replayed and every session descending from that login is compromised.
+// Deactivated and locked users lose the family too, single sign-on users included.
+type RefreshSessionUsecase struct {
+	refreshContract contracts.RefreshTokenContract
+	userContract    contracts.UserContract
+	roleContract    contracts.RoleContract
+	request         contracts.IGenericRequest[models.RefreshSession]
+}
+
+func NewRefreshSessionUsecase(refreshContract contracts.RefreshTokenContract, userContract contracts.UserContract, roleContract contracts.RoleContract, request contracts.IGenericRequest[models.RefreshSession]) *RefreshSessionUsecase {
+	return &RefreshSessionUsecase{refreshContract: refreshContract, userContract: userContract, roleContract: roleContract, request: request}
+}
+
+func (u *RefreshSessionUsecase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *RefreshSessionUsecase) Execute() (*models.Session, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	current, err := u.refreshContract.GetOnce("token_hash", HashRefreshToken(request.RefreshToken))
+	if err != nil {
+		return nil, invalidRefreshToken()
+	}
+
+	switch current.State(now) {
+	case models.RefreshTokenStateReused:
+		return nil, u.revokeFamily(current.FamilyID, now)
+	case models.RefreshTokenStateExpired:
+		return nil, invalidRefreshToken()
+	}
+
+	user, err := u.userContract.GetOnce("id", current.UserID)
+	if err != nil {
+		return nil, invalidRefreshToken()
+	}
+	if !user.Active || user.IsLocked(now) {
+		if err := u.refreshContract.RevokeFamily(current.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, invalidRefreshToken()
+	}
+	token, next, err := issueRefreshToken(u.refreshContract, current.UserID, current.FamilyID, now)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := u.refreshContract.Revoke(current.ID, next.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// a concurrent request exchanged the same token first
+		return nil, u.revokeFamily(current.FamilyID, now)
+	}
+	data := user.ToUserData()
+	if user.RoleID != "" {
+		// the role may have started requiring MFA since the login
+		role, err := u.roleContract.GetOnce("id", user.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		data = data.WithRole(role, role.Permissions)
+	}
+	return &models.Session{User: *data, RefreshToken: token, ExpiresAt: next.ExpiresAt}, nil
+}
+
+func (u *RefreshSessionUsecase) revokeFamily(familyID string, now time.Time) *models.SystemError {
+	if err := u.refreshContract.RevokeFamily(familyID, now); err != nil {
+		return err
+	}
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "refresh token reuse detected, the session was revoked", struct{}{})
+}
+
+func invalidRefreshToken() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "invalid or expired refresh token", struct{}{})
+}
@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// fakeRefreshTokens keeps the refresh tokens in memory
+type fakeRefreshTokens struct {
+	tokens map[string]*models.RefreshToken
+}
+
+func newFakeRefreshTokens() *fakeRefreshTokens {
+	return &fakeRefreshTokens{tokens: map[string]*models.RefreshToken{}}
+}
+
+func (f *fakeRefreshTokens) GetByFilter(models.SearchQuery) (*models.PaginatedResponse[models.RefreshToken], *models.SystemError) {
+	return nil, nil
+}
+
+func (f *fakeRefreshTokens) Exists(key string, value any) (bool, *models.SystemError) {
+	_, err := f.GetOnce(key, value)
+	return err == nil, nil
+}
+
+func (f *fakeRefreshTokens) GetOnce(key string, value any) (*models.RefreshToken, *models.SystemError) {
+	for _, token := range f.tokens {
+		if key == "token_hash" && token.TokenHash == value {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "GetOnce failed", struct{}{})
+}
+
+func (f *fakeRefreshTokens) Create(item models.RefreshToken) (models.RefreshToken, *models.SystemError) {
+	item.ID = fmt.Sprintf("token-%d", len(f.tokens)+1)
+	f.tokens[item.ID] = &item
+	return item, nil
+}
+
+func (f *fakeRefreshTokens) Update(id string, item models.RefreshToken) (models.RefreshToken, *models.SystemError) {
+	f.tokens[id] = &item
+	return item, nil
+}
+
+func (f *fakeRefreshTokens) Delete(id string) (interface{}, error) {
+	delete(f.tokens, id)
+	return nil, nil
+}
+
+func (f *fakeRefreshTokens) Revoke(id string, replacedByID string, at time.Time) (bool, *models.SystemError) {
+	token, ok := f.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RevokedAt = &at
+	token.ReplacedByID = replacedByID
+	return true, nil
+}
+
+func (f *fakeRefreshTokens) RevokeFamily(familyID string, at time.Time) *models.SystemError {
+	for _, token := range f.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (f *fakeRefreshTokens) RevokeByUser(userID string, at time.Time) *models.SystemError {
+	for _, token := range f.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// active returns how many tokens of the family can still be exchanged
+func (f *fakeRefreshTokens) active(familyID string) int {
+	count := 0
+	for _, token := range f.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// fakeUsers answers GetOnce by id, the refresh only reads users
+type fakeUsers struct {
+	contracts.UserContract
+	users map[string]models.User
+}
+
+func (f *fakeUsers) GetOnce(key string, value any) (*models.User, *models.SystemError) {
+	if user, ok := f.users[value.(string)]; ok && key == "id" {
+		return &user, nil
+	}
+	return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "GetOnce failed", struct{}{})
+}
+
+// startSession opens a session of the user and returns the family of its refresh token
+func startSession(t *testing.T, tokens *fakeRefreshTokens, user models.User) (string, string) {
+	t.Helper()
+	useCase := NewStartSessionUsecase(tokens, user.ToUserData())
+	session, err := useCase.Execute()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	current, err := tokens.GetOnce("token_hash", HashRefreshToken(session.RefreshToken))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	return session.RefreshToken, current.FamilyID
+}
+
+func refresh(tokens *fakeRefreshTokens, users *fakeUsers, token string) (*models.Session, *models.SystemError) {
+	return NewRefreshSessionUsecase(tokens, users, nil, contracts.NewGenericRequest(models.RefreshSession{RefreshToken: token})).Execute()
+}
+
+func TestRefreshSessionRevokesTheFamilyOnReuse(t *testing.T) {
+	tokens := newFakeRefreshTokens()
+	user := models.User{ID: "user", Username: "jdoe", Active: true}
+	users := &fakeUsers{users: map[string]models.User{user.ID: user}}
+	first, family := startSession(t, tokens, user)
+
+	session, err := refresh(tokens, users, first)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if session.RefreshToken == first || tokens.active(family) != 1 {
+		t.Fatalf("Expected the token to be rotated within its family")
+	}
+
+	// the first token was already exchanged, presenting it again ends every session of the login
+	if _, err := refresh(tokens, users, first); err == nil {
+		t.Fatalf("Expected the reused token to be refused")
+	}
+	if tokens.active(family) != 0 {
+		t.Errorf("Expected the family to be revoked, %d tokens still active", tokens.active(family))
+	}
+	if _, err := refresh(tokens, users, session.RefreshToken); err == nil {
+		t.Errorf("Expected the rotated token to be refused after the reuse")
+	}
+}
+
+func TestRefreshSessionRefusesInactiveAndLockedUsers(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+	for name, user := range map[string]models.User{
+		"inactive": {ID: "user", Username: "jdoe"},
+		"locked":   {ID: "user", Username: "jdoe", Active: true, LockedUntil: &lockedUntil},
+		"sso":      {ID: "user", Username: "jdoe", LocalLoginDisabled: true},
+	} {
+		tokens := newFakeRefreshTokens()
+		token, family := startSession(t, tokens, user)
+		users := &fakeUsers{users: map[string]models.User{user.ID: user}}
+		if _, err := refresh(tokens, users, token); err == nil {
+			t.Errorf("%s: expected the refresh to be refused", name)
+		}
+		if tokens.active(family) != 0 {
+			t.Errorf("%s: expected the family to be revoked", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package sessions
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// StartSessionUsecase opens the refresh token family of a user that just logged in.
+//
+// Example Usage:
+//
+//	useCase := sessions.NewStartSessionUsecase(refreshTokenRepo, userData)
+//	if err := useCase.Validate(); err != nil {
+//	    return err
+//	}
+//	session, err := useCase.Execute()
+type StartSessionUsecase struct {
+	refreshContract contracts.RefreshTokenContract
+	user            *models.UserData
+}
+
+func NewStartSessionUsecase(refreshContract contracts.RefreshTokenContract, user *models.UserData) *StartSessionUsecase {
+	return &StartSessionUsecase{refreshContract: refreshContract, user: user}
+}
+
+func (u *StartSessionUsecase) Validate() *models.SystemError {
+	if u.user == nil || u.user.Id == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "user is required", struct{}{})
+	}
+	return nil
+}
+
+func (u *StartSessionUsecase) Execute() (*models.Session, *models.SystemError) {
+	familyID, err := newFamilyID()
+	if err != nil {
+		return nil, err
+	}
+	token, created, err := issueRefreshToken(u.refreshContract, u.user.Id, familyID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &models.Session{User: *u.user, RefreshToken: token, ExpiresAt: created.ExpiresAt}, nil
+}
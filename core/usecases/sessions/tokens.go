@@ -0,0 +1,53 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// refreshTokenBytes is the entropy of a refresh token
+const refreshTokenBytes = 32
+
+// HashRefreshToken returns the value stored for a refresh token
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueRefreshToken stores a new token of the family and returns the value handed to the client
+func issueRefreshToken(refreshContract contracts.RefreshTokenContract, userID, familyID string, now time.Time) (string, *models.RefreshToken, *models.SystemError) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not generate refresh token", struct{}{})
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	created, err := refreshContract.Create(models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashRefreshToken(token),
+		ExpiresAt: now.Add(models.RefreshTokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &created, nil
+}
+
+// newFamilyID returns a random UUID identifying the tokens of a login
+func newFamilyID() (string, *models.SystemError) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not generate session id", struct{}{})
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
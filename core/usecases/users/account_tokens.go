@@ -0,0 +1,349 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// accountTokenBytes is the entropy of the tokens mailed to the users
+const accountTokenBytes = 32
+
+// ForgotPasswordUseCase mails a password reset link. Unknown and inactive emails are silently
+// ignored so the answer does not reveal which emails have an account.
+type ForgotPasswordUseCase struct {
+	userContract     contracts.UserContract
+	tokenContract    contracts.AccountTokenContract
+	mailerContract   contracts.MailerContract
+	templateContract contracts.MailTemplateContract
+	request          contracts.IGenericRequest[models.ForgotPassword]
+}
+
+func NewForgotPasswordUseCase(
+	userContract contracts.UserContract,
+	tokenContract contracts.AccountTokenContract,
+	mailerContract contracts.MailerContract,
+	templateContract contracts.MailTemplateContract,
+	request contracts.IGenericRequest[models.ForgotPassword],
+) *ForgotPasswordUseCase {
+	return &ForgotPasswordUseCase{
+		userContract:     userContract,
+		tokenContract:    tokenContract,
+		mailerContract:   mailerContract,
+		templateContract: templateContract,
+		request:          request,
+	}
+}
+
+func (u *ForgotPasswordUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *ForgotPasswordUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("email", request.Email)
+	// users of single sign-on only have no password to reset
+	if err != nil || !user.Active || user.LocalLoginDisabled {
+		return nil
+	}
+	now := time.Now().UTC()
+	token, err := issueAccountToken(u.tokenContract, user, models.AccountTokenPasswordReset, models.PasswordResetTTL, now)
+	if err != nil {
+		return err
+	}
+	return sendMail(u.templateContract, u.mailerContract, models.MailMessage{
+		Template: models.MailTemplatePasswordReset,
+		Language: request.Language,
+		To:       user.Email,
+		Data:     mailData(user, withToken(request.ResetURL, token), models.PasswordResetTTL),
+	})
+}
+
+// ResetPasswordUseCase sets a new password with the token of a reset link. The token is single use,
+// the sessions of the user are revoked and its lockout is cleared, the link proves the email is theirs.
+type ResetPasswordUseCase struct {
+	userContract         contracts.UserContract
+	tokenContract        contracts.AccountTokenContract
+	refreshContract      contracts.RefreshTokenContract
+	auditContract        contracts.AuditContract
+	policyContract       contracts.PasswordPolicyContract
+	historyContract      contracts.PasswordHistoryContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.ResetPassword]
+}
+
+func NewResetPasswordUseCase(
+	userContract contracts.UserContract,
+	tokenContract contracts.AccountTokenContract,
+	refreshContract contracts.RefreshTokenContract,
+	auditContract contracts.AuditContract,
+	policyContract contracts.PasswordPolicyContract,
+	historyContract contracts.PasswordHistoryContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.ResetPassword],
+) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{
+		userContract:         userContract,
+		tokenContract:        tokenContract,
+		refreshContract:      refreshContract,
+		auditContract:        auditContract,
+		policyContract:       policyContract,
+		historyContract:      historyContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *ResetPasswordUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	token, err := findAccountToken(u.tokenContract, request.Token, models.AccountTokenPasswordReset, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("id", token.UserID)
+	if err != nil {
+		return invalidAccountToken()
+	}
+	return checkNewPassword(u.policyContract, u.historyContract, u.cryptographyContract, user, user.Username, request.NewPassword)
+}
+
+func (u *ResetPasswordUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	token, err := useAccountToken(u.tokenContract, request.Token, models.AccountTokenPasswordReset, now)
+	if err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("id", token.UserID)
+	if err != nil {
+		return invalidAccountToken()
+	}
+	if err := setPassword(u.cryptographyContract, user, request.NewPassword, now); err != nil {
+		return err
+	}
+	updated, err := u.userContract.Update(user.ID, *user)
+	if err != nil {
+		return err
+	}
+	if err := recordPassword(u.policyContract, u.historyContract, updated); err != nil {
+		return err
+	}
+	if err := u.refreshContract.RevokeByUser(updated.ID, now); err != nil {
+		return err
+	}
+	if err := u.userContract.ResetFailedLogins(updated.ID); err != nil {
+		return err
+	}
+	return u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionPasswordReset,
+		Actor:     updated.Username,
+		Subject:   updated.Username,
+		IP:        request.IP,
+		CreatedAt: now,
+	})
+}
+
+// SendEmailVerificationUseCase mails a verification link to the current email of the user
+type SendEmailVerificationUseCase struct {
+	userContract     contracts.UserContract
+	tokenContract    contracts.AccountTokenContract
+	mailerContract   contracts.MailerContract
+	templateContract contracts.MailTemplateContract
+	request          contracts.IGenericRequest[models.SendEmailVerification]
+}
+
+func NewSendEmailVerificationUseCase(
+	userContract contracts.UserContract,
+	tokenContract contracts.AccountTokenContract,
+	mailerContract contracts.MailerContract,
+	templateContract contracts.MailTemplateContract,
+	request contracts.IGenericRequest[models.SendEmailVerification],
+) *SendEmailVerificationUseCase {
+	return &SendEmailVerificationUseCase{
+		userContract:     userContract,
+		tokenContract:    tokenContract,
+		mailerContract:   mailerContract,
+		templateContract: templateContract,
+		request:          request,
+	}
+}
+
+func (u *SendEmailVerificationUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if user.EmailVerifiedAt != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El correo ya está verificado", struct{}{})
+	}
+	return nil
+}
+
+func (u *SendEmailVerificationUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return err
+	}
+	token, err := issueAccountToken(u.tokenContract, user, models.AccountTokenEmailVerification, models.EmailVerificationTTL, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return sendMail(u.templateContract, u.mailerContract, models.MailMessage{
+		Template: models.MailTemplateEmailVerification,
+		Language: request.Language,
+		To:       user.Email,
+		Data:     mailData(user, withToken(request.VerifyURL, token), models.EmailVerificationTTL),
+	})
+}
+
+// VerifyEmailUseCase marks the email of the user as verified with the token of a verification link,
+// the token is refused when the user changed its email after it was sent
+type VerifyEmailUseCase struct {
+	userContract  contracts.UserContract
+	tokenContract contracts.AccountTokenContract
+	request       contracts.IGenericRequest[models.VerifyEmail]
+}
+
+func NewVerifyEmailUseCase(userContract contracts.UserContract, tokenContract contracts.AccountTokenContract, request contracts.IGenericRequest[models.VerifyEmail]) *VerifyEmailUseCase {
+	return &VerifyEmailUseCase{userContract: userContract, tokenContract: tokenContract, request: request}
+}
+
+func (u *VerifyEmailUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	_, err := findAccountToken(u.tokenContract, request.Token, models.AccountTokenEmailVerification, time.Now().UTC())
+	return err
+}
+
+func (u *VerifyEmailUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	token, err := useAccountToken(u.tokenContract, request.Token, models.AccountTokenEmailVerification, now)
+	if err != nil {
+		return err
+	}
+	verified, err := u.userContract.VerifyEmail(token.UserID, token.Email, now)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return invalidAccountToken()
+	}
+	return nil
+}
+
+// issueAccountToken stores a new token for the purpose, voiding the previous ones, and returns the value to mail
+func issueAccountToken(tokenContract contracts.AccountTokenContract, user *models.User, purpose models.AccountTokenPurpose, ttl time.Duration, now time.Time) (string, *models.SystemError) {
+	token, err := newAccountToken()
+	if err != nil {
+		return "", err
+	}
+	if err := tokenContract.Invalidate(user.ID, purpose, now); err != nil {
+		return "", err
+	}
+	_, err = tokenContract.Create(models.AccountToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashAccountToken(token),
+		Email:     user.Email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findAccountToken returns the token while it is usable for the purpose, without spending it
+func findAccountToken(tokenContract contracts.AccountTokenContract, token string, purpose models.AccountTokenPurpose, now time.Time) (*models.AccountToken, *models.SystemError) {
+	stored, err := tokenContract.GetOnce("token_hash", hashAccountToken(token))
+	if err != nil || !stored.Usable(purpose, now) {
+		return nil, invalidAccountToken()
+	}
+	return stored, nil
+}
+
+// useAccountToken spends the token, only one of two concurrent requests succeeds
+func useAccountToken(tokenContract contracts.AccountTokenContract, token string, purpose models.AccountTokenPurpose, now time.Time) (*models.AccountToken, *models.SystemError) {
+	stored, err := findAccountToken(tokenContract, token, purpose, now)
+	if err != nil {
+		return nil, err
+	}
+	used, err := tokenContract.Use(stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, invalidAccountToken()
+	}
+	return stored, nil
+}
+
+// newAccountToken returns a random token to mail, store only its hashAccountToken
+func newAccountToken() (string, *models.SystemError) {
+	raw := make([]byte, accountTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not generate token", struct{}{})
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// withToken appends the token to the page of the client handling the link
+func withToken(page string, token string) string {
+	link, err := url.Parse(page)
+	if err != nil {
+		return page + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+func mailData(user *models.User, link string, ttl time.Duration) map[string]string {
+	name := user.Name
+	if name == "" {
+		name = user.Username
+	}
+	return map[string]string{
+		"Name":  name,
+		"Link":  link,
+		"Hours": strconv.Itoa(int(ttl.Hours())),
+	}
+}
+
+func sendMail(templateContract contracts.MailTemplateContract, mailerContract contracts.MailerContract, message models.MailMessage) *models.SystemError {
+	mail, err := templateContract.Render(message)
+	if err != nil {
+		return err
+	}
+	return mailerContract.Send(mail)
+}
+
+func invalidAccountToken() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El enlace es inválido o expiró", struct{}{})
+}
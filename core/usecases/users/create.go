@@ -1,6 +1,8 @@
 package user
 
 import (
+	"time"
+
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
 )
@@ -24,7 +26,7 @@ import (
 //	request := &contracts.GenericRequest[models.CreateUser]{Data: user}
 //
 //	// 3. Instantiate the UseCase
-//	useCase := user.NewCreateUserUseCase(userRepo, request)
+//	useCase := user.NewCreateUserUseCase(userRepo, roleRepo, passwordPolicy, passwordHistoryRepo, request, securityContext)
 //
 //	// 4. Validate the request
 //	if err := useCase.Validate(); err != nil {
@@ -42,15 +44,21 @@ import (
 //	fmt.Printf("User created: %s\n", createdUser.Username)
 type CreateUserUseCase struct {
 	userContract    contracts.UserContract
+	roleContract    contracts.RoleContract
+	policyContract  contracts.PasswordPolicyContract
+	historyContract contracts.PasswordHistoryContract
 	request         contracts.IGenericRequest[models.CreateUser]
 	securityContext contracts.CryptographyContract
 }
 
 // NewCreateUserUseCase creates a new instance of CreateUserUseCase.
 // It injects the user contract (dependency inversion) and the request data.
-func NewCreateUserUseCase(userContract contracts.UserContract, request contracts.IGenericRequest[models.CreateUser], securityContext contracts.CryptographyContract) *CreateUserUseCase {
+func NewCreateUserUseCase(userContract contracts.UserContract, roleContract contracts.RoleContract, policyContract contracts.PasswordPolicyContract, historyContract contracts.PasswordHistoryContract, request contracts.IGenericRequest[models.CreateUser], securityContext contracts.CryptographyContract) *CreateUserUseCase {
 	return &CreateUserUseCase{
 		userContract:    userContract,
+		roleContract:    roleContract,
+		policyContract:  policyContract,
+		historyContract: historyContract,
 		request:         request,
 		securityContext: securityContext,
 	}
@@ -79,7 +87,10 @@ func (u *CreateUserUseCase) Validate() *models.SystemError {
 	if len(paginatedData.Rows) > 0 {
 		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario ya existe", struct{}{})
 	}
-	return nil
+	if err := checkNewPassword(u.policyContract, u.historyContract, u.securityContext, nil, request.Username, request.Password); err != nil {
+		return err
+	}
+	return validateRole(u.roleContract, request.RoleID)
 }
 
 // Execute performs the user creation operation.
@@ -88,14 +99,15 @@ func (u *CreateUserUseCase) Execute() (*models.UserData, *models.SystemError) {
 	request := u.request.Build()
 	newUser := request.ToUser()
 	newUser.Active = true
-	password, err := u.securityContext.EncodePassword(request.Password)
-	if err != nil {
+	if err := setPassword(u.securityContext, newUser, request.Password, time.Now().UTC()); err != nil {
 		return nil, err
 	}
-	newUser.Password = password
 	user, err := u.userContract.Create(*newUser)
 	if err != nil {
 		return nil, err
 	}
+	if err := recordPassword(u.policyContract, u.historyContract, user); err != nil {
+		return nil, err
+	}
 	return user.ToUserData(), nil
 }
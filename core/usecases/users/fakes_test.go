@@ -0,0 +1,280 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+func notFound() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "GetOnce failed", struct{}{})
+}
+
+// fakeUsers keeps the users in memory, the lockout and MFA columns behave like the repository
+type fakeUsers struct {
+	users map[string]*models.User
+}
+
+func newFakeUsers(users ...models.User) *fakeUsers {
+	f := &fakeUsers{users: map[string]*models.User{}}
+	for i := range users {
+		f.users[users[i].ID] = &users[i]
+	}
+	return f
+}
+
+func (f *fakeUsers) GetByFilter(models.SearchQuery) (*models.PaginatedResponse[models.User], *models.SystemError) {
+	return nil, nil
+}
+
+func (f *fakeUsers) Exists(key string, value any) (bool, *models.SystemError) {
+	_, err := f.GetOnce(key, value)
+	return err == nil, nil
+}
+
+func (f *fakeUsers) GetOnce(key string, value any) (*models.User, *models.SystemError) {
+	for _, user := range f.users {
+		field := map[string]string{"id": user.ID, "username": user.Username, "email": user.Email, "external_id": user.ExternalID}[key]
+		if field != "" && field == value {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, notFound()
+}
+
+func (f *fakeUsers) Create(item models.User) (models.User, *models.SystemError) {
+	item.ID = fmt.Sprintf("user-%d", len(f.users)+1)
+	f.users[item.ID] = &item
+	return item, nil
+}
+
+func (f *fakeUsers) Update(id string, item models.User) (models.User, *models.SystemError) {
+	f.users[id] = &item
+	return item, nil
+}
+
+func (f *fakeUsers) Delete(id string) (interface{}, error) {
+	delete(f.users, id)
+	return nil, nil
+}
+
+func (f *fakeUsers) RegisterFailedLogin(id string) (int, *models.SystemError) {
+	f.users[id].FailedLoginAttempts++
+	return f.users[id].FailedLoginAttempts, nil
+}
+
+func (f *fakeUsers) Lock(id string, until time.Time) *models.SystemError {
+	f.users[id].LockedUntil = &until
+	return nil
+}
+
+func (f *fakeUsers) ResetFailedLogins(id string) *models.SystemError {
+	f.users[id].FailedLoginAttempts = 0
+	f.users[id].LockedUntil = nil
+	return nil
+}
+
+func (f *fakeUsers) UpdateMFA(id string, secret string, enabled bool) *models.SystemError {
+	f.users[id].MFASecret = secret
+	f.users[id].MFAEnabled = enabled
+	f.users[id].MFALastStep = 0
+	return nil
+}
+
+func (f *fakeUsers) AcceptMFAStep(id string, step int64) (bool, *models.SystemError) {
+	if f.users[id].MFALastStep >= step {
+		return false, nil
+	}
+	f.users[id].MFALastStep = step
+	return true, nil
+}
+
+func (f *fakeUsers) VerifyEmail(id string, email string, at time.Time) (bool, *models.SystemError) {
+	if f.users[id].Email != email {
+		return false, nil
+	}
+	f.users[id].EmailVerifiedAt = &at
+	return true, nil
+}
+
+// fakeCryptography hashes by prefixing the password and remembers the hashes compared
+type fakeCryptography struct {
+	compared []string
+}
+
+func (f *fakeCryptography) EncodePassword(password string) (string, *models.SystemError) {
+	return "hashed:" + password, nil
+}
+
+func (f *fakeCryptography) ComparePassword(password string, hash string) (bool, *models.SystemError) {
+	f.compared = append(f.compared, hash)
+	return hash == "hashed:"+password, nil
+}
+
+// fakeAudit records the entries, the use cases never read them
+type fakeAudit struct {
+	contracts.AuditContract
+	entries []models.AuditEntry
+}
+
+func (f *fakeAudit) Record(entry models.AuditEntry) *models.SystemError {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+// fakePolicy accepts every password and never expires them
+type fakePolicy struct{}
+
+func (fakePolicy) Policy() models.PasswordPolicy {
+	return models.PasswordPolicy{}
+}
+
+func (fakePolicy) Check(string, string) []models.PasswordViolation {
+	return nil
+}
+
+// fakeDenylist keeps the revoked token ids, revoking one twice reports it was already revoked
+type fakeDenylist struct {
+	revoked map[string]bool
+}
+
+func (f *fakeDenylist) Revoke(token models.RevokedToken) (bool, *models.SystemError) {
+	if f.revoked[token.JTI] {
+		return false, nil
+	}
+	f.revoked[token.JTI] = true
+	return true, nil
+}
+
+func (f *fakeDenylist) IsRevoked(jti string) (bool, *models.SystemError) {
+	return f.revoked[jti], nil
+}
+
+// fakeSigner hands out opaque tokens and returns the claims they were signed with
+type fakeSigner struct {
+	signed map[string]map[string]any
+}
+
+func (f *fakeSigner) Sign(claims map[string]any) (string, *models.SystemError) {
+	token := fmt.Sprintf("signed-%d", len(f.signed)+1)
+	f.signed[token] = claims
+	return token, nil
+}
+
+func (f *fakeSigner) Verify(token string) (map[string]any, *models.SystemError) {
+	claims, ok := f.signed[token]
+	if !ok {
+		return nil, notFound()
+	}
+	return claims, nil
+}
+
+func (f *fakeSigner) PublicKeys() models.JSONWebKeySet {
+	return models.JSONWebKeySet{}
+}
+
+// fakeOTP accepts the codes listed for their time step
+type fakeOTP struct {
+	steps map[string]int64
+}
+
+func (f *fakeOTP) GenerateSecret() (string, *models.SystemError) {
+	return "secret", nil
+}
+
+func (f *fakeOTP) URI(account string, secret string) string {
+	return "otpauth://totp/" + account
+}
+
+func (f *fakeOTP) Verify(secret string, code string, at time.Time) (int64, bool) {
+	step, ok := f.steps[code]
+	return step, ok
+}
+
+// fakeRecovery holds no recovery codes
+type fakeRecovery struct{}
+
+func (fakeRecovery) Replace(string, []string) *models.SystemError {
+	return nil
+}
+
+func (fakeRecovery) Unused(string) ([]models.RecoveryCode, *models.SystemError) {
+	return nil, nil
+}
+
+func (fakeRecovery) Use(string, time.Time) (bool, *models.SystemError) {
+	return false, nil
+}
+
+func (fakeRecovery) DeleteByUser(string) *models.SystemError {
+	return nil
+}
+
+// fakeRoles answers GetOnce by name, the use cases only resolve roles
+type fakeRoles struct {
+	contracts.RoleContract
+	roles []models.Role
+}
+
+func (f *fakeRoles) GetOnce(key string, value any) (*models.Role, *models.SystemError) {
+	for _, role := range f.roles {
+		if (key == "name" && role.Name == value) || (key == "id" && role.ID == value) {
+			found := role
+			return &found, nil
+		}
+	}
+	return nil, notFound()
+}
+
+// fakeInvitations keeps the invitations in memory, accepting one marks it used
+type fakeInvitations struct {
+	contracts.InvitationContract
+	invitations map[string]*models.Invitation
+}
+
+func (f *fakeInvitations) GetOnce(key string, value any) (*models.Invitation, *models.SystemError) {
+	for _, invitation := range f.invitations {
+		if key == "token_hash" && invitation.TokenHash == value {
+			found := *invitation
+			return &found, nil
+		}
+	}
+	return nil, notFound()
+}
+
+func (f *fakeInvitations) Accept(id string, at time.Time) (bool, *models.SystemError) {
+	invitation, ok := f.invitations[id]
+	if !ok || !invitation.Usable(at) {
+		return false, nil
+	}
+	invitation.UsedAt = &at
+	return true, nil
+}
+
+// fakeRefreshTokens records the users whose sessions were revoked
+type fakeRefreshTokens struct {
+	contracts.RefreshTokenContract
+	revokedUsers []string
+}
+
+func (f *fakeRefreshTokens) RevokeByUser(userID string, at time.Time) *models.SystemError {
+	f.revokedUsers = append(f.revokedUsers, userID)
+	return nil
+}
+
+// fakeOIDC signs in the identity it holds whatever the code
+type fakeOIDC struct {
+	identity models.OIDCIdentity
+}
+
+func (f *fakeOIDC) AuthorizationURL(state string, nonce string, codeChallenge string) (string, *models.SystemError) {
+	return "https://issuer.example.com/authorize?state=" + state, nil
+}
+
+func (f *fakeOIDC) Exchange(code string, codeVerifier string, nonce string) (*models.OIDCIdentity, *models.SystemError) {
+	identity := f.identity
+	return &identity, nil
+}
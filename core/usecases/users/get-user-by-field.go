@@ -17,19 +17,22 @@ func NewGetUserByFieldUseCase(userContract contracts.UserContract, request contr
 	}
 }
 
+// lookupFields are the fields identifying a single user
+var lookupFields = map[string]bool{"ID": true, "Username": true, "Email": true}
+
 func (u *GetUserByFieldUseCase) Validate() *models.SystemError {
 	request := u.request.Build()
 	if request.Key == "" || request.Value == "" {
 		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Key and Value are required", nil)
 	}
-	if request.Key != "username" && request.Key != "email" && request.Key != "id" {
+	if name, _, ok := (models.User{}).SearchFields().Lookup(request.Key); !ok || !lookupFields[name] {
 		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Key must be username, email or id", nil)
 	}
-	exists, err := u.userContract.Exists(request.Key, request.Value)
+	user, err := u.find(request)
 	if err != nil {
 		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Error checking if user exists", nil)
 	}
-	if !exists {
+	if user == nil {
 		return models.NewSystemError(models.SystemErrorCodeNone, models.SystemErrorType(models.SystemErrorLevelError), models.SystemErrorLevelError, "User not found", nil)
 	}
 
@@ -38,8 +41,8 @@ func (u *GetUserByFieldUseCase) Validate() *models.SystemError {
 
 func (u *GetUserByFieldUseCase) Execute() (*models.UserData, *models.SystemError) {
 	request := u.request.Build()
-	user, err := u.userContract.GetOnce(request.Key, request.Value)
-	if err != nil {
+	user, err := u.find(request)
+	if err != nil || user == nil {
 		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Error getting user", nil)
 	}
 	result := &models.UserData{
@@ -50,8 +53,24 @@ func (u *GetUserByFieldUseCase) Execute() (*models.UserData, *models.SystemError
 		Email:    user.Email,
 		Type:     user.Type,
 		Picture:  user.Picture,
-		Role:     user.Role,
+		RoleID:   user.RoleID,
 		Active:   user.Active,
 	}
 	return result, nil
 }
+
+// find returns the user matching the filter, nil when there is none. The key goes through the
+// search fields of the user like any other filter, so it never reaches SQL as given.
+func (u *GetUserByFieldUseCase) find(filter models.Filter) (*models.User, *models.SystemError) {
+	paginatedData, err := u.userContract.GetByFilter(models.SearchQuery{
+		Filters:    models.Filters{filter},
+		Pagination: models.Pagination{Page: 1, Limit: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(paginatedData.Rows) == 0 {
+		return nil, nil
+	}
+	return &paginatedData.Rows[0], nil
+}
@@ -58,7 +58,7 @@ func NewListUserUseCase(userContract contracts.UserContract, request contracts.I
 // It checks if the request is empty and validates each filter against the User model using reflection.
 func (u *ListUserUseCase) Validate() *models.SystemError {
 	request := u.request.Build()
-	if err := request.Filters.Validate(models.User{}); err != nil {
+	if err := request.Validate(models.User{}); err != nil {
 		return err
 	}
 	return nil
@@ -83,14 +83,17 @@ func (u *ListUserUseCase) Execute() (*models.PaginatedResponse[*models.UserData]
 			Email:    paginatedData.Rows[i].Email,
 			Type:     paginatedData.Rows[i].Type,
 			Picture:  paginatedData.Rows[i].Picture,
-			Role:     paginatedData.Rows[i].Role,
+			RoleID:   paginatedData.Rows[i].RoleID,
 			Active:   paginatedData.Rows[i].Active,
 		})
 	}
 
 	return &models.PaginatedResponse[*models.UserData]{
-		TotalRows:  paginatedData.TotalRows,
-		TotalPages: paginatedData.TotalPages,
-		Rows:       result,
+		TotalRows:      paginatedData.TotalRows,
+		TotalPages:     paginatedData.TotalPages,
+		TotalEstimated: paginatedData.TotalEstimated,
+		NextCursor:     paginatedData.NextCursor,
+		PrevCursor:     paginatedData.PrevCursor,
+		Rows:           result,
 	}, nil
 }
@@ -1,19 +1,40 @@
 package user
 
 import (
+	"fmt"
+	"time"
+
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
 )
 
+// timingPasswordHash is compared when the username does not exist or the account cannot log in,
+// so unknown usernames and locked or deactivated accounts take as long to reject as wrong passwords
+const timingPasswordHash = "$2a$10$yh6cyBGGZYaiJn5IJfanm.mNqSqQXm7fYd.vpRrHeOTv5whnWObAO"
+
+// LoginUserUseCase checks the credentials of a user. Every failure returns the same error so callers
+// cannot tell unknown usernames, wrong passwords, locked and deactivated accounts apart; consecutive failures lock
+// the account for a time that doubles with each failure past models.LoginLockoutThreshold.
+// Users whose password expired log in flagged with MustChangePassword, users with MFA enabled
+// receive a challenge to complete with VerifyMFAUseCase instead of being logged in.
+// Users with LocalLoginDisabled only sign in through OIDCLoginUseCase.
 type LoginUserUseCase struct {
 	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	auditContract        contracts.AuditContract
+	policyContract       contracts.PasswordPolicyContract
+	signerContract       contracts.TokenSignerContract
 	request              contracts.IGenericRequest[models.LoginUser]
 	cryptographyContract contracts.CryptographyContract
 }
 
-func NewLoginUserUseCase(userContract contracts.UserContract, request contracts.IGenericRequest[models.LoginUser], cryptographyContract contracts.CryptographyContract) *LoginUserUseCase {
+func NewLoginUserUseCase(userContract contracts.UserContract, roleContract contracts.RoleContract, auditContract contracts.AuditContract, policyContract contracts.PasswordPolicyContract, signerContract contracts.TokenSignerContract, request contracts.IGenericRequest[models.LoginUser], cryptographyContract contracts.CryptographyContract) *LoginUserUseCase {
 	return &LoginUserUseCase{
 		userContract:         userContract,
+		roleContract:         roleContract,
+		auditContract:        auditContract,
+		policyContract:       policyContract,
+		signerContract:       signerContract,
 		request:              request,
 		cryptographyContract: cryptographyContract,
 	}
@@ -24,45 +45,82 @@ func (u *LoginUserUseCase) Validate() *models.SystemError {
 	if request.Username == "" || request.Password == "" {
 		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "request is empty", struct{}{})
 	}
-	paginatedData, err := u.userContract.GetByFilter(models.SearchQuery{
-		Filters: models.Filters{
-			{
-				Key:   "Username",
-				Value: request.Username,
-			},
-		},
-	})
+	return nil
+}
+
+func (u *LoginUserUseCase) Execute() (*models.LoginResult, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	user, err := u.userContract.GetOnce("username", request.Username)
 	if err != nil {
-		return err
+		u.cryptographyContract.ComparePassword(request.Password, timingPasswordHash)
+		return nil, invalidCredentials()
 	}
-	if len(paginatedData.Rows) == 0 {
-		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	if !user.Active || user.IsLocked(now) || user.LocalLoginDisabled {
+		u.cryptographyContract.ComparePassword(request.Password, timingPasswordHash)
+		return nil, invalidCredentials()
 	}
 
 	// Compare the plain text password with the hashed password
-	isValid, err := u.cryptographyContract.ComparePassword(request.Password, paginatedData.Rows[0].Password)
+	isValid, err := u.cryptographyContract.ComparePassword(request.Password, user.Password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !isValid {
-		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Contraseña incorrecta", struct{}{})
+		if err := registerLoginFailure(u.userContract, u.auditContract, user, request.IP, now); err != nil {
+			return nil, err
+		}
+		return nil, invalidCredentials()
 	}
 
-	return nil
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := u.userContract.ResetFailedLogins(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if !user.MustChangePassword && u.policyContract.Policy().Expired(user.PasswordChangedAt, now) {
+		user.MustChangePassword = true
+		if _, err := u.userContract.Update(user.ID, *user); err != nil {
+			return nil, err
+		}
+	}
+	if user.MFAEnabled {
+		challenge, err := newMFAChallenge(u.signerContract, user.Username, now)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResult{Challenge: challenge}, nil
+	}
+	data, err := withRole(*user, u.roleContract)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{User: data}, nil
 }
 
-func (u *LoginUserUseCase) Execute() (*models.UserData, *models.SystemError) {
-	request := u.request.Build()
-	paginatedData, err := u.userContract.GetByFilter(models.SearchQuery{
-		Filters: models.Filters{
-			{
-				Key:   "Username",
-				Value: request.Username,
-			},
-		},
-	})
+// registerLoginFailure counts a wrong password or MFA code and locks the account once the threshold is reached
+func registerLoginFailure(userContract contracts.UserContract, auditContract contracts.AuditContract, user *models.User, ip string, now time.Time) *models.SystemError {
+	attempts, err := userContract.RegisterFailedLogin(user.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return paginatedData.Rows[0].ToUserData(), nil
+	lock := models.LockoutDuration(attempts)
+	if lock == 0 {
+		return nil
+	}
+	until := now.Add(lock)
+	if err := userContract.Lock(user.ID, until); err != nil {
+		return err
+	}
+	return auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionAccountLocked,
+		Subject:   user.Username,
+		IP:        ip,
+		Details:   fmt.Sprintf("locked until %s after %d failed logins", until.Format(time.RFC3339), attempts),
+		CreatedAt: now,
+	})
+}
+
+func invalidCredentials() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Usuario o contraseña incorrectos", struct{}{})
 }
@@ -0,0 +1,79 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+func login(users *fakeUsers, audit *fakeAudit, crypto *fakeCryptography, password string) (*models.LoginResult, *models.SystemError) {
+	request := contracts.NewGenericRequest(models.LoginUser{Username: "jdoe", Password: password})
+	return NewLoginUserUseCase(users, nil, audit, fakePolicy{}, nil, request, crypto).Execute()
+}
+
+func TestLoginLockoutEscalates(t *testing.T) {
+	users := newFakeUsers(models.User{ID: "user", Username: "jdoe", Password: "hashed:secret", Active: true})
+	audit := &fakeAudit{}
+	crypto := &fakeCryptography{}
+
+	for i := 1; i < models.LoginLockoutThreshold; i++ {
+		if _, err := login(users, audit, crypto, "wrong"); err == nil {
+			t.Fatalf("Expected the wrong password to be refused")
+		}
+	}
+	if users.users["user"].LockedUntil != nil {
+		t.Fatalf("Expected the account to stay unlocked below the threshold")
+	}
+
+	before := time.Now()
+	if _, err := login(users, audit, crypto, "wrong"); err == nil {
+		t.Fatalf("Expected the wrong password to be refused")
+	}
+	first := users.users["user"].LockedUntil
+	if first == nil || first.Sub(before) < time.Minute || len(audit.entries) != 1 {
+		t.Fatalf("Expected the threshold to lock the account for a minute and audit it, got %v", first)
+	}
+
+	// a locked account refuses the right password, comparing the timing hash instead of its own
+	crypto.compared = nil
+	if _, err := login(users, audit, crypto, "secret"); err == nil {
+		t.Fatalf("Expected the locked account to be refused")
+	}
+	if len(crypto.compared) != 1 || crypto.compared[0] != timingPasswordHash {
+		t.Errorf("Expected the locked account to be compared with the timing hash, got %v", crypto.compared)
+	}
+
+	// every failure after the lock expires doubles it
+	expired := time.Now().Add(-time.Second)
+	users.users["user"].LockedUntil = &expired
+	before = time.Now()
+	if _, err := login(users, audit, crypto, "wrong"); err == nil {
+		t.Fatalf("Expected the wrong password to be refused")
+	}
+	second := users.users["user"].LockedUntil
+	if second == nil || second.Sub(before) < 2*time.Minute || second.Sub(before) > 3*time.Minute {
+		t.Fatalf("Expected the second lock to last two minutes, got %v", second)
+	}
+
+	users.users["user"].LockedUntil = &expired
+	result, err := login(users, audit, crypto, "secret")
+	if err != nil || result.User == nil {
+		t.Fatalf("Expected the right password to log in once the lock expired, got %v", err)
+	}
+	if users.users["user"].FailedLoginAttempts != 0 || users.users["user"].LockedUntil != nil {
+		t.Errorf("Expected the login to reset the failed attempts")
+	}
+}
+
+func TestLoginRefusesInactiveUsers(t *testing.T) {
+	users := newFakeUsers(models.User{ID: "user", Username: "jdoe", Password: "hashed:secret"})
+	crypto := &fakeCryptography{}
+	if _, err := login(users, &fakeAudit{}, crypto, "secret"); err == nil {
+		t.Fatalf("Expected the inactive user to be refused")
+	}
+	if len(crypto.compared) != 1 || crypto.compared[0] != timingPasswordHash {
+		t.Errorf("Expected the inactive user to be compared with the timing hash, got %v", crypto.compared)
+	}
+}
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// GetCurrentUserUseCase returns the profile of the authenticated user with its role and effective permissions
+type GetCurrentUserUseCase struct {
+	userContract contracts.UserContract
+	roleContract contracts.RoleContract
+	username     string
+}
+
+func NewGetCurrentUserUseCase(userContract contracts.UserContract, roleContract contracts.RoleContract, username string) *GetCurrentUserUseCase {
+	return &GetCurrentUserUseCase{
+		userContract: userContract,
+		roleContract: roleContract,
+		username:     username,
+	}
+}
+
+func (u *GetCurrentUserUseCase) Validate() *models.SystemError {
+	if u.username == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "username is required", struct{}{})
+	}
+	return nil
+}
+
+func (u *GetCurrentUserUseCase) Execute() (*models.UserData, *models.SystemError) {
+	user, err := u.userContract.GetOnce("username", u.username)
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	return withRole(*user, u.roleContract)
+}
+
+// UpdateProfileUseCase lets the authenticated user change its name, picture and password.
+// Changing the password requires the current one, follows the password policy and revokes every session of the user.
+type UpdateProfileUseCase struct {
+	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	refreshContract      contracts.RefreshTokenContract
+	policyContract       contracts.PasswordPolicyContract
+	historyContract      contracts.PasswordHistoryContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.UpdateProfile]
+}
+
+func NewUpdateProfileUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	refreshContract contracts.RefreshTokenContract,
+	policyContract contracts.PasswordPolicyContract,
+	historyContract contracts.PasswordHistoryContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.UpdateProfile],
+) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{
+		userContract:         userContract,
+		roleContract:         roleContract,
+		refreshContract:      refreshContract,
+		policyContract:       policyContract,
+		historyContract:      historyContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *UpdateProfileUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if request.NewPassword != "" {
+		valid, err := u.cryptographyContract.ComparePassword(request.CurrentPassword, user.Password)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Contraseña incorrecta", struct{}{})
+		}
+		return checkNewPassword(u.policyContract, u.historyContract, u.cryptographyContract, user, user.Username, request.NewPassword)
+	}
+	return nil
+}
+
+func (u *UpdateProfileUseCase) Execute() (*models.UserData, *models.SystemError) {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return nil, err
+	}
+	request.ApplyTo(user)
+	now := time.Now().UTC()
+	if request.NewPassword != "" {
+		if err := setPassword(u.cryptographyContract, user, request.NewPassword, now); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := u.userContract.Update(user.ID, *user)
+	if err != nil {
+		return nil, err
+	}
+	if request.NewPassword != "" {
+		if err := recordPassword(u.policyContract, u.historyContract, updated); err != nil {
+			return nil, err
+		}
+		if err := u.refreshContract.RevokeByUser(updated.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return withRole(updated, u.roleContract)
+}
@@ -0,0 +1,517 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// VerifyMFAUseCase completes a login challenged by LoginUserUseCase with the code of the
+// authenticator app or a recovery code. Wrong codes count as failed logins and lock the account.
+// The challenge is spent before the code is checked, a challenge answered twice or with a wrong
+// code cannot be answered again and the user logs in anew.
+type VerifyMFAUseCase struct {
+	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	auditContract        contracts.AuditContract
+	denylistContract     contracts.TokenDenylistContract
+	signerContract       contracts.TokenSignerContract
+	otpContract          contracts.OTPContract
+	recoveryContract     contracts.RecoveryCodeContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.VerifyMFA]
+}
+
+func NewVerifyMFAUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	auditContract contracts.AuditContract,
+	denylistContract contracts.TokenDenylistContract,
+	signerContract contracts.TokenSignerContract,
+	otpContract contracts.OTPContract,
+	recoveryContract contracts.RecoveryCodeContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.VerifyMFA],
+) *VerifyMFAUseCase {
+	return &VerifyMFAUseCase{
+		userContract:         userContract,
+		roleContract:         roleContract,
+		auditContract:        auditContract,
+		denylistContract:     denylistContract,
+		signerContract:       signerContract,
+		otpContract:          otpContract,
+		recoveryContract:     recoveryContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *VerifyMFAUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *VerifyMFAUseCase) Execute() (*models.UserData, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	claims, err := u.signerContract.Verify(request.Token)
+	if err != nil || claims["purpose"] != models.MFAChallengePurpose {
+		return nil, invalidMFACode()
+	}
+	jti, _ := claims["jti"].(string)
+	username, _ := claims["sub"].(string)
+	if jti == "" {
+		return nil, invalidMFACode()
+	}
+	// the challenge is single use, only the request that denies it first goes on
+	expiresAt := now.Add(models.MFAChallengeTTL)
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = time.Unix(int64(exp), 0).UTC()
+	}
+	claimed, err := u.denylistContract.Revoke(models.RevokedToken{JTI: jti, ExpiresAt: expiresAt})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, invalidMFACode()
+	}
+	user, err := u.userContract.GetOnce("username", username)
+	if err != nil || !user.Active || !user.MFAEnabled || user.IsLocked(now) {
+		return nil, invalidMFACode()
+	}
+
+	valid, err := verifySecondFactor(u.userContract, u.otpContract, u.recoveryContract, u.cryptographyContract, user, request.Code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if err := registerLoginFailure(u.userContract, u.auditContract, user, request.IP, now); err != nil {
+			return nil, err
+		}
+		return nil, invalidMFACode()
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := u.userContract.ResetFailedLogins(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return withRole(*user, u.roleContract)
+}
+
+// EnrollMFAUseCase generates the TOTP secret of the authenticated user, MFA is enabled by ActivateMFAUseCase
+type EnrollMFAUseCase struct {
+	userContract contracts.UserContract
+	otpContract  contracts.OTPContract
+	username     string
+}
+
+func NewEnrollMFAUseCase(userContract contracts.UserContract, otpContract contracts.OTPContract, username string) *EnrollMFAUseCase {
+	return &EnrollMFAUseCase{userContract: userContract, otpContract: otpContract, username: username}
+}
+
+func (u *EnrollMFAUseCase) Validate() *models.SystemError {
+	user, err := u.userContract.GetOnce("username", u.username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if user.MFAEnabled {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La verificación en dos pasos ya está activada", struct{}{})
+	}
+	return nil
+}
+
+func (u *EnrollMFAUseCase) Execute() (*models.MFAEnrollment, *models.SystemError) {
+	user, err := u.userContract.GetOnce("username", u.username)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := u.otpContract.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userContract.UpdateMFA(user.ID, secret, false); err != nil {
+		return nil, err
+	}
+	return &models.MFAEnrollment{Secret: secret, URI: u.otpContract.URI(user.Username, secret)}, nil
+}
+
+// ActivateMFAUseCase enables MFA once the user proves its app generates valid codes and hands out the recovery codes
+type ActivateMFAUseCase struct {
+	userContract         contracts.UserContract
+	otpContract          contracts.OTPContract
+	recoveryContract     contracts.RecoveryCodeContract
+	auditContract        contracts.AuditContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.MFACode]
+}
+
+func NewActivateMFAUseCase(
+	userContract contracts.UserContract,
+	otpContract contracts.OTPContract,
+	recoveryContract contracts.RecoveryCodeContract,
+	auditContract contracts.AuditContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.MFACode],
+) *ActivateMFAUseCase {
+	return &ActivateMFAUseCase{
+		userContract:         userContract,
+		otpContract:          otpContract,
+		recoveryContract:     recoveryContract,
+		auditContract:        auditContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *ActivateMFAUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if user.MFAEnabled {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La verificación en dos pasos ya está activada", struct{}{})
+	}
+	if user.MFASecret == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Debe iniciar la configuración de la verificación en dos pasos", struct{}{})
+	}
+	if _, ok := u.otpContract.Verify(user.MFASecret, normalizeCode(request.Code), time.Now().UTC()); !ok {
+		return invalidMFACode()
+	}
+	return nil
+}
+
+func (u *ActivateMFAUseCase) Execute() (*models.MFARecoveryCodes, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := u.otpContract.Verify(user.MFASecret, normalizeCode(request.Code), now)
+	if !ok {
+		return nil, invalidMFACode()
+	}
+	if err := u.userContract.UpdateMFA(user.ID, user.MFASecret, true); err != nil {
+		return nil, err
+	}
+	if _, err := u.userContract.AcceptMFAStep(user.ID, step); err != nil {
+		return nil, err
+	}
+	codes, err := replaceRecoveryCodes(u.recoveryContract, u.cryptographyContract, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.auditContract.Record(models.AuditEntry{Action: models.AuditActionMFAEnabled, Actor: user.Username, Subject: user.Username, IP: request.IP, CreatedAt: now}); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableMFAUseCase turns MFA off for the authenticated user, it needs the password and a code
+// and is refused when the role of the user requires MFA
+type DisableMFAUseCase struct {
+	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	otpContract          contracts.OTPContract
+	recoveryContract     contracts.RecoveryCodeContract
+	auditContract        contracts.AuditContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.MFACode]
+}
+
+func NewDisableMFAUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	otpContract contracts.OTPContract,
+	recoveryContract contracts.RecoveryCodeContract,
+	auditContract contracts.AuditContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.MFACode],
+) *DisableMFAUseCase {
+	return &DisableMFAUseCase{
+		userContract:         userContract,
+		roleContract:         roleContract,
+		otpContract:          otpContract,
+		recoveryContract:     recoveryContract,
+		auditContract:        auditContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *DisableMFAUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.Password == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "password is required", struct{}{})
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if !user.MFAEnabled {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La verificación en dos pasos no está activada", struct{}{})
+	}
+	valid, err := u.cryptographyContract.ComparePassword(request.Password, user.Password)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Contraseña incorrecta", struct{}{})
+	}
+	if user.RoleID != "" {
+		role, err := u.roleContract.GetOnce("id", user.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.RequireMFA {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Su rol requiere la verificación en dos pasos", struct{}{})
+		}
+	}
+	return nil
+}
+
+func (u *DisableMFAUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return err
+	}
+	valid, err := verifySecondFactor(u.userContract, u.otpContract, u.recoveryContract, u.cryptographyContract, user, request.Code, now)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return invalidMFACode()
+	}
+	if err := clearMFA(u.userContract, u.recoveryContract, user.ID); err != nil {
+		return err
+	}
+	return u.auditContract.Record(models.AuditEntry{Action: models.AuditActionMFADisabled, Actor: user.Username, Subject: user.Username, IP: request.IP, CreatedAt: now})
+}
+
+// RegenerateRecoveryCodesUseCase replaces the recovery codes of the authenticated user, the old ones stop working
+type RegenerateRecoveryCodesUseCase struct {
+	userContract         contracts.UserContract
+	otpContract          contracts.OTPContract
+	recoveryContract     contracts.RecoveryCodeContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.MFACode]
+}
+
+func NewRegenerateRecoveryCodesUseCase(
+	userContract contracts.UserContract,
+	otpContract contracts.OTPContract,
+	recoveryContract contracts.RecoveryCodeContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.MFACode],
+) *RegenerateRecoveryCodesUseCase {
+	return &RegenerateRecoveryCodesUseCase{
+		userContract:         userContract,
+		otpContract:          otpContract,
+		recoveryContract:     recoveryContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
+	}
+}
+
+func (u *RegenerateRecoveryCodesUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	if !user.MFAEnabled {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La verificación en dos pasos no está activada", struct{}{})
+	}
+	return nil
+}
+
+func (u *RegenerateRecoveryCodesUseCase) Execute() (*models.MFARecoveryCodes, *models.SystemError) {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return nil, err
+	}
+	// only the app proves possession, a recovery code cannot mint new ones
+	step, ok := u.otpContract.Verify(user.MFASecret, normalizeCode(request.Code), time.Now().UTC())
+	if !ok {
+		return nil, invalidMFACode()
+	}
+	accepted, err := u.userContract.AcceptMFAStep(user.ID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, invalidMFACode()
+	}
+	return replaceRecoveryCodes(u.recoveryContract, u.cryptographyContract, user.ID)
+}
+
+// ResetMFAUseCase lets an administrator turn off the MFA of a user that lost its device,
+// the user has to enroll again when its role requires MFA
+type ResetMFAUseCase struct {
+	userContract     contracts.UserContract
+	recoveryContract contracts.RecoveryCodeContract
+	auditContract    contracts.AuditContract
+	request          contracts.IGenericRequest[models.UnlockUser]
+}
+
+func NewResetMFAUseCase(userContract contracts.UserContract, recoveryContract contracts.RecoveryCodeContract, auditContract contracts.AuditContract, request contracts.IGenericRequest[models.UnlockUser]) *ResetMFAUseCase {
+	return &ResetMFAUseCase{
+		userContract:     userContract,
+		recoveryContract: recoveryContract,
+		auditContract:    auditContract,
+		request:          request,
+	}
+}
+
+func (u *ResetMFAUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.userContract.GetOnce("username", request.Username); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	return nil
+}
+
+func (u *ResetMFAUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return err
+	}
+	if err := clearMFA(u.userContract, u.recoveryContract, user.ID); err != nil {
+		return err
+	}
+	return u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionMFAReset,
+		Actor:     request.Actor,
+		Subject:   user.Username,
+		IP:        request.IP,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// newMFAChallenge signs the token identifying the login waiting for its second factor
+func newMFAChallenge(signerContract contracts.TokenSignerContract, username string, now time.Time) (*models.MFAChallenge, *models.SystemError) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not generate MFA challenge", struct{}{})
+	}
+	expiresAt := now.Add(models.MFAChallengeTTL)
+	token, err := signerContract.Sign(map[string]any{
+		"sub":     username,
+		"purpose": models.MFAChallengePurpose,
+		"jti":     hex.EncodeToString(jti),
+		"iat":     now.Unix(),
+		"exp":     expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.MFAChallenge{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// verifySecondFactor accepts a TOTP code not used before or an unused recovery code, which is spent
+func verifySecondFactor(
+	userContract contracts.UserContract,
+	otpContract contracts.OTPContract,
+	recoveryContract contracts.RecoveryCodeContract,
+	cryptographyContract contracts.CryptographyContract,
+	user *models.User,
+	code string,
+	now time.Time,
+) (bool, *models.SystemError) {
+	code = normalizeCode(code)
+	if isTOTPCode(code) {
+		step, ok := otpContract.Verify(user.MFASecret, code, now)
+		if !ok {
+			return false, nil
+		}
+		return userContract.AcceptMFAStep(user.ID, step)
+	}
+	codes, err := recoveryContract.Unused(user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, recovery := range codes {
+		match, err := cryptographyContract.ComparePassword(code, recovery.CodeHash)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return recoveryContract.Use(recovery.ID, now)
+		}
+	}
+	return false, nil
+}
+
+// replaceRecoveryCodes generates new recovery codes formatted XXXXX-XXXXX and stores their hashes
+func replaceRecoveryCodes(recoveryContract contracts.RecoveryCodeContract, cryptographyContract contracts.CryptographyContract, userID string) (*models.MFARecoveryCodes, *models.SystemError) {
+	codes := make([]string, models.MFARecoveryCodeCount)
+	hashes := make([]string, models.MFARecoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not generate recovery codes", struct{}{})
+		}
+		code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)[:10]
+		hash, err := cryptographyContract.EncodePassword(code)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hash
+	}
+	if err := recoveryContract.Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+	return &models.MFARecoveryCodes{Codes: codes}, nil
+}
+
+func clearMFA(userContract contracts.UserContract, recoveryContract contracts.RecoveryCodeContract, userID string) *models.SystemError {
+	if err := userContract.UpdateMFA(userID, "", false); err != nil {
+		return err
+	}
+	return recoveryContract.DeleteByUser(userID)
+}
+
+// normalizeCode accepts codes typed with spaces, dashes or in lower case
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func invalidMFACode() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Código inválido o expirado", struct{}{})
+}
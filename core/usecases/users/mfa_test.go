@@ -0,0 +1,50 @@
+package user
+
+import (
+	"testing"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+func TestVerifyMFAChallengeIsSingleUse(t *testing.T) {
+	users := newFakeUsers(models.User{ID: "user", Username: "jdoe", Password: "hashed:secret", Active: true, MFAEnabled: true, MFASecret: "secret"})
+	signer := &fakeSigner{signed: map[string]map[string]any{}}
+	denylist := &fakeDenylist{revoked: map[string]bool{}}
+	otp := &fakeOTP{steps: map[string]int64{"111111": 1, "222222": 2, "333333": 3}}
+	crypto := &fakeCryptography{}
+
+	challenge := func() string {
+		request := contracts.NewGenericRequest(models.LoginUser{Username: "jdoe", Password: "secret"})
+		result, err := NewLoginUserUseCase(users, nil, &fakeAudit{}, fakePolicy{}, signer, request, crypto).Execute()
+		if err != nil || result.Challenge == nil {
+			t.Fatalf("Expected the login to answer a challenge, got %v", err)
+		}
+		return result.Challenge.Token
+	}
+	verify := func(token string, code string) (*models.UserData, *models.SystemError) {
+		request := contracts.NewGenericRequest(models.VerifyMFA{Token: token, Code: code})
+		return NewVerifyMFAUseCase(users, nil, &fakeAudit{}, denylist, signer, otp, fakeRecovery{}, crypto, request).Execute()
+	}
+
+	token := challenge()
+	if _, err := verify(token, "111111"); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	// a later code of the app does not reopen the answered challenge
+	if _, err := verify(token, "222222"); err == nil {
+		t.Errorf("Expected the answered challenge to be refused")
+	}
+
+	// a wrong code spends the challenge too
+	token = challenge()
+	if _, err := verify(token, "000000"); err == nil {
+		t.Fatalf("Expected the wrong code to be refused")
+	}
+	if _, err := verify(token, "333333"); err == nil {
+		t.Errorf("Expected the challenge answered with a wrong code to be refused")
+	}
+	if users.users["user"].FailedLoginAttempts != 1 {
+		t.Errorf("Expected one failed login, got %d", users.users["user"].FailedLoginAttempts)
+	}
+}
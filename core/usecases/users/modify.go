@@ -1,6 +1,8 @@
 package user
 
 import (
+	"time"
+
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
 )
@@ -27,7 +29,7 @@ import (
 //	}}
 //
 //	// 3. Instantiate the UseCase
-//	useCase := user.NewModifyUserUseCase(userRepo, request)
+//	useCase := user.NewModifyUserUseCase(userRepo, roleRepo, refreshTokenRepo, passwordPolicy, passwordHistoryRepo, securityContext, request)
 //
 //	// 4. Validate the request
 //	if err := useCase.Validate(); err != nil {
@@ -46,16 +48,34 @@ import (
 //	    fmt.Printf("User: %s\n", user.Username)
 //	}
 type ModifyUserUseCase struct {
-	userContract contracts.UserContract
-	request      contracts.IGenericRequest[models.ModifyUser]
+	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	refreshContract      contracts.RefreshTokenContract
+	policyContract       contracts.PasswordPolicyContract
+	historyContract      contracts.PasswordHistoryContract
+	cryptographyContract contracts.CryptographyContract
+	request              contracts.IGenericRequest[models.ModifyUser]
 }
 
 // NewModifyUserUseCase creates a new instance of ModifyUserUseCase
 // It injects the user contract (dependency inversion) and the request data
-func NewModifyUserUseCase(userContract contracts.UserContract, request contracts.IGenericRequest[models.ModifyUser]) *ModifyUserUseCase {
+func NewModifyUserUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	refreshContract contracts.RefreshTokenContract,
+	policyContract contracts.PasswordPolicyContract,
+	historyContract contracts.PasswordHistoryContract,
+	cryptographyContract contracts.CryptographyContract,
+	request contracts.IGenericRequest[models.ModifyUser],
+) *ModifyUserUseCase {
 	return &ModifyUserUseCase{
-		userContract: userContract,
-		request:      request,
+		userContract:         userContract,
+		roleContract:         roleContract,
+		refreshContract:      refreshContract,
+		policyContract:       policyContract,
+		historyContract:      historyContract,
+		cryptographyContract: cryptographyContract,
+		request:              request,
 	}
 }
 
@@ -66,16 +86,47 @@ func (u *ModifyUserUseCase) Validate() *models.SystemError {
 	if err := request.Validate(); err != nil {
 		return err
 	}
-	return nil
+	if request.Password != "" {
+		user, err := u.userContract.GetOnce("id", request.ID)
+		if err != nil {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+		}
+		if err := checkNewPassword(u.policyContract, u.historyContract, u.cryptographyContract, user, request.Username, request.Password); err != nil {
+			return err
+		}
+	}
+	return validateRole(u.roleContract, request.RoleID)
 }
 
 // Execute performs the user modification operation
-// It builds the user data from the request and passes it to the user contract to update the user
+// It builds the user data from the request and passes it to the user contract to update the user.
+// An empty password keeps the current one, a new password revokes every session of the user.
 func (u *ModifyUserUseCase) Execute() (*models.UserData, *models.SystemError) {
 	request := u.request.Build()
-	user, err := u.userContract.Update(request.ID, *request.ToUser())
+	existing, err := u.userContract.GetOnce("id", request.ID)
 	if err != nil {
 		return nil, err
 	}
+	modified := request.ToUser()
+	// the request does not carry the active flag, deactivated users stay deactivated
+	modified.Active = existing.Active
+	now := time.Now().UTC()
+	if request.Password != "" {
+		if err := setPassword(u.cryptographyContract, modified, request.Password, now); err != nil {
+			return nil, err
+		}
+	}
+	user, err := u.userContract.Update(request.ID, *modified)
+	if err != nil {
+		return nil, err
+	}
+	if request.Password != "" {
+		if err := recordPassword(u.policyContract, u.historyContract, user); err != nil {
+			return nil, err
+		}
+		if err := u.refreshContract.RevokeByUser(user.ID, now); err != nil {
+			return nil, err
+		}
+	}
 	return user.ToUserData(), nil
 }
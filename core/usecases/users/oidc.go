@@ -0,0 +1,288 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// oidcSecretBytes is the entropy of the state, the nonce and the PKCE verifier
+const oidcSecretBytes = 32
+
+// StartOIDCLoginUseCase prepares an authorization code request with PKCE. The state, the nonce and the
+// verifier travel signed in the flow the client keeps, so the server holds no pending logins.
+type StartOIDCLoginUseCase struct {
+	oidcContract   contracts.OIDCProviderContract
+	signerContract contracts.TokenSignerContract
+}
+
+func NewStartOIDCLoginUseCase(oidcContract contracts.OIDCProviderContract, signerContract contracts.TokenSignerContract) *StartOIDCLoginUseCase {
+	return &StartOIDCLoginUseCase{oidcContract: oidcContract, signerContract: signerContract}
+}
+
+func (u *StartOIDCLoginUseCase) Validate() *models.SystemError {
+	return nil
+}
+
+func (u *StartOIDCLoginUseCase) Execute() (*models.OIDCAuthorization, *models.SystemError) {
+	now := time.Now().UTC()
+	values := make([]string, 3)
+	for i := range values {
+		raw := make([]byte, oidcSecretBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "could not start single sign-on", struct{}{})
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	url, err := u.oidcContract.AuthorizationURL(state, nonce, codeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+	flow, err := u.signerContract.Sign(map[string]any{
+		"purpose":  models.OIDCFlowPurpose,
+		"state":    state,
+		"nonce":    nonce,
+		"verifier": verifier,
+		"iat":      now.Unix(),
+		"exp":      now.Add(models.OIDCFlowTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.OIDCAuthorization{URL: url, Flow: flow, ExpiresIn: int(models.OIDCFlowTTL.Seconds())}, nil
+}
+
+// OIDCLoginUseCase completes a single sign-on. The identity is matched to the user it was linked to,
+// or by verified email on its first login, and users signing in for the first time are provisioned when
+// models.OIDCSettings allow it. Once groups are mapped the mapping sets the role on every login, falling
+// back to the default role, the type is never changed.
+type OIDCLoginUseCase struct {
+	userContract    contracts.UserContract
+	roleContract    contracts.RoleContract
+	refreshContract contracts.RefreshTokenContract
+	auditContract   contracts.AuditContract
+	oidcContract    contracts.OIDCProviderContract
+	signerContract  contracts.TokenSignerContract
+	settings        models.OIDCSettings
+	request         contracts.IGenericRequest[models.OIDCCallback]
+}
+
+func NewOIDCLoginUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	refreshContract contracts.RefreshTokenContract,
+	auditContract contracts.AuditContract,
+	oidcContract contracts.OIDCProviderContract,
+	signerContract contracts.TokenSignerContract,
+	settings models.OIDCSettings,
+	request contracts.IGenericRequest[models.OIDCCallback],
+) *OIDCLoginUseCase {
+	return &OIDCLoginUseCase{
+		userContract:    userContract,
+		roleContract:    roleContract,
+		refreshContract: refreshContract,
+		auditContract:   auditContract,
+		oidcContract:    oidcContract,
+		signerContract:  signerContract,
+		settings:        settings,
+		request:         request,
+	}
+}
+
+func (u *OIDCLoginUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *OIDCLoginUseCase) Execute() (*models.LoginResult, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	claims, err := u.signerContract.Verify(request.Flow)
+	if err != nil {
+		return nil, expiredOIDCLogin()
+	}
+	state, _ := claims["state"].(string)
+	nonce, _ := claims["nonce"].(string)
+	verifier, _ := claims["verifier"].(string)
+	if claims["purpose"] != models.OIDCFlowPurpose || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(request.State)) != 1 {
+		return nil, expiredOIDCLogin()
+	}
+
+	identity, err := u.oidcContract.Exchange(request.Code, verifier, nonce)
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "No se pudo iniciar sesión con el proveedor", struct{}{})
+	}
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La cuenta del proveedor no tiene un correo verificado", struct{}{})
+	}
+
+	user, err := u.findUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = u.provision(identity, request.IP, now); err != nil {
+			return nil, err
+		}
+	} else if user, err = u.link(user, identity, now); err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		challenge, err := newMFAChallenge(u.signerContract, user.Username, now)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResult{Challenge: challenge}, nil
+	}
+	data, err := withRole(*user, u.roleContract)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{User: data}, nil
+}
+
+// findUser returns the user linked to the identity, else the user with its verified email, nil when there is none
+func (u *OIDCLoginUseCase) findUser(identity *models.OIDCIdentity) (*models.User, *models.SystemError) {
+	if user, err := u.userContract.GetOnce("external_id", identity.ExternalID()); err == nil {
+		return user, nil
+	}
+	user, err := u.userContract.GetOnce("email", identity.Email)
+	if err != nil {
+		return nil, nil
+	}
+	// the email now belongs to another identity of the provider, the account is not theirs
+	if user.ExternalID != "" {
+		return nil, ssoRefused()
+	}
+	// anyone can register an unverified email, it does not prove the account is theirs
+	if user.EmailVerifiedAt == nil {
+		return nil, ssoRefused()
+	}
+	return user, nil
+}
+
+func (u *OIDCLoginUseCase) provision(identity *models.OIDCIdentity, ip string, now time.Time) (*models.User, *models.SystemError) {
+	if !u.settings.AutoProvision {
+		return nil, ssoRefused()
+	}
+	if _, err := u.userContract.GetOnce("username", identity.Email); err == nil {
+		return nil, ssoRefused()
+	}
+	roleID, err := roleIDByName(u.roleContract, u.roleName(identity))
+	if err != nil {
+		return nil, err
+	}
+	created, err := u.userContract.Create(models.User{
+		Username:           identity.Email,
+		Email:              identity.Email,
+		Name:               identity.Name,
+		LastName:           identity.LastName,
+		Type:               models.UserTypeNormal,
+		Active:             true,
+		RoleID:             roleID,
+		EmailVerifiedAt:    &now,
+		ExternalID:         identity.ExternalID(),
+		LocalLoginDisabled: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionUserProvisioned,
+		Subject:   created.Username,
+		IP:        ip,
+		Details:   "issuer=" + identity.Issuer + " groups=" + strings.Join(identity.Groups, ","),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// link stores the identity on its first login and applies the role of its groups. The sessions opened
+// before the account was linked are revoked, they may not belong to the owner of the identity.
+func (u *OIDCLoginUseCase) link(user *models.User, identity *models.OIDCIdentity, now time.Time) (*models.User, *models.SystemError) {
+	if !user.Active {
+		return nil, ssoRefused()
+	}
+	changed := false
+	linked := user.ExternalID == ""
+	if linked {
+		user.ExternalID = identity.ExternalID()
+		changed = true
+	}
+	// without a mapping the role of linked users is managed by hand
+	if len(u.settings.GroupRoles) > 0 {
+		roleID, err := roleIDByName(u.roleContract, u.roleName(identity))
+		if err != nil {
+			return nil, err
+		}
+		if roleID != user.RoleID {
+			user.RoleID = roleID
+			changed = true
+		}
+	}
+	if changed {
+		updated, err := u.userContract.Update(user.ID, *user)
+		if err != nil {
+			return nil, err
+		}
+		user = &updated
+	}
+	if linked {
+		if err := u.refreshContract.RevokeByUser(user.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	// the provider verified the email, the verification link is not needed
+	if user.EmailVerifiedAt == nil && strings.EqualFold(user.Email, identity.Email) {
+		if _, err := u.userContract.VerifyEmail(user.ID, user.Email, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+	return user, nil
+}
+
+// roleName is the role mapped to the groups of the identity, else the default role
+func (u *OIDCLoginUseCase) roleName(identity *models.OIDCIdentity) string {
+	if roleName, mapped := u.settings.RoleFor(identity.Groups); mapped {
+		return roleName
+	}
+	return u.settings.DefaultRole
+}
+
+// roleIDByName resolves a configured role name, empty means no role
+func roleIDByName(roleContract contracts.RoleContract, name string) (string, *models.SystemError) {
+	if name == "" {
+		return "", nil
+	}
+	role, err := roleContract.GetOnce("name", name)
+	if err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "configured role "+name+" not found", struct{}{})
+	}
+	return role.ID, nil
+}
+
+// codeChallenge is the PKCE S256 challenge of the verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func expiredOIDCLogin() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El inicio de sesión expiró, intente de nuevo", struct{}{})
+}
+
+func ssoRefused() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeAuth, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "No tiene acceso con esta cuenta", struct{}{})
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+type oidcFixture struct {
+	users    *fakeUsers
+	roles    *fakeRoles
+	refresh  *fakeRefreshTokens
+	provider *fakeOIDC
+	signer   *fakeSigner
+	settings models.OIDCSettings
+}
+
+func newOIDCFixture(users ...models.User) *oidcFixture {
+	return &oidcFixture{
+		users:    newFakeUsers(users...),
+		roles:    &fakeRoles{roles: []models.Role{{ID: "role-admin", Name: "Admin"}, {ID: "role-user", Name: "User"}}},
+		refresh:  &fakeRefreshTokens{},
+		provider: &fakeOIDC{identity: models.OIDCIdentity{Issuer: "https://issuer.example.com", Subject: "sub", Email: "jdoe@example.com", EmailVerified: true}},
+		signer:   &fakeSigner{signed: map[string]map[string]any{}},
+	}
+}
+
+func (f *oidcFixture) login(t *testing.T) (*models.LoginResult, *models.SystemError) {
+	t.Helper()
+	authorization, err := NewStartOIDCLoginUseCase(f.provider, f.signer).Execute()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	state, _ := f.signer.signed[authorization.Flow]["state"].(string)
+	request := contracts.NewGenericRequest(models.OIDCCallback{Code: "code", State: state, Flow: authorization.Flow})
+	return NewOIDCLoginUseCase(f.users, f.roles, f.refresh, &fakeAudit{}, f.provider, f.signer, f.settings, request).Execute()
+}
+
+func TestOIDCLinksByVerifiedEmailOnly(t *testing.T) {
+	verifiedAt := time.Now().Add(-time.Hour)
+	f := newOIDCFixture(
+		models.User{ID: "user", Username: "jdoe", Email: "jdoe@example.com", Active: true},
+	)
+	// a local account that never proved the email is not handed to the identity
+	if _, err := f.login(t); err == nil {
+		t.Fatalf("Expected the unverified email to be refused")
+	}
+	if f.users.users["user"].ExternalID != "" || len(f.refresh.revokedUsers) != 0 {
+		t.Fatalf("Expected the unverified account to stay unlinked")
+	}
+
+	f.users.users["user"].EmailVerifiedAt = &verifiedAt
+	result, err := f.login(t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if result.User == nil || result.User.Id != "user" || f.users.users["user"].ExternalID != "https://issuer.example.com|sub" {
+		t.Fatalf("Expected the verified account to be linked, got %+v", result.User)
+	}
+	if len(f.refresh.revokedUsers) != 1 || f.refresh.revokedUsers[0] != "user" {
+		t.Errorf("Expected the first link to revoke the sessions of the account, got %v", f.refresh.revokedUsers)
+	}
+
+	// later logins find the identity, the sessions are kept
+	if _, err := f.login(t); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if len(f.refresh.revokedUsers) != 1 {
+		t.Errorf("Expected only the first link to revoke the sessions, got %v", f.refresh.revokedUsers)
+	}
+}
+
+func TestOIDCRoleFallsBackWhenGroupsNoLongerMap(t *testing.T) {
+	f := newOIDCFixture(
+		models.User{ID: "user", Username: "jdoe", Email: "jdoe@example.com", Active: true, RoleID: "role-admin", ExternalID: "https://issuer.example.com|sub"},
+	)
+	f.settings = models.OIDCSettings{DefaultRole: "User", GroupRoles: []models.OIDCGroupRole{{Group: "hr-admins", Role: "Admin"}}}
+
+	f.provider.identity.Groups = []string{"hr-admins"}
+	if _, err := f.login(t); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if f.users.users["user"].RoleID != "role-admin" {
+		t.Fatalf("Expected the mapped role, got %q", f.users.users["user"].RoleID)
+	}
+
+	// removed from the group at the provider, the admin role goes away on the next login
+	f.provider.identity.Groups = nil
+	if _, err := f.login(t); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if f.users.users["user"].RoleID != "role-user" {
+		t.Errorf("Expected the default role, got %q", f.users.users["user"].RoleID)
+	}
+
+	f.settings.DefaultRole = ""
+	if _, err := f.login(t); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if f.users.users["user"].RoleID != "" {
+		t.Errorf("Expected no role without a default, got %q", f.users.users["user"].RoleID)
+	}
+}
@@ -0,0 +1,71 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// checkNewPassword validates a password against the policy and, for existing users,
+// against the current and the last models.PasswordPolicy.HistorySize passwords
+func checkNewPassword(
+	policyContract contracts.PasswordPolicyContract,
+	historyContract contracts.PasswordHistoryContract,
+	cryptographyContract contracts.CryptographyContract,
+	user *models.User,
+	username string,
+	password string,
+) *models.SystemError {
+	policy := policyContract.Policy()
+	violations := policyContract.Check(username, password)
+	// reuse is checked last, comparing hashes is slow
+	if len(violations) == 0 && user != nil && policy.HistorySize > 0 {
+		hashes, err := historyContract.Recent(user.ID, policy.HistorySize)
+		if err != nil {
+			return err
+		}
+		for _, hash := range append([]string{user.Password}, hashes...) {
+			if hash == "" {
+				continue
+			}
+			reused, err := cryptographyContract.ComparePassword(password, hash)
+			if err != nil {
+				return err
+			}
+			if reused {
+				violations = append(violations, models.PasswordViolation{
+					Rule:    models.PasswordRuleReused,
+					Message: fmt.Sprintf("No puede repetir ninguna de sus últimas %d contraseñas", policy.HistorySize),
+				})
+				break
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return models.NewPasswordPolicyError(policy, violations)
+	}
+	return nil
+}
+
+// setPassword encodes the new password of the user and restarts its expiry
+func setPassword(cryptographyContract contracts.CryptographyContract, user *models.User, password string, now time.Time) *models.SystemError {
+	encoded, err := cryptographyContract.EncodePassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = encoded
+	user.PasswordChangedAt = &now
+	user.MustChangePassword = false
+	return nil
+}
+
+// recordPassword keeps the hash of the saved password for the reuse check
+func recordPassword(policyContract contracts.PasswordPolicyContract, historyContract contracts.PasswordHistoryContract, user models.User) *models.SystemError {
+	keep := policyContract.Policy().HistorySize
+	if keep == 0 {
+		return nil
+	}
+	return historyContract.Record(user.ID, user.Password, keep)
+}
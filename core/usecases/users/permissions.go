@@ -0,0 +1,63 @@
+package user
+
+import (
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// GetUserPermissionsUseCase resolves the permissions granted to a user through its role.
+type GetUserPermissionsUseCase struct {
+	userContract contracts.UserContract
+	roleContract contracts.RoleContract
+	username     string
+}
+
+func NewGetUserPermissionsUseCase(userContract contracts.UserContract, roleContract contracts.RoleContract, username string) *GetUserPermissionsUseCase {
+	return &GetUserPermissionsUseCase{
+		userContract: userContract,
+		roleContract: roleContract,
+		username:     username,
+	}
+}
+
+func (u *GetUserPermissionsUseCase) Validate() *models.SystemError {
+	if u.username == "" {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "username is required", struct{}{})
+	}
+	return nil
+}
+
+func (u *GetUserPermissionsUseCase) Execute() ([]models.Permission, *models.SystemError) {
+	user, err := u.userContract.GetOnce("username", u.username)
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "user not found", struct{}{})
+	}
+	if user.RoleID == "" {
+		return []models.Permission{}, nil
+	}
+	return u.roleContract.GetPermissions(user.RoleID)
+}
+
+// validateRole checks that the role assigned to a user exists, users without role are allowed
+func validateRole(roleContract contracts.RoleContract, roleID string) *models.SystemError {
+	if roleID == "" {
+		return nil
+	}
+	if _, err := roleContract.GetOnce("id", roleID); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El rol no existe", struct{}{})
+	}
+	return nil
+}
+
+// withRole returns the data of the user with the name and the permissions of its role
+func withRole(user models.User, roleContract contracts.RoleContract) (*models.UserData, *models.SystemError) {
+	data := user.ToUserData()
+	if user.RoleID == "" {
+		return data.WithRole(nil, nil), nil
+	}
+	role, err := roleContract.GetOnce("id", user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	return data.WithRole(role, role.Permissions), nil
+}
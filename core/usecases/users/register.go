@@ -0,0 +1,285 @@
+package user
+
+import (
+	"strconv"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// RegisterUserUseCase is the public registration. The type of the new user is always normal and its role
+// is the default role of models.RegistrationSettings, or the type and role of the invitation when it brings one.
+type RegisterUserUseCase struct {
+	userContract         contracts.UserContract
+	roleContract         contracts.RoleContract
+	invitationContract   contracts.InvitationContract
+	policyContract       contracts.PasswordPolicyContract
+	historyContract      contracts.PasswordHistoryContract
+	cryptographyContract contracts.CryptographyContract
+	settings             models.RegistrationSettings
+	request              contracts.IGenericRequest[models.RegisterUser]
+}
+
+func NewRegisterUserUseCase(
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	invitationContract contracts.InvitationContract,
+	policyContract contracts.PasswordPolicyContract,
+	historyContract contracts.PasswordHistoryContract,
+	cryptographyContract contracts.CryptographyContract,
+	settings models.RegistrationSettings,
+	request contracts.IGenericRequest[models.RegisterUser],
+) *RegisterUserUseCase {
+	return &RegisterUserUseCase{
+		userContract:         userContract,
+		roleContract:         roleContract,
+		invitationContract:   invitationContract,
+		policyContract:       policyContract,
+		historyContract:      historyContract,
+		cryptographyContract: cryptographyContract,
+		settings:             settings,
+		request:              request,
+	}
+}
+
+func (u *RegisterUserUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if u.settings.Mode == models.RegistrationModeDisabled {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El registro está deshabilitado", struct{}{})
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	email := request.Email
+	if request.InvitationToken != "" {
+		invitation, err := u.findInvitation(request.InvitationToken, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		email = invitation.Email
+	} else {
+		if u.settings.Mode != models.RegistrationModeOpen {
+			return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Se requiere una invitación para registrarse", struct{}{})
+		}
+		if _, err := u.defaultRoleID(); err != nil {
+			return err
+		}
+	}
+	if _, err := u.userContract.GetOnce("username", request.Username); err == nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario ya existe", struct{}{})
+	}
+	if _, err := u.userContract.GetOnce("email", email); err == nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Ya existe un usuario con ese correo", struct{}{})
+	}
+	return checkNewPassword(u.policyContract, u.historyContract, u.cryptographyContract, nil, request.Username, request.Password)
+}
+
+func (u *RegisterUserUseCase) Execute() (*models.UserData, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	newUser := &models.User{
+		Username: request.Username,
+		Email:    request.Email,
+		Name:     request.Name,
+		LastName: request.LastName,
+		Picture:  request.Picture,
+		Type:     models.UserTypeNormal,
+		Active:   true,
+	}
+	var invitation *models.Invitation
+	if request.InvitationToken != "" {
+		var err *models.SystemError
+		invitation, err = u.findInvitation(request.InvitationToken, now)
+		if err != nil {
+			return nil, err
+		}
+		// the invitation was mailed there, the email is proven
+		newUser.Email = invitation.Email
+		newUser.EmailVerifiedAt = &now
+		newUser.Type = invitation.Type
+		newUser.RoleID = invitation.RoleID
+	} else {
+		roleID, err := u.defaultRoleID()
+		if err != nil {
+			return nil, err
+		}
+		newUser.RoleID = roleID
+	}
+	if err := setPassword(u.cryptographyContract, newUser, request.Password, now); err != nil {
+		return nil, err
+	}
+	created, err := u.userContract.Create(*newUser)
+	if err != nil {
+		return nil, err
+	}
+	if invitation != nil {
+		// spent only once the user exists, a failed create leaves the invitation usable
+		accepted, err := u.invitationContract.Accept(invitation.ID, now)
+		if err == nil && !accepted {
+			err = invalidInvitation()
+		}
+		if err != nil {
+			// a concurrent registration accepted it first, the second user goes away
+			u.userContract.Delete(created.ID)
+			return nil, err
+		}
+	}
+	if err := recordPassword(u.policyContract, u.historyContract, created); err != nil {
+		return nil, err
+	}
+	return created.ToUserData(), nil
+}
+
+func (u *RegisterUserUseCase) findInvitation(token string, now time.Time) (*models.Invitation, *models.SystemError) {
+	invitation, err := u.invitationContract.GetOnce("token_hash", hashAccountToken(token))
+	if err != nil || !invitation.Usable(now) {
+		return nil, invalidInvitation()
+	}
+	return invitation, nil
+}
+
+// defaultRoleID resolves the role of users registered without invitation, empty leaves them without role
+func (u *RegisterUserUseCase) defaultRoleID() (string, *models.SystemError) {
+	if u.settings.DefaultRole == "" {
+		return "", nil
+	}
+	role, err := u.roleContract.GetOnce("name", u.settings.DefaultRole)
+	if err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "default registration role "+u.settings.DefaultRole+" not found", struct{}{})
+	}
+	return role.ID, nil
+}
+
+// CreateInvitationUseCase lets an administrator invite someone by email with the type and role it will get
+type CreateInvitationUseCase struct {
+	invitationContract contracts.InvitationContract
+	userContract       contracts.UserContract
+	roleContract       contracts.RoleContract
+	auditContract      contracts.AuditContract
+	mailerContract     contracts.MailerContract
+	templateContract   contracts.MailTemplateContract
+	request            contracts.IGenericRequest[models.CreateInvitation]
+}
+
+func NewCreateInvitationUseCase(
+	invitationContract contracts.InvitationContract,
+	userContract contracts.UserContract,
+	roleContract contracts.RoleContract,
+	auditContract contracts.AuditContract,
+	mailerContract contracts.MailerContract,
+	templateContract contracts.MailTemplateContract,
+	request contracts.IGenericRequest[models.CreateInvitation],
+) *CreateInvitationUseCase {
+	return &CreateInvitationUseCase{
+		invitationContract: invitationContract,
+		userContract:       userContract,
+		roleContract:       roleContract,
+		auditContract:      auditContract,
+		mailerContract:     mailerContract,
+		templateContract:   templateContract,
+		request:            request,
+	}
+}
+
+func (u *CreateInvitationUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.userContract.GetOnce("email", request.Email); err == nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Ya existe un usuario con ese correo", struct{}{})
+	}
+	return validateRole(u.roleContract, request.RoleID)
+}
+
+func (u *CreateInvitationUseCase) Execute() (*models.Invitation, *models.SystemError) {
+	request := u.request.Build()
+	now := time.Now().UTC()
+	token, err := newAccountToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := u.invitationContract.Create(models.Invitation{
+		Email:     request.Email,
+		RoleID:    request.RoleID,
+		Type:      request.Type,
+		TokenHash: hashAccountToken(token),
+		InvitedBy: request.Actor,
+		ExpiresAt: now.Add(models.InvitationTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionUserInvited,
+		Actor:     request.Actor,
+		Subject:   request.Email,
+		Details:   "type=" + string(request.Type) + " role_id=" + request.RoleID,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	if err := sendMail(u.templateContract, u.mailerContract, models.MailMessage{
+		Template: models.MailTemplateInvitation,
+		Language: request.Language,
+		To:       request.Email,
+		Data: map[string]string{
+			"InvitedBy": request.Actor,
+			"Link":      withToken(request.AcceptURL, token),
+			"Days":      strconv.Itoa(int(models.InvitationTTL.Hours() / 24)),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListInvitationsUseCase returns the invitations that can still be accepted
+type ListInvitationsUseCase struct {
+	invitationContract contracts.InvitationContract
+}
+
+func NewListInvitationsUseCase(invitationContract contracts.InvitationContract) *ListInvitationsUseCase {
+	return &ListInvitationsUseCase{invitationContract: invitationContract}
+}
+
+func (u *ListInvitationsUseCase) Validate() *models.SystemError {
+	return nil
+}
+
+func (u *ListInvitationsUseCase) Execute() ([]models.Invitation, *models.SystemError) {
+	return u.invitationContract.Pending(time.Now().UTC())
+}
+
+// RevokeInvitationUseCase cancels an invitation before it is accepted
+type RevokeInvitationUseCase struct {
+	invitationContract contracts.InvitationContract
+	request            contracts.IGenericRequest[models.RevokeInvitation]
+}
+
+func NewRevokeInvitationUseCase(invitationContract contracts.InvitationContract, request contracts.IGenericRequest[models.RevokeInvitation]) *RevokeInvitationUseCase {
+	return &RevokeInvitationUseCase{invitationContract: invitationContract, request: request}
+}
+
+func (u *RevokeInvitationUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	return request.Validate()
+}
+
+func (u *RevokeInvitationUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	revoked, err := u.invitationContract.Revoke(request.ID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La invitación no existe o ya fue usada", struct{}{})
+	}
+	return nil
+}
+
+func invalidInvitation() *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "La invitación es inválida o expiró", struct{}{})
+}
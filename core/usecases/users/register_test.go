@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+func register(users *fakeUsers, invitations *fakeInvitations, mode models.RegistrationMode, body models.RegisterUser) (*models.UserData, *models.SystemError) {
+	roles := &fakeRoles{roles: []models.Role{{ID: "role-employee", Name: "employee"}}}
+	settings := models.RegistrationSettings{Mode: mode, DefaultRole: "employee"}
+	useCase := NewRegisterUserUseCase(users, roles, invitations, fakePolicy{}, nil, &fakeCryptography{}, settings, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		return nil, err
+	}
+	return useCase.Execute()
+}
+
+func TestRegisterOpenModeGetsNormalTypeAndDefaultRole(t *testing.T) {
+	users := newFakeUsers(models.User{ID: "admin", Username: "admin", Email: "admin@example.com", Type: models.UserTypeAdmin, Active: true})
+	invitations := &fakeInvitations{invitations: map[string]*models.Invitation{}}
+
+	created, err := register(users, invitations, models.RegistrationModeOpen, models.RegisterUser{Username: "jdoe", Password: "secret", Email: "jdoe@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if created.Type != models.UserTypeNormal || created.RoleID != "role-employee" {
+		t.Errorf("Expected a normal user with the default role, got %s with role %q", created.Type, created.RoleID)
+	}
+	if users.users[created.Id].EmailVerifiedAt != nil {
+		t.Errorf("Expected the email of an open registration to stay unverified")
+	}
+
+	// the email of another user cannot be taken over by registering
+	if _, err := register(users, invitations, models.RegistrationModeOpen, models.RegisterUser{Username: "other", Password: "secret", Email: "admin@example.com"}); err == nil {
+		t.Errorf("Expected the registered email to be refused")
+	}
+}
+
+func TestRegisterInviteModeRequiresAnInvitation(t *testing.T) {
+	users := newFakeUsers()
+	invitations := &fakeInvitations{invitations: map[string]*models.Invitation{
+		"invitation": {ID: "invitation", Email: "jdoe@example.com", Type: models.UserTypeAdmin, RoleID: "role-admin", TokenHash: hashAccountToken("token"), ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+
+	if _, err := register(users, invitations, models.RegistrationModeInvite, models.RegisterUser{Username: "jdoe", Password: "secret", Email: "jdoe@example.com"}); err == nil {
+		t.Fatalf("Expected the registration without invitation to be refused")
+	}
+	if len(users.users) != 0 {
+		t.Fatalf("Expected no user to be created")
+	}
+
+	created, err := register(users, invitations, models.RegistrationModeInvite, models.RegisterUser{Username: "jdoe", Password: "secret", InvitationToken: "token"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Message)
+	}
+	if created.Type != models.UserTypeAdmin || created.RoleID != "role-admin" || created.Email != "jdoe@example.com" {
+		t.Errorf("Expected the type, role and email of the invitation, got %+v", created)
+	}
+	if invitations.invitations["invitation"].UsedAt == nil {
+		t.Errorf("Expected the invitation to be accepted")
+	}
+	if _, err := register(users, invitations, models.RegistrationModeInvite, models.RegisterUser{Username: "jdoe2", Password: "secret", InvitationToken: "token"}); err == nil {
+		t.Errorf("Expected the accepted invitation to be refused")
+	}
+}
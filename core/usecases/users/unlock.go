@@ -0,0 +1,52 @@
+package user
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+)
+
+// UnlockUserUseCase lets an administrator clear the lockout of a user before it expires
+type UnlockUserUseCase struct {
+	userContract  contracts.UserContract
+	auditContract contracts.AuditContract
+	request       contracts.IGenericRequest[models.UnlockUser]
+}
+
+func NewUnlockUserUseCase(userContract contracts.UserContract, auditContract contracts.AuditContract, request contracts.IGenericRequest[models.UnlockUser]) *UnlockUserUseCase {
+	return &UnlockUserUseCase{
+		userContract:  userContract,
+		auditContract: auditContract,
+		request:       request,
+	}
+}
+
+func (u *UnlockUserUseCase) Validate() *models.SystemError {
+	request := u.request.Build()
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.userContract.GetOnce("username", request.Username); err != nil {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "El usuario no existe", struct{}{})
+	}
+	return nil
+}
+
+func (u *UnlockUserUseCase) Execute() *models.SystemError {
+	request := u.request.Build()
+	user, err := u.userContract.GetOnce("username", request.Username)
+	if err != nil {
+		return err
+	}
+	if err := u.userContract.ResetFailedLogins(user.ID); err != nil {
+		return err
+	}
+	return u.auditContract.Record(models.AuditEntry{
+		Action:    models.AuditActionAccountUnlocked,
+		Actor:     request.Actor,
+		Subject:   user.Username,
+		IP:        request.IP,
+		CreatedAt: time.Now().UTC(),
+	})
+}
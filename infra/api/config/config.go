@@ -5,8 +5,11 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"hrms.local/core/models"
+
 	"github.com/joho/godotenv"
 )
 
@@ -22,10 +25,58 @@ type Config struct {
 	// Server Configuration
 	ServerPort     string
 	Environment    string
-	JWTSecret      string
 	ReadTimeout    time.Duration
 	WriteTimeout   time.Duration
 	MaxHeaderBytes int
+
+	// Token signing: PEM keys named <kid>.pem, the active kid and an HS256 secret used when no keys directory is set
+	JWTKeysDir     string
+	JWTActiveKeyID string
+	JWTSecret      string
+
+	// Login attempts accepted per client IP and minute
+	LoginRateLimit int
+
+	// Rules of new passwords and the file with breached passwords, empty disables that check
+	PasswordPolicy       models.PasswordPolicy
+	PasswordBreachedList string
+
+	// Mail delivery: "smtp", "file" (.eml files in MailDir) or "memory", and the sender address
+	MailDriver   string
+	MailDir      string
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Base URL of the web client, mailed links point to its /reset-password, /verify-email and /register pages
+	AppURL string
+
+	// Who can register at /api/auth/create: "disabled", "invite" or "open", and the role given in open mode
+	Registration models.RegistrationSettings
+
+	// OpenID Connect single sign-on, enabled when OIDCIssuerURL is set. The redirect URL is the page of the
+	// web client the provider sends the code to, OIDCGroupRoles maps groups to roles as "group=Role,..."
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCRedirectURL   string
+	OIDCScopes        string
+	OIDCGroupsClaim   string
+	OIDCGroupRoles    string
+	OIDCAutoProvision bool
+	OIDCDefaultRole   string
+	// Serve a development issuer at /mock-oidc that signs in any email, refused in production
+	OIDCMock bool
+
+	// Company name printed on payslips and bank files
+	CompanyName string
+}
+
+// IsProduction reports whether the server runs in production, where insecure defaults are refused
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.Environment, "production")
 }
 
 func LoadConfig() *Config {
@@ -46,6 +97,9 @@ func LoadConfig() *Config {
 		)
 	}
 
+	passwordPolicy := models.DefaultPasswordPolicy()
+	appURL := strings.TrimSuffix(getEnv("APP_URL", "http://localhost:4200"), "/")
+
 	return &Config{
 		DBHost:         getEnv("DB_HOST", "localhost"),
 		DBPort:         getEnv("DB_PORT", "5432"),
@@ -55,10 +109,46 @@ func LoadConfig() *Config {
 		DBURL:          dbURL,
 		ServerPort:     getEnv("SERVER_PORT", "5000"),
 		Environment:    getEnv("ENVIRONMENT", "development"),
-		JWTSecret:      getEnv("JWT_SECRET", "default_secret"),
 		ReadTimeout:    time.Duration(getEnvInt("READ_TIMEOUT", 10)) * time.Second,
 		WriteTimeout:   time.Duration(getEnvInt("WRITE_TIMEOUT", 10)) * time.Second,
 		MaxHeaderBytes: getEnvInt("MAX_HEADER_BYTES", 1<<20),
+		CompanyName:    getEnv("COMPANY_NAME", "HEX-HRMS"),
+		JWTKeysDir:     getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKeyID: getEnv("JWT_ACTIVE_KID", ""),
+		JWTSecret:      getEnv("JWT_SECRET", getEnv("JWT_SECRET_KEY", "")),
+		LoginRateLimit: getEnvInt("LOGIN_RATE_LIMIT", 10),
+		PasswordPolicy: models.PasswordPolicy{
+			MinLength:     getEnvInt("PASSWORD_MIN_LENGTH", passwordPolicy.MinLength),
+			RequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", passwordPolicy.RequireUpper),
+			RequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", passwordPolicy.RequireLower),
+			RequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", passwordPolicy.RequireDigit),
+			RequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", passwordPolicy.RequireSymbol),
+			HistorySize:   getEnvInt("PASSWORD_HISTORY", passwordPolicy.HistorySize),
+			MaxAge:        time.Duration(getEnvInt("PASSWORD_MAX_AGE_DAYS", int(passwordPolicy.MaxAge.Hours()/24))) * 24 * time.Hour,
+		},
+		PasswordBreachedList: getEnv("PASSWORD_BREACHED_LIST", ""),
+		MailDriver:           getEnv("MAIL_DRIVER", "file"),
+		MailDir:              getEnv("MAIL_DIR", "mail"),
+		MailFrom:             getEnv("MAIL_FROM", "no-reply@localhost"),
+		SMTPHost:             getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:             getEnv("SMTP_PORT", "587"),
+		SMTPUsername:         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:         getEnv("SMTP_PASSWORD", ""),
+		AppURL:               appURL,
+		Registration: models.RegistrationSettings{
+			Mode:        models.RegistrationMode(strings.ToLower(getEnv("REGISTRATION_MODE", string(models.RegistrationModeInvite)))),
+			DefaultRole: getEnv("REGISTRATION_DEFAULT_ROLE", "User"),
+		},
+		OIDCIssuerURL:     getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:      getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:   getEnv("OIDC_REDIRECT_URL", appURL+"/auth/callback"),
+		OIDCScopes:        getEnv("OIDC_SCOPES", "openid email profile"),
+		OIDCGroupsClaim:   getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCGroupRoles:    getEnv("OIDC_GROUP_ROLES", ""),
+		OIDCAutoProvision: getEnvBool("OIDC_AUTO_PROVISION", true),
+		OIDCDefaultRole:   getEnv("OIDC_DEFAULT_ROLE", "User"),
+		OIDCMock:          getEnvBool("OIDC_MOCK", false),
 	}
 }
 
@@ -80,3 +170,15 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Printf("Warning: environment variable %s is not a boolean, using fallback %t", key, fallback)
+			return fallback
+		}
+		return b
+	}
+	return fallback
+}
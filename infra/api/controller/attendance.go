@@ -0,0 +1,335 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	attendanceUseCase "hrms.local/core/usecases/attendance"
+	employeeUseCase "hrms.local/core/usecases/employees"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceController struct {
+	*types.BaseController
+	attendanceContract contracts.AttendanceContract
+	scheduleContract   contracts.WorkScheduleContract
+	employeeContract   contracts.EmployeeContract
+	departmentContract contracts.DepartmentContract
+	userContract       contracts.UserContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewAttendanceController(
+	authMiddleware *middleware.AuthMiddleware,
+	permission *middleware.PermissionMiddleware,
+	attendanceContract contracts.AttendanceContract,
+	scheduleContract contracts.WorkScheduleContract,
+	employeeContract contracts.EmployeeContract,
+	departmentContract contracts.DepartmentContract,
+	userContract contracts.UserContract,
+) *AttendanceController {
+	return &AttendanceController{
+		BaseController:     types.NewBaseController("/attendance"),
+		attendanceContract: attendanceContract,
+		scheduleContract:   scheduleContract,
+		employeeContract:   employeeContract,
+		departmentContract: departmentContract,
+		userContract:       userContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (ac *AttendanceController) SetContext(c *gin.Context) {
+	if r, ok := ac.attendanceContract.(*repo.AttendanceRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ac.scheduleContract.(*repo.WorkScheduleRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ac.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ac.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ac.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// currentEmployee resolves the employee linked to the authenticated user
+func (ac *AttendanceController) currentEmployee(c *gin.Context) (*models.Employee, *models.SystemError) {
+	useCase := employeeUseCase.NewGetCurrentEmployeeUseCase(ac.employeeContract, ac.userContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		return nil, err
+	}
+	return useCase.Execute()
+}
+
+func (ac *AttendanceController) ClockIn(c *gin.Context) {
+	ac.registerEvent(c, models.ClockEventTypeIn)
+}
+
+func (ac *AttendanceController) ClockOut(c *gin.Context) {
+	ac.registerEvent(c, models.ClockEventTypeOut)
+}
+
+// registerEvent clocks the caller, only attendance managers and kiosks may clock another employee
+// and only kiosks and integrations authenticated with an API key may send the source and time
+func (ac *AttendanceController) registerEvent(c *gin.Context, eventType models.ClockEventType) {
+	ac.SetContext(c)
+	body := models.RegisterClockEvent{Source: models.ClockSourceWeb}
+	if c.Request.ContentLength > 0 {
+		if _, err := ac.BaseController.GetBody(c, &body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	}
+	body.Type = eventType
+	body.Trusted = middleware.IsServiceAccount(c)
+	if body.EmployeeID == "" || !ac.permission.Has(c, models.PermissionManageAttendance) {
+		employee, err := ac.currentEmployee(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+		body.EmployeeID = employee.ID
+	}
+
+	useCase := attendanceUseCase.NewRegisterClockEventUsecase(ac.attendanceContract, ac.employeeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	event, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+func (ac *AttendanceController) Correct(c *gin.Context) {
+	ac.SetContext(c)
+	var body models.CorrectAttendanceEvent
+	if _, err := ac.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	manager, err := ac.currentEmployee(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Message})
+		return
+	}
+	body.CorrectedByID = manager.ID
+
+	useCase := attendanceUseCase.NewCorrectAttendanceEventUsecase(ac.attendanceContract, ac.employeeContract, ac.departmentContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	event, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+func (ac *AttendanceController) GetAll(c *gin.Context) {
+	ac.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := ac.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 10},
+		}
+	}
+
+	useCase := attendanceUseCase.NewListAttendanceEventsUsecase(ac.attendanceContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	events, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+func (ac *AttendanceController) Timesheet(c *gin.Context) {
+	ac.SetContext(c)
+	var body models.TimesheetQuery
+	if _, err := ac.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	if body.EmployeeID == "" {
+		employee, err := ac.currentEmployee(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+		body.EmployeeID = employee.ID
+	}
+
+	useCase := attendanceUseCase.NewGetTimesheetUsecase(ac.attendanceContract, ac.employeeContract, ac.scheduleContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	timesheets, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, timesheets)
+}
+
+// Export downloads the timesheets of a period as CSV.
+// Query parameters: from and to (YYYY-MM-DD, required), employee_id and schedule_id (optional).
+func (ac *AttendanceController) Export(c *gin.Context) {
+	ac.SetContext(c)
+	from, errFrom := time.Parse(time.DateOnly, c.Query("from"))
+	to, errTo := time.Parse(time.DateOnly, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must use the YYYY-MM-DD format"})
+		return
+	}
+	query := models.TimesheetQuery{
+		EmployeeID: c.Query("employee_id"),
+		ScheduleID: c.Query("schedule_id"),
+		From:       from,
+		To:         to,
+	}
+
+	useCase := attendanceUseCase.NewExportTimesheetsUsecase(ac.attendanceContract, ac.employeeContract, ac.scheduleContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	timesheets, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=attendance_%s_%s.csv", c.Query("from"), c.Query("to")))
+	c.Status(http.StatusOK)
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"employee_id", "date", "work_day", "first_in", "last_out", "scheduled_minutes", "worked_minutes", "late_minutes", "early_leave_minutes", "overtime_minutes", "absent", "incomplete"})
+	for _, sheet := range timesheets {
+		_ = writer.Write([]string{
+			sheet.EmployeeID,
+			sheet.Date,
+			strconv.FormatBool(sheet.WorkDay),
+			formatOptionalTime(sheet.FirstIn),
+			formatOptionalTime(sheet.LastOut),
+			strconv.Itoa(sheet.ScheduledMinutes),
+			strconv.Itoa(sheet.WorkedMinutes),
+			strconv.Itoa(sheet.LateMinutes),
+			strconv.Itoa(sheet.EarlyLeaveMinutes),
+			strconv.Itoa(sheet.OvertimeMinutes),
+			strconv.FormatBool(sheet.Absent),
+			strconv.FormatBool(sheet.Incomplete),
+		})
+	}
+	writer.Flush()
+}
+
+func (ac *AttendanceController) SaveSchedule(c *gin.Context) {
+	ac.SetContext(c)
+	var body models.WorkSchedule
+	if _, err := ac.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := attendanceUseCase.NewSaveWorkScheduleUsecase(ac.scheduleContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	schedule, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (ac *AttendanceController) GetAllSchedules(c *gin.Context) {
+	ac.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := ac.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 100},
+		}
+	}
+
+	useCase := attendanceUseCase.NewListWorkSchedulesUsecase(ac.scheduleContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	schedules, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (ac *AttendanceController) RegisterRoutes(router *gin.RouterGroup) {
+	attendance := router.Group("/attendance")
+	attendance.Use(ac.authMiddleware.AuthMiddleware())
+	{
+		attendance.POST("/clock-in", ac.ClockIn)
+		attendance.POST("/clock-out", ac.ClockOut)
+		ac.permission.Guard(attendance).POST("/devices/clock-in", models.PermissionManageAttendance, ac.ClockIn)
+		ac.permission.Guard(attendance).POST("/devices/clock-out", models.PermissionManageAttendance, ac.ClockOut)
+		attendance.POST("/correct", ac.Correct)
+		ac.permission.Guard(attendance).POST("/get-all", models.PermissionViewMenuAttendance, ac.GetAll)
+		ac.permission.Guard(attendance).POST("/timesheet", models.PermissionViewMenuAttendance, ac.Timesheet)
+		ac.permission.Guard(attendance).GET("/export", models.PermissionViewMenuAttendance, ac.Export)
+		ac.permission.Guard(attendance).POST("/schedules/save", models.PermissionManageAttendance, ac.SaveSchedule)
+		attendance.POST("/schedules/get-all", ac.GetAllSchedules)
+	}
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
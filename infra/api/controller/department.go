@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	departmentUseCase "hrms.local/core/usecases/departments"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentController struct {
+	*types.BaseController
+	departmentContract contracts.DepartmentContract
+	employeeContract   contracts.EmployeeContract
+	positionContract   contracts.PositionContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewDepartmentController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, departmentContract contracts.DepartmentContract, employeeContract contracts.EmployeeContract, positionContract contracts.PositionContract) *DepartmentController {
+	return &DepartmentController{
+		BaseController:     types.NewBaseController("/departments"),
+		departmentContract: departmentContract,
+		employeeContract:   employeeContract,
+		positionContract:   positionContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (dc *DepartmentController) SetContext(c *gin.Context) {
+	if r, ok := dc.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := dc.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := dc.positionContract.(*repo.PositionRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+func (dc *DepartmentController) Create(c *gin.Context) {
+	dc.SetContext(c)
+	var body models.CreateDepartment
+	if _, err := dc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := departmentUseCase.NewCreateDepartmentUsecase(dc.departmentContract, dc.employeeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	department, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+func (dc *DepartmentController) Update(c *gin.Context) {
+	dc.SetContext(c)
+	var body models.Department
+	if _, err := dc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := departmentUseCase.NewUpdateDepartmentUsecase(dc.departmentContract, dc.employeeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	department, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+func (dc *DepartmentController) Delete(c *gin.Context) {
+	dc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := dc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := departmentUseCase.NewDeleteDepartmentUsecase(dc.departmentContract, dc.employeeContract, dc.positionContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	if err := useCase.Execute(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Department deleted"})
+}
+
+func (dc *DepartmentController) Get(c *gin.Context) {
+	dc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := dc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := departmentUseCase.NewGetDepartmentUsecase(dc.departmentContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	department, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+func (dc *DepartmentController) GetAll(c *gin.Context) {
+	dc.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := dc.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 100},
+		}
+	}
+
+	useCase := departmentUseCase.NewListDepartmentsUsecase(dc.departmentContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	departments, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, departments)
+}
+
+func (dc *DepartmentController) RegisterRoutes(router *gin.RouterGroup) {
+	departments := router.Group("/departments")
+	departments.Use(dc.authMiddleware.AuthMiddleware())
+	{
+		dc.permission.Guard(departments).POST("/create", models.PermissionEditDepartments, dc.Create)
+		dc.permission.Guard(departments).POST("/update", models.PermissionEditDepartments, dc.Update)
+		dc.permission.Guard(departments).POST("/delete", models.PermissionEditDepartments, dc.Delete)
+		dc.permission.Guard(departments).POST("/get", models.PermissionViewMenuDepartments, dc.Get)
+		dc.permission.Guard(departments).POST("/get-all", models.PermissionViewMenuDepartments, dc.GetAll)
+	}
+}
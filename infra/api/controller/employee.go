@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	employeeUseCase "hrms.local/core/usecases/employees"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmployeeController struct {
+	*types.BaseController
+	employeeContract   contracts.EmployeeContract
+	userContract       contracts.UserContract
+	departmentContract contracts.DepartmentContract
+	positionContract   contracts.PositionContract
+	auditContract      contracts.AuditContract
+	refreshContract    contracts.RefreshTokenContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewEmployeeController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, employeeContract contracts.EmployeeContract, userContract contracts.UserContract, departmentContract contracts.DepartmentContract, positionContract contracts.PositionContract, auditContract contracts.AuditContract, refreshContract contracts.RefreshTokenContract) *EmployeeController {
+	return &EmployeeController{
+		BaseController:     types.NewBaseController("/employees"),
+		employeeContract:   employeeContract,
+		userContract:       userContract,
+		departmentContract: departmentContract,
+		positionContract:   positionContract,
+		auditContract:      auditContract,
+		refreshContract:    refreshContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (ec *EmployeeController) SetContext(c *gin.Context) {
+	if r, ok := ec.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ec.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ec.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ec.positionContract.(*repo.PositionRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ec.auditContract.(*repo.AuditRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := ec.refreshContract.(*repo.RefreshTokenRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+func (ec *EmployeeController) Create(c *gin.Context) {
+	ec.SetContext(c)
+	var body models.CreateEmployee
+	if _, err := ec.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := employeeUseCase.NewCreateEmployeeUseCase(ec.employeeContract, ec.userContract, ec.departmentContract, ec.positionContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	employee, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, employee)
+}
+
+func (ec *EmployeeController) Update(c *gin.Context) {
+	ec.SetContext(c)
+	var body models.ModifyEmployee
+	if _, err := ec.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	body.Actor = c.GetString("userID")
+	body.IP = c.ClientIP()
+	body.ActorPermissions = middleware.Permissions(c)
+
+	useCase := employeeUseCase.NewModifyEmployeeUseCase(ec.employeeContract, ec.userContract, ec.departmentContract, ec.positionContract, ec.auditContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	employee, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, employee)
+}
+
+func (ec *EmployeeController) Terminate(c *gin.Context) {
+	ec.SetContext(c)
+	var body models.TerminateEmployee
+	if _, err := ec.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := employeeUseCase.NewTerminateEmployeeUseCase(ec.employeeContract, ec.userContract, ec.refreshContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	employee, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, employee)
+}
+
+func (ec *EmployeeController) Get(c *gin.Context) {
+	ec.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := ec.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := employeeUseCase.NewGetEmployeeUseCase(ec.employeeContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	employee, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, employee)
+}
+
+func (ec *EmployeeController) GetAll(c *gin.Context) {
+	ec.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := ec.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 10},
+		}
+	}
+
+	useCase := employeeUseCase.NewListEmployeesUseCase(ec.employeeContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	employees, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, employees)
+}
+
+func (ec *EmployeeController) RegisterRoutes(router *gin.RouterGroup) {
+	employees := router.Group("/employees")
+	employees.Use(ec.authMiddleware.AuthMiddleware())
+	{
+		ec.permission.Guard(employees).POST("/create", models.PermissionEditEmployees, ec.Create)
+		ec.permission.Guard(employees).POST("/update", models.PermissionEditEmployees, ec.Update)
+		ec.permission.Guard(employees).POST("/terminate", models.PermissionEditEmployees, ec.Terminate)
+		ec.permission.Guard(employees).POST("/get", models.PermissionViewEmployees, ec.Get)
+		ec.permission.Guard(employees).POST("/get-all", models.PermissionViewEmployees, ec.GetAll)
+	}
+}
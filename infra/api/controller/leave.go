@@ -0,0 +1,335 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	employeeUseCase "hrms.local/core/usecases/employees"
+	leaveUseCase "hrms.local/core/usecases/leaves"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveController struct {
+	*types.BaseController
+	leaveContract      contracts.LeaveRequestContract
+	leaveTypeContract  contracts.LeaveTypeContract
+	employeeContract   contracts.EmployeeContract
+	departmentContract contracts.DepartmentContract
+	scheduleContract   contracts.WorkScheduleContract
+	userContract       contracts.UserContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewLeaveController(
+	authMiddleware *middleware.AuthMiddleware,
+	permission *middleware.PermissionMiddleware,
+	leaveContract contracts.LeaveRequestContract,
+	leaveTypeContract contracts.LeaveTypeContract,
+	employeeContract contracts.EmployeeContract,
+	departmentContract contracts.DepartmentContract,
+	scheduleContract contracts.WorkScheduleContract,
+	userContract contracts.UserContract,
+) *LeaveController {
+	return &LeaveController{
+		BaseController:     types.NewBaseController("/leaves"),
+		leaveContract:      leaveContract,
+		leaveTypeContract:  leaveTypeContract,
+		employeeContract:   employeeContract,
+		departmentContract: departmentContract,
+		scheduleContract:   scheduleContract,
+		userContract:       userContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (lc *LeaveController) SetContext(c *gin.Context) {
+	if r, ok := lc.leaveContract.(*repo.LeaveRequestRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := lc.leaveTypeContract.(*repo.LeaveTypeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := lc.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := lc.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := lc.scheduleContract.(*repo.WorkScheduleRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := lc.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// currentEmployee resolves the employee linked to the authenticated user
+func (lc *LeaveController) currentEmployee(c *gin.Context) (*models.Employee, *models.SystemError) {
+	useCase := employeeUseCase.NewGetCurrentEmployeeUseCase(lc.employeeContract, lc.userContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		return nil, err
+	}
+	return useCase.Execute()
+}
+
+// Submit files a leave request of the caller, leave managers may file it for another employee
+func (lc *LeaveController) Submit(c *gin.Context) {
+	lc.SetContext(c)
+	var body models.SubmitLeaveRequest
+	if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	if body.EmployeeID == "" || !lc.permission.Has(c, models.PermissionManageLeaves) {
+		employee, err := lc.currentEmployee(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+		body.EmployeeID = employee.ID
+	}
+
+	useCase := leaveUseCase.NewSubmitLeaveRequestUsecase(lc.leaveContract, lc.leaveTypeContract, lc.employeeContract, lc.scheduleContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveRequest, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveRequest)
+}
+
+func (lc *LeaveController) Approve(c *gin.Context) {
+	lc.decide(c, models.LeaveDecisionApprove)
+}
+
+func (lc *LeaveController) Reject(c *gin.Context) {
+	lc.decide(c, models.LeaveDecisionReject)
+}
+
+func (lc *LeaveController) decide(c *gin.Context, decision models.LeaveDecision) {
+	lc.SetContext(c)
+	var body models.DecideLeaveRequest
+	if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	approver, err := lc.currentEmployee(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Message})
+		return
+	}
+	body.Decision = decision
+	body.ApproverID = approver.ID
+
+	useCase := leaveUseCase.NewDecideLeaveRequestUsecase(lc.leaveContract, lc.leaveTypeContract, lc.employeeContract, lc.departmentContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveRequest, err := useCase.Execute()
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Type == models.SystemErrorTypeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveRequest)
+}
+
+func (lc *LeaveController) Cancel(c *gin.Context) {
+	lc.SetContext(c)
+	var body models.CancelLeaveRequest
+	if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	employee, err := lc.currentEmployee(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Message})
+		return
+	}
+	body.CancelledBy = employee.ID
+
+	useCase := leaveUseCase.NewCancelLeaveRequestUsecase(lc.leaveContract, lc.employeeContract, lc.departmentContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveRequest, err := useCase.Execute()
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Type == models.SystemErrorTypeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveRequest)
+}
+
+func (lc *LeaveController) Get(c *gin.Context) {
+	lc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := leaveUseCase.NewGetLeaveRequestUsecase(lc.leaveContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveRequest, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveRequest)
+}
+
+func (lc *LeaveController) GetAll(c *gin.Context) {
+	lc.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := lc.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 10},
+		}
+	}
+
+	useCase := leaveUseCase.NewListLeaveRequestsUsecase(lc.leaveContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveRequests, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveRequests)
+}
+
+// Balances answers the balances of the caller, leave managers may read those of another employee
+func (lc *LeaveController) Balances(c *gin.Context) {
+	lc.SetContext(c)
+	var body models.LeaveBalanceQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	}
+	if body.EmployeeID == "" || !lc.permission.Has(c, models.PermissionManageLeaves) {
+		employee, err := lc.currentEmployee(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+		body.EmployeeID = employee.ID
+	}
+
+	useCase := leaveUseCase.NewGetLeaveBalancesUsecase(lc.leaveContract, lc.leaveTypeContract, lc.employeeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	balances, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+func (lc *LeaveController) SaveType(c *gin.Context) {
+	lc.SetContext(c)
+	var body models.LeaveType
+	if _, err := lc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := leaveUseCase.NewSaveLeaveTypeUsecase(lc.leaveTypeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveType, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveType)
+}
+
+func (lc *LeaveController) GetAllTypes(c *gin.Context) {
+	lc.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := lc.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 100},
+		}
+	}
+
+	useCase := leaveUseCase.NewListLeaveTypesUsecase(lc.leaveTypeContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	leaveTypes, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, leaveTypes)
+}
+
+func (lc *LeaveController) RegisterRoutes(router *gin.RouterGroup) {
+	leaves := router.Group("/leaves")
+	leaves.Use(lc.authMiddleware.AuthMiddleware())
+	{
+		leaves.POST("/submit", lc.Submit)
+		leaves.POST("/approve", lc.Approve)
+		leaves.POST("/reject", lc.Reject)
+		leaves.POST("/cancel", lc.Cancel)
+		lc.permission.Guard(leaves).POST("/get", models.PermissionViewMenuLeaveRequests, lc.Get)
+		lc.permission.Guard(leaves).POST("/get-all", models.PermissionViewMenuLeaveRequests, lc.GetAll)
+		leaves.POST("/balances", lc.Balances)
+		lc.permission.Guard(leaves).POST("/types/save", models.PermissionManageLeaves, lc.SaveType)
+		leaves.POST("/types/get-all", lc.GetAllTypes)
+	}
+}
@@ -0,0 +1,395 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	employeeUseCase "hrms.local/core/usecases/employees"
+	payrollUseCase "hrms.local/core/usecases/payroll"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayrollController struct {
+	*types.BaseController
+	periodContract     contracts.PayPeriodContract
+	runContract        contracts.PayrollRunContract
+	payslipContract    contracts.PayslipContract
+	employeeContract   contracts.EmployeeContract
+	positionContract   contracts.PositionContract
+	attendanceContract contracts.AttendanceContract
+	scheduleContract   contracts.WorkScheduleContract
+	departmentContract contracts.DepartmentContract
+	userContract       contracts.UserContract
+	exportContract     contracts.PayrollExportContract
+	rules              []contracts.PayrollRuleContract
+	companyName        string
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewPayrollController(
+	authMiddleware *middleware.AuthMiddleware,
+	permission *middleware.PermissionMiddleware,
+	periodContract contracts.PayPeriodContract,
+	runContract contracts.PayrollRunContract,
+	payslipContract contracts.PayslipContract,
+	employeeContract contracts.EmployeeContract,
+	positionContract contracts.PositionContract,
+	attendanceContract contracts.AttendanceContract,
+	scheduleContract contracts.WorkScheduleContract,
+	departmentContract contracts.DepartmentContract,
+	userContract contracts.UserContract,
+	exportContract contracts.PayrollExportContract,
+	rules []contracts.PayrollRuleContract,
+	companyName string,
+) *PayrollController {
+	return &PayrollController{
+		BaseController:     types.NewBaseController("/payroll"),
+		periodContract:     periodContract,
+		runContract:        runContract,
+		payslipContract:    payslipContract,
+		employeeContract:   employeeContract,
+		positionContract:   positionContract,
+		attendanceContract: attendanceContract,
+		scheduleContract:   scheduleContract,
+		departmentContract: departmentContract,
+		userContract:       userContract,
+		exportContract:     exportContract,
+		rules:              rules,
+		companyName:        companyName,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (pc *PayrollController) SetContext(c *gin.Context) {
+	if r, ok := pc.periodContract.(*repo.PayPeriodRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.runContract.(*repo.PayrollRunRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.payslipContract.(*repo.PayslipRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.positionContract.(*repo.PositionRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.attendanceContract.(*repo.AttendanceRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.scheduleContract.(*repo.WorkScheduleRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// currentEmployee resolves the employee linked to the authenticated user
+func (pc *PayrollController) currentEmployee(c *gin.Context) (*models.Employee, *models.SystemError) {
+	useCase := employeeUseCase.NewGetCurrentEmployeeUseCase(pc.employeeContract, pc.userContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		return nil, err
+	}
+	return useCase.Execute()
+}
+
+// searchQuery reads the SearchQuery of list endpoints, defaulting to the first page
+func (pc *PayrollController) searchQuery(c *gin.Context) (models.SearchQuery, bool) {
+	query := models.SearchQuery{
+		Pagination: models.Pagination{Page: 1, Limit: 10},
+	}
+	if c.Request.ContentLength > 0 {
+		if _, err := pc.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return query, false
+		}
+	}
+	return query, true
+}
+
+func (pc *PayrollController) CreatePeriod(c *gin.Context) {
+	pc.SetContext(c)
+	var body models.PayPeriod
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := payrollUseCase.NewCreatePayPeriodUsecase(pc.periodContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	period, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+func (pc *PayrollController) GetAllPeriods(c *gin.Context) {
+	pc.SetContext(c)
+	query, ok := pc.searchQuery(c)
+	if !ok {
+		return
+	}
+
+	useCase := payrollUseCase.NewListPayPeriodsUsecase(pc.periodContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	periods, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+func (pc *PayrollController) CalculateRun(c *gin.Context) {
+	pc.SetContext(c)
+	var body models.CalculatePayrollRun
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := payrollUseCase.NewCalculatePayrollRunUsecase(pc.runContract, pc.periodContract, pc.employeeContract, pc.positionContract, pc.attendanceContract, pc.scheduleContract, pc.rules, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	run, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func (pc *PayrollController) FinalizeRun(c *gin.Context) {
+	pc.changeRunStatus(c, models.PayrollStatusFinalized)
+}
+
+func (pc *PayrollController) PayRun(c *gin.Context) {
+	pc.changeRunStatus(c, models.PayrollStatusPaid)
+}
+
+func (pc *PayrollController) changeRunStatus(c *gin.Context, status models.PayrollStatus) {
+	pc.SetContext(c)
+	var body models.ChangePayrollRunStatus
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	body.Status = status
+
+	useCase := payrollUseCase.NewChangePayrollRunStatusUsecase(pc.runContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	run, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func (pc *PayrollController) DeleteRun(c *gin.Context) {
+	pc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := payrollUseCase.NewDeletePayrollRunUsecase(pc.runContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	if err := useCase.Execute(); err != nil {
+		status := http.StatusInternalServerError
+		if err.Type == models.SystemErrorTypeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Payroll run deleted"})
+}
+
+func (pc *PayrollController) GetAllRuns(c *gin.Context) {
+	pc.SetContext(c)
+	query, ok := pc.searchQuery(c)
+	if !ok {
+		return
+	}
+
+	useCase := payrollUseCase.NewListPayrollRunsUsecase(pc.runContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	runs, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// payslipNotFound answers missing payslips and the payslips of other employees alike
+const payslipNotFound = "Payslip not found"
+
+// GetPayslip answers a payslip to its employee, or to any caller with view_menu_payroll
+func (pc *PayrollController) GetPayslip(c *gin.Context) {
+	pc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := payrollUseCase.NewGetPayslipUsecase(pc.payslipContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	payslip, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": payslipNotFound})
+		return
+	}
+	// the payslips of others are answered like missing ones, their ids are not confirmed
+	if !pc.permission.Has(c, models.PermissionViewMenuPayroll) {
+		employee, err := pc.currentEmployee(c)
+		if err != nil || employee.ID != payslip.EmployeeID {
+			c.JSON(http.StatusNotFound, gin.H{"error": payslipNotFound})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, payslip)
+}
+
+func (pc *PayrollController) GetAllPayslips(c *gin.Context) {
+	pc.SetContext(c)
+	query, ok := pc.searchQuery(c)
+	if !ok {
+		return
+	}
+
+	useCase := payrollUseCase.NewListPayslipsUsecase(pc.payslipContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	payslips, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, payslips)
+}
+
+func (pc *PayrollController) ExportPayslip(c *gin.Context) {
+	pc.SetContext(c)
+	id := c.Query("id")
+
+	useCase := payrollUseCase.NewExportPayslipUsecase(pc.payslipContract, pc.runContract, pc.periodContract, pc.employeeContract, pc.positionContract, pc.departmentContract, pc.exportContract, pc.companyName, id)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	pdf, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=payslip_%s.pdf", id))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (pc *PayrollController) ExportBankFile(c *gin.Context) {
+	pc.SetContext(c)
+	request := models.ExportBankFile{
+		RunID:  c.Query("run_id"),
+		Format: models.BankFileFormat(c.DefaultQuery("format", string(models.BankFileFormatCSV))),
+	}
+
+	useCase := payrollUseCase.NewExportBankFileUsecase(pc.runContract, pc.periodContract, pc.payslipContract, pc.employeeContract, pc.exportContract, pc.companyName, contracts.NewGenericRequest(request))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	file, err := useCase.Execute()
+	if err != nil {
+		// missing bank accounts, mixed currencies and values too long for the format are the data to fix
+		status := http.StatusInternalServerError
+		if err.Type == models.SystemErrorTypeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if request.Format == models.BankFileFormatFixedWidth {
+		contentType = "text/plain; charset=us-ascii"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=bank_transfers_%s.%s", request.RunID, request.Format.Extension()))
+	c.Data(http.StatusOK, contentType, file)
+}
+
+func (pc *PayrollController) RegisterRoutes(router *gin.RouterGroup) {
+	payroll := router.Group("/payroll")
+	payroll.Use(pc.authMiddleware.AuthMiddleware())
+	{
+		pc.permission.Guard(payroll).POST("/periods/create", models.PermissionManagePayroll, pc.CreatePeriod)
+		pc.permission.Guard(payroll).POST("/periods/get-all", models.PermissionViewMenuPayroll, pc.GetAllPeriods)
+		pc.permission.Guard(payroll).POST("/runs/calculate", models.PermissionManagePayroll, pc.CalculateRun)
+		pc.permission.Guard(payroll).POST("/runs/finalize", models.PermissionManagePayroll, pc.FinalizeRun)
+		pc.permission.Guard(payroll).POST("/runs/pay", models.PermissionManagePayroll, pc.PayRun)
+		pc.permission.Guard(payroll).POST("/runs/delete", models.PermissionManagePayroll, pc.DeleteRun)
+		pc.permission.Guard(payroll).POST("/runs/get-all", models.PermissionViewMenuPayroll, pc.GetAllRuns)
+		payroll.POST("/payslips/get", pc.GetPayslip)
+		pc.permission.Guard(payroll).POST("/payslips/get-all", models.PermissionViewMenuPayroll, pc.GetAllPayslips)
+		pc.permission.Guard(payroll).GET("/payslips/pdf", models.PermissionExportPayroll, pc.ExportPayslip)
+		pc.permission.Guard(payroll).GET("/runs/bank-file", models.PermissionExportPayroll, pc.ExportBankFile)
+	}
+}
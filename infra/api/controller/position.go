@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	positionUseCase "hrms.local/core/usecases/positions"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PositionController struct {
+	*types.BaseController
+	positionContract   contracts.PositionContract
+	departmentContract contracts.DepartmentContract
+	employeeContract   contracts.EmployeeContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewPositionController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, positionContract contracts.PositionContract, departmentContract contracts.DepartmentContract, employeeContract contracts.EmployeeContract) *PositionController {
+	return &PositionController{
+		BaseController:     types.NewBaseController("/positions"),
+		positionContract:   positionContract,
+		departmentContract: departmentContract,
+		employeeContract:   employeeContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (pc *PositionController) SetContext(c *gin.Context) {
+	if r, ok := pc.positionContract.(*repo.PositionRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.departmentContract.(*repo.DepartmentRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := pc.employeeContract.(*repo.EmployeeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+func (pc *PositionController) Create(c *gin.Context) {
+	pc.SetContext(c)
+	var body models.CreatePosition
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := positionUseCase.NewCreatePositionUsecase(pc.positionContract, pc.departmentContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	position, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+func (pc *PositionController) Update(c *gin.Context) {
+	pc.SetContext(c)
+	var body models.Position
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := positionUseCase.NewUpdatePositionUsecase(pc.positionContract, pc.departmentContract, pc.employeeContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	position, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+func (pc *PositionController) Delete(c *gin.Context) {
+	pc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := positionUseCase.NewDeletePositionUsecase(pc.positionContract, pc.employeeContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	if err := useCase.Execute(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Position deleted"})
+}
+
+func (pc *PositionController) Get(c *gin.Context) {
+	pc.SetContext(c)
+	var body struct {
+		ID string `json:"id"`
+	}
+	if _, err := pc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := positionUseCase.NewGetPositionUsecase(pc.positionContract, body.ID)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	position, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+func (pc *PositionController) GetAll(c *gin.Context) {
+	pc.SetContext(c)
+	var query models.SearchQuery
+	if c.Request.ContentLength > 0 {
+		if _, err := pc.BaseController.GetBody(c, &query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			return
+		}
+	} else {
+		query = models.SearchQuery{
+			Pagination: models.Pagination{Page: 1, Limit: 100},
+		}
+	}
+
+	useCase := positionUseCase.NewListPositionsUsecase(pc.positionContract, contracts.NewGenericRequest(query))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	positions, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, positions)
+}
+
+func (pc *PositionController) RegisterRoutes(router *gin.RouterGroup) {
+	positions := router.Group("/positions")
+	positions.Use(pc.authMiddleware.AuthMiddleware())
+	{
+		pc.permission.Guard(positions).POST("/create", models.PermissionEditPositions, pc.Create)
+		pc.permission.Guard(positions).POST("/update", models.PermissionEditPositions, pc.Update)
+		pc.permission.Guard(positions).POST("/delete", models.PermissionEditPositions, pc.Delete)
+		pc.permission.Guard(positions).POST("/get", models.PermissionViewMenuPosition, pc.Get)
+		pc.permission.Guard(positions).POST("/get-all", models.PermissionViewMenuPosition, pc.GetAll)
+	}
+}
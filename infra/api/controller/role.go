@@ -18,15 +18,19 @@ type RoleController struct {
 	*types.BaseController
 	roleContract       contracts.RoleContract
 	permissionContract contracts.PermissionContract
+	userContract       contracts.UserContract
 	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
 }
 
-func NewRoleController(authMiddleware *middleware.AuthMiddleware, roleContract contracts.RoleContract, permissionContract contracts.PermissionContract) *RoleController {
+func NewRoleController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, roleContract contracts.RoleContract, permissionContract contracts.PermissionContract, userContract contracts.UserContract) *RoleController {
 	return &RoleController{
 		BaseController:     types.NewBaseController("/roles"),
 		roleContract:       roleContract,
 		permissionContract: permissionContract,
+		userContract:       userContract,
 		authMiddleware:     authMiddleware,
+		permission:         permission,
 	}
 }
 
@@ -34,6 +38,9 @@ func (rc *RoleController) SetContext(c *gin.Context) {
 	if r, ok := rc.roleContract.(*repo.RoleRepository); ok {
 		r.WithContext(c.Request.Context())
 	}
+	if r, ok := rc.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
 }
 
 func (rc *RoleController) Create(c *gin.Context) {
@@ -90,7 +97,7 @@ func (rc *RoleController) Delete(c *gin.Context) {
 		return
 	}
 
-	useCase := roleUseCase.NewDeleteRoleUsecase(rc.roleContract, body.ID)
+	useCase := roleUseCase.NewDeleteRoleUsecase(rc.roleContract, rc.userContract, body.ID)
 	if err := useCase.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
 		return
@@ -191,12 +198,12 @@ func (rc *RoleController) RegisterRoutes(router *gin.RouterGroup) {
 	roles := router.Group("/roles")
 	roles.Use(rc.authMiddleware.AuthMiddleware())
 	{
-		roles.POST("/create", rc.Create)
-		roles.POST("/update", rc.Update)
-		roles.POST("/delete", rc.Delete)
-		roles.POST("/get", rc.Get)
-		roles.POST("/get-all", rc.GetAll)
-		roles.GET("/get-permissions/:role_id", rc.GetPermissions)
-		roles.GET("/system-permissions", rc.ListSystemPermissions)
+		rc.permission.Guard(roles).POST("/create", models.PermissionEditRoles, rc.Create)
+		rc.permission.Guard(roles).POST("/update", models.PermissionEditRoles, rc.Update)
+		rc.permission.Guard(roles).POST("/delete", models.PermissionEditRoles, rc.Delete)
+		rc.permission.Guard(roles).POST("/get", models.PermissionViewRoles, rc.Get)
+		rc.permission.Guard(roles).POST("/get-all", models.PermissionViewRoles, rc.GetAll)
+		rc.permission.Guard(roles).GET("/get-permissions/:role_id", models.PermissionViewRoles, rc.GetPermissions)
+		rc.permission.Guard(roles).GET("/system-permissions", models.PermissionViewRoles, rc.ListSystemPermissions)
 	}
 }
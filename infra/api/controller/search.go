@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	searchUseCase "hrms.local/core/usecases/search"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchController finds users and employees by name, email, employee number or department
+type SearchController struct {
+	*types.BaseController
+	searchContract contracts.SearchContract
+	authMiddleware *middleware.AuthMiddleware
+	permission     *middleware.PermissionMiddleware
+}
+
+func NewSearchController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, searchContract contracts.SearchContract) *SearchController {
+	return &SearchController{
+		BaseController: types.NewBaseController("/search"),
+		searchContract: searchContract,
+		authMiddleware: authMiddleware,
+		permission:     permission,
+	}
+}
+
+func (sc *SearchController) SetContext(c *gin.Context) {
+	if r, ok := sc.searchContract.(*repo.SearchRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// Search answers the users the caller may view with view_users and the employees with view_employees
+func (sc *SearchController) Search(c *gin.Context) {
+	sc.SetContext(c)
+	var body models.PeopleSearch
+	if _, err := sc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	body.ActorPermissions = middleware.Permissions(c)
+
+	useCase := searchUseCase.NewSearchPeopleUseCase(sc.searchContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	results, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (sc *SearchController) RegisterRoutes(router *gin.RouterGroup) {
+	search := router.Group("/search")
+	search.Use(sc.authMiddleware.AuthMiddleware())
+	{
+		search.POST("", sc.permission.RequireAnyPermission(models.PermissionViewUsers, models.PermissionViewEmployees), sc.Search)
+	}
+}
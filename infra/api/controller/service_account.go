@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"net/http"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	apiKeyUseCase "hrms.local/core/usecases/apikeys"
+	"hrms.local/infra/api/middleware"
+	"hrms.local/infra/api/types"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAccountController manages the service accounts of the integrations and their API keys
+type ServiceAccountController struct {
+	*types.BaseController
+	accountContract    contracts.ServiceAccountContract
+	apiKeyContract     contracts.APIKeyContract
+	permissionContract contracts.PermissionContract
+	auditContract      contracts.AuditContract
+	authMiddleware     *middleware.AuthMiddleware
+	permission         *middleware.PermissionMiddleware
+}
+
+func NewServiceAccountController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, accountContract contracts.ServiceAccountContract, apiKeyContract contracts.APIKeyContract, permissionContract contracts.PermissionContract, auditContract contracts.AuditContract) *ServiceAccountController {
+	return &ServiceAccountController{
+		BaseController:     types.NewBaseController("/service-accounts"),
+		accountContract:    accountContract,
+		apiKeyContract:     apiKeyContract,
+		permissionContract: permissionContract,
+		auditContract:      auditContract,
+		authMiddleware:     authMiddleware,
+		permission:         permission,
+	}
+}
+
+func (sc *ServiceAccountController) SetContext(c *gin.Context) {
+	if r, ok := sc.accountContract.(*repo.ServiceAccountRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := sc.apiKeyContract.(*repo.APIKeyRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := sc.auditContract.(*repo.AuditRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// humanOnly keeps API keys from managing API keys, a leaked key could otherwise mint new ones
+func humanOnly(c *gin.Context) {
+	if middleware.IsServiceAccount(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Las claves de API no pueden administrar claves"})
+		return
+	}
+	c.Next()
+}
+
+func (sc *ServiceAccountController) Create(c *gin.Context) {
+	sc.SetContext(c)
+	var body models.CreateServiceAccount
+	if _, err := sc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	body.Actor = c.GetString("userID")
+
+	useCase := apiKeyUseCase.NewCreateServiceAccountUseCase(sc.accountContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	account, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+func (sc *ServiceAccountController) List(c *gin.Context) {
+	sc.SetContext(c)
+	useCase := apiKeyUseCase.NewListServiceAccountsUseCase(sc.accountContract)
+	accounts, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+// CreateKey answers the key once, only its prefix is shown afterwards
+func (sc *ServiceAccountController) CreateKey(c *gin.Context) {
+	sc.SetContext(c)
+	var body models.CreateAPIKey
+	if _, err := sc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	body.Actor = c.GetString("userID")
+	body.ActorPermissions = middleware.Permissions(c)
+
+	useCase := apiKeyUseCase.NewCreateAPIKeyUseCase(sc.apiKeyContract, sc.accountContract, sc.permissionContract, sc.auditContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	key, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+func (sc *ServiceAccountController) ListKeys(c *gin.Context) {
+	sc.SetContext(c)
+	var body models.ListAPIKeys
+	if _, err := sc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+
+	useCase := apiKeyUseCase.NewListAPIKeysUseCase(sc.apiKeyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	keys, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+func (sc *ServiceAccountController) RevokeKey(c *gin.Context) {
+	sc.SetContext(c)
+	var body models.RevokeAPIKey
+	if _, err := sc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	body.Actor = c.GetString("userID")
+	body.IP = c.ClientIP()
+
+	useCase := apiKeyUseCase.NewRevokeAPIKeyUseCase(sc.apiKeyContract, sc.auditContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		status := http.StatusInternalServerError
+		if err.Code == models.SystemErrorCodeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Clave revocada"})
+}
+
+func (sc *ServiceAccountController) RegisterRoutes(router *gin.RouterGroup) {
+	accounts := router.Group("/service-accounts")
+	accounts.Use(sc.authMiddleware.AuthMiddleware())
+	{
+		sc.permission.Guard(accounts).POST("/create", models.PermissionManageAPIKeys, humanOnly, sc.Create)
+		sc.permission.Guard(accounts).POST("/list", models.PermissionManageAPIKeys, humanOnly, sc.List)
+		sc.permission.Guard(accounts).POST("/keys/create", models.PermissionManageAPIKeys, humanOnly, sc.CreateKey)
+		sc.permission.Guard(accounts).POST("/keys/list", models.PermissionManageAPIKeys, humanOnly, sc.ListKeys)
+		sc.permission.Guard(accounts).POST("/keys/revoke", models.PermissionManageAPIKeys, humanOnly, sc.RevokeKey)
+	}
+}
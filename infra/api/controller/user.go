@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"log"
 	"net/http"
 
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
+	sessionUseCase "hrms.local/core/usecases/sessions"
 	userUseCase "hrms.local/core/usecases/users"
 	"hrms.local/infra/api/middleware"
 	"hrms.local/infra/api/types"
@@ -15,16 +17,57 @@ import (
 
 type UserController struct {
 	*types.BaseController
-	userContract         contracts.UserContract
+	userContract       contracts.UserContract
+	roleContract       contracts.RoleContract
+	refreshContract    contracts.RefreshTokenContract
+	denylistContract   contracts.TokenDenylistContract
+	auditContract      contracts.AuditContract
+	historyContract    contracts.PasswordHistoryContract
+	policyContract     contracts.PasswordPolicyContract
+	signerContract     contracts.TokenSignerContract
+	otpContract        contracts.OTPContract
+	recoveryContract   contracts.RecoveryCodeContract
+	tokenContract      contracts.AccountTokenContract
+	invitationContract contracts.InvitationContract
+	mailerContract     contracts.MailerContract
+	templateContract   contracts.MailTemplateContract
+	// Base URL of the web client receiving the mailed links
+	appURL string
+	// Who can use the public registration
+	registration models.RegistrationSettings
+	// Single sign-on provider, nil when OpenID Connect is not configured
+	oidcContract         contracts.OIDCProviderContract
+	oidcSettings         models.OIDCSettings
 	authMiddleware       *middleware.AuthMiddleware
+	permission           *middleware.PermissionMiddleware
+	loginLimiter         *middleware.RateLimiter
 	cryptographyContract contracts.CryptographyContract
 }
 
-func NewUserController(authMiddleware *middleware.AuthMiddleware, userContract contracts.UserContract, cryptographyContract contracts.CryptographyContract) *UserController {
+func NewUserController(authMiddleware *middleware.AuthMiddleware, permission *middleware.PermissionMiddleware, loginLimiter *middleware.RateLimiter, userContract contracts.UserContract, roleContract contracts.RoleContract, refreshContract contracts.RefreshTokenContract, denylistContract contracts.TokenDenylistContract, auditContract contracts.AuditContract, historyContract contracts.PasswordHistoryContract, policyContract contracts.PasswordPolicyContract, signerContract contracts.TokenSignerContract, otpContract contracts.OTPContract, recoveryContract contracts.RecoveryCodeContract, tokenContract contracts.AccountTokenContract, invitationContract contracts.InvitationContract, mailerContract contracts.MailerContract, templateContract contracts.MailTemplateContract, appURL string, registration models.RegistrationSettings, oidcContract contracts.OIDCProviderContract, oidcSettings models.OIDCSettings, cryptographyContract contracts.CryptographyContract) *UserController {
 	return &UserController{
 		BaseController:       types.NewBaseController("/auth"),
 		userContract:         userContract,
+		roleContract:         roleContract,
+		refreshContract:      refreshContract,
+		denylistContract:     denylistContract,
+		auditContract:        auditContract,
+		historyContract:      historyContract,
+		policyContract:       policyContract,
+		signerContract:       signerContract,
+		otpContract:          otpContract,
+		recoveryContract:     recoveryContract,
+		tokenContract:        tokenContract,
+		invitationContract:   invitationContract,
+		mailerContract:       mailerContract,
+		templateContract:     templateContract,
+		appURL:               appURL,
+		registration:         registration,
+		oidcContract:         oidcContract,
+		oidcSettings:         oidcSettings,
 		authMiddleware:       authMiddleware,
+		permission:           permission,
+		loginLimiter:         loginLimiter,
 		cryptographyContract: cryptographyContract,
 	}
 }
@@ -33,8 +76,78 @@ func (uc *UserController) SetContext(c *gin.Context) {
 	if r, ok := uc.userContract.(*repo.UserRepository); ok {
 		r.WithContext(c.Request.Context())
 	}
+	if r, ok := uc.roleContract.(*repo.RoleRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.refreshContract.(*repo.RefreshTokenRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.denylistContract.(*repo.TokenDenylistRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.auditContract.(*repo.AuditRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.historyContract.(*repo.PasswordHistoryRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.recoveryContract.(*repo.RecoveryCodeRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.tokenContract.(*repo.AccountTokenRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := uc.invitationContract.(*repo.InvitationRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+}
+
+// invalidRequest answers 400 with the details of the error, password policy errors list the broken rules
+func invalidRequest(c *gin.Context, err *models.SystemError) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Message, "details": err.Details})
+	c.Abort()
+}
+
+// failedRequest answers 401 to authentication errors and 500 to the rest
+func failedRequest(c *gin.Context, err *models.SystemError) {
+	status := http.StatusInternalServerError
+	if err.Code == models.SystemErrorCodeAuth {
+		status = http.StatusUnauthorized
+	}
+	c.JSON(status, gin.H{"error": err.Message})
+	c.Abort()
+}
+
+// RegisterUser is the public registration, the server decides the type and the role of the new user
+func (uc *UserController) RegisterUser(c *gin.Context) {
+	var body models.RegisterUser
+	uc.SetContext(c)
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+
+	useCase := userUseCase.NewRegisterUserUseCase(uc.userContract, uc.roleContract, uc.invitationContract, uc.policyContract, uc.historyContract, uc.cryptographyContract, uc.registration, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	// invited users proved their email by opening the invitation
+	if !data.EmailVerified {
+		if err := uc.sendEmailVerification(c, data.Username); err != nil {
+			log.Printf("Failed to send email verification to %s: %s", data.Username, err.Message)
+		}
+	}
+	c.JSON(http.StatusOK, data)
 }
 
+// CreateUser lets users with edit_users create accounts of any type and role
 func (uc *UserController) CreateUser(c *gin.Context) {
 	var body models.CreateUser
 	uc.SetContext(c)
@@ -45,10 +158,9 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 		return
 	}
 
-	useCase := userUseCase.NewCreateUserUseCase(uc.userContract, contracts.NewGenericRequest(body), uc.cryptographyContract)
+	useCase := userUseCase.NewCreateUserUseCase(uc.userContract, uc.roleContract, uc.policyContract, uc.historyContract, contracts.NewGenericRequest(body), uc.cryptographyContract)
 	if err := useCase.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
-		c.Abort()
+		invalidRequest(c, err)
 		return
 	}
 	data, err := useCase.Execute()
@@ -57,9 +169,26 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 		c.Abort()
 		return
 	}
+	// the account exists even when the mail fails, the user can ask for another link
+	if err := uc.sendEmailVerification(c, data.Username); err != nil {
+		log.Printf("Failed to send email verification to %s: %s", data.Username, err.Message)
+	}
 	c.JSON(http.StatusOK, data)
 }
 
+func (uc *UserController) sendEmailVerification(c *gin.Context, username string) *models.SystemError {
+	request := models.SendEmailVerification{
+		Username:  username,
+		Language:  models.ParseLanguage(c.GetHeader("Accept-Language")),
+		VerifyURL: uc.appURL + "/verify-email",
+	}
+	useCase := userUseCase.NewSendEmailVerificationUseCase(uc.userContract, uc.tokenContract, uc.mailerContract, uc.templateContract, contracts.NewGenericRequest(request))
+	if err := useCase.Validate(); err != nil {
+		return err
+	}
+	return useCase.Execute()
+}
+
 func (uc *UserController) LoginUser(c *gin.Context) {
 	var body models.LoginUser
 	uc.SetContext(c)
@@ -70,45 +199,254 @@ func (uc *UserController) LoginUser(c *gin.Context) {
 		return
 	}
 
-	useCase := userUseCase.NewLoginUserUseCase(uc.userContract, contracts.NewGenericRequest(body), uc.cryptographyContract)
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewLoginUserUseCase(uc.userContract, uc.roleContract, uc.auditContract, uc.policyContract, uc.signerContract, contracts.NewGenericRequest(body), uc.cryptographyContract)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	result, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	uc.finishLogin(c, result)
+}
+
+// finishLogin starts the session of a login, or answers the challenge when a second factor is required
+func (uc *UserController) finishLogin(c *gin.Context, result *models.LoginResult) {
+	if result.Challenge != nil {
+		// the client has to send the token with a code to /auth/mfa/verify
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    result.Challenge.Token,
+			"expires_in":   int(models.MFAChallengeTTL.Seconds()),
+		})
+		return
+	}
+	uc.startSession(c, result.User)
+}
+
+// StartOIDCLogin returns the provider URL to send the user to and the flow the client keeps for the callback
+func (uc *UserController) StartOIDCLogin(c *gin.Context) {
+	useCase := userUseCase.NewStartOIDCLoginUseCase(uc.oidcContract, uc.signerContract)
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	authorization, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authorization)
+}
+
+// OIDCCallback completes a single sign-on with the code and state the provider redirected the client with
+func (uc *UserController) OIDCCallback(c *gin.Context) {
+	var body models.OIDCCallback
+	uc.SetContext(c)
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewOIDCLoginUseCase(uc.userContract, uc.roleContract, uc.refreshContract, uc.auditContract, uc.oidcContract, uc.signerContract, uc.oidcSettings, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	result, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	uc.finishLogin(c, result)
+}
+
+// VerifyMFA completes a login challenged for its second factor
+func (uc *UserController) VerifyMFA(c *gin.Context) {
+	var body models.VerifyMFA
+	uc.SetContext(c)
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewVerifyMFAUseCase(uc.userContract, uc.roleContract, uc.auditContract, uc.denylistContract, uc.signerContract, uc.otpContract, uc.recoveryContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
 	if err := useCase.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
 		c.Abort()
 		return
 	}
 	data, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	uc.startSession(c, data)
+}
+
+// startSession issues the refresh and access tokens of a logged in user
+func (uc *UserController) startSession(c *gin.Context, data *models.UserData) {
+	session := sessionUseCase.NewStartSessionUsecase(uc.refreshContract, data)
+	if err := session.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	started, err := session.Execute()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
 		c.Abort()
 		return
 	}
-	tokenData, err2 := uc.authMiddleware.GenerateToken(data.Username, map[string]interface{}{
-		"username": data.Username,
-		"type":     data.Type,
-		"email":    data.Email,
+	tokens, ok := uc.tokenPair(c, started)
+	if !ok {
+		return
+	}
+	response := gin.H{
+		"username":      data.Username,
+		"type":          data.Type,
+		"email":         data.Email,
+		"picture":       data.Picture,
+		"role":          data.Role,
+		"permissions":   data.Permissions,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		// the client has to send the user to /auth/me/update, every other route answers 403
+		"must_change_password": data.MustChangePassword,
+		// the client has to send the user to enroll MFA, every other route answers 403
+		"mfa_setup_required": data.MFASetupRequired,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// tokenPair signs the access token of a session
+func (uc *UserController) tokenPair(c *gin.Context, session *models.Session) (*models.TokenPair, bool) {
+	tokenData, err := uc.authMiddleware.GenerateToken(session.User.Username, map[string]interface{}{
+		"username": session.User.Username,
+		"type":     session.User.Type,
+		"email":    session.User.Email,
+		// restricts the token to changing the expired password, see AuthConfig.PasswordChangeRoutes
+		"must_change_password": session.User.MustChangePassword,
+		// restricts the token to enrolling MFA, see AuthConfig.MFASetupRoutes
+		"mfa_setup_required": session.User.MFASetupRequired,
 	})
-	if err2 != nil {
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al generar el token"})
 		c.Abort()
+		return nil, false
+	}
+	return &models.TokenPair{
+		AccessToken:  tokenData,
+		RefreshToken: session.RefreshToken,
+		ExpiresIn:    int(models.AccessTokenTTL.Seconds()),
+	}, true
+}
+
+func (uc *UserController) RefreshToken(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.RefreshSession
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
 		return
 	}
-	response := gin.H{
-		"username": data.Username,
-		"type":     data.Type,
-		"email":    data.Email,
-		"picture":  "",
-		"role":     "",
-		"token":    tokenData,
+
+	useCase := sessionUseCase.NewRefreshSessionUsecase(uc.refreshContract, uc.userContract, uc.roleContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
 	}
-	c.JSON(http.StatusOK, response)
+	session, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	tokens, ok := uc.tokenPair(c, session)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
 }
 
 func (uc *UserController) LogoutUser(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.Logout
+	if c.Request.ContentLength > 0 {
+		if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+			c.Abort()
+			return
+		}
+	}
+	body.JTI = c.GetString("jti")
+	body.Username = c.GetString("userID")
+	body.ExpiresAt = c.GetTime("tokenExpiresAt")
+
+	useCase := sessionUseCase.NewLogoutUsecase(uc.refreshContract, uc.denylistContract, uc.userContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
 }
 
 func (uc *UserController) Me(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Me successful"})
+	uc.SetContext(c)
+	useCase := userUseCase.NewGetCurrentUserUseCase(uc.userContract, uc.roleContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) UpdateProfile(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.UpdateProfile
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.Username = c.GetString("userID")
+
+	useCase := userUseCase.NewUpdateProfileUseCase(uc.userContract, uc.roleContract, uc.refreshContract, uc.policyContract, uc.historyContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, data)
 }
 
 func (uc *UserController) GetUserByField(c *gin.Context) {
@@ -184,13 +522,252 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 	// Assuming NewModifyUserUseCase exists and has this signature
 	request := contracts.NewGenericRequest(body)
-	user := userUseCase.NewModifyUserUseCase(uc.userContract, request)
+	user := userUseCase.NewModifyUserUseCase(uc.userContract, uc.roleContract, uc.refreshContract, uc.policyContract, uc.historyContract, uc.cryptographyContract, request)
 	if err := user.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	data, err := user.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) UnlockUser(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.UnlockUser
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
 		c.Abort()
 		return
 	}
-	data, err := user.Execute()
+	body.Actor = c.GetString("userID")
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewUnlockUserUseCase(uc.userContract, uc.auditContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario desbloqueado"})
+}
+
+// ForgotPassword answers the same whether the email has an account or not
+func (uc *UserController) ForgotPassword(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.ForgotPassword
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if body.Language == "" {
+		body.Language = models.ParseLanguage(c.GetHeader("Accept-Language"))
+	}
+	body.ResetURL = uc.appURL + "/reset-password"
+
+	useCase := userUseCase.NewForgotPasswordUseCase(uc.userContract, uc.tokenContract, uc.mailerContract, uc.templateContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	// a failure would tell registered emails apart, it is only logged
+	if err := useCase.Execute(); err != nil {
+		log.Printf("Failed to send password reset: %s", err.Message)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Si el correo está registrado recibirá un enlace para restablecer la contraseña"})
+}
+
+func (uc *UserController) ResetPassword(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.ResetPassword
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewResetPasswordUseCase(uc.userContract, uc.tokenContract, uc.refreshContract, uc.auditContract, uc.policyContract, uc.historyContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Contraseña restablecida"})
+}
+
+func (uc *UserController) VerifyEmail(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.VerifyEmail
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+
+	useCase := userUseCase.NewVerifyEmailUseCase(uc.userContract, uc.tokenContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Correo verificado"})
+}
+
+// ResendEmailVerification mails a new verification link to the authenticated user
+func (uc *UserController) ResendEmailVerification(c *gin.Context) {
+	uc.SetContext(c)
+	if err := uc.sendEmailVerification(c, c.GetString("userID")); err != nil {
+		status := http.StatusInternalServerError
+		if err.Code == models.SystemErrorCodeValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Enlace de verificación enviado"})
+}
+
+func (uc *UserController) EnrollMFA(c *gin.Context) {
+	uc.SetContext(c)
+	useCase := userUseCase.NewEnrollMFAUseCase(uc.userContract, uc.otpContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) ActivateMFA(c *gin.Context) {
+	uc.SetContext(c)
+	body, ok := uc.mfaCode(c)
+	if !ok {
+		return
+	}
+	useCase := userUseCase.NewActivateMFAUseCase(uc.userContract, uc.otpContract, uc.recoveryContract, uc.auditContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) DisableMFA(c *gin.Context) {
+	uc.SetContext(c)
+	body, ok := uc.mfaCode(c)
+	if !ok {
+		return
+	}
+	useCase := userUseCase.NewDisableMFAUseCase(uc.userContract, uc.roleContract, uc.otpContract, uc.recoveryContract, uc.auditContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Verificación en dos pasos desactivada"})
+}
+
+func (uc *UserController) RegenerateRecoveryCodes(c *gin.Context) {
+	uc.SetContext(c)
+	body, ok := uc.mfaCode(c)
+	if !ok {
+		return
+	}
+	useCase := userUseCase.NewRegenerateRecoveryCodesUseCase(uc.userContract, uc.otpContract, uc.recoveryContract, uc.cryptographyContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	data, err := useCase.Execute()
+	if err != nil {
+		failedRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) ResetMFA(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.UnlockUser
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.Actor = c.GetString("userID")
+	body.IP = c.ClientIP()
+
+	useCase := userUseCase.NewResetMFAUseCase(uc.userContract, uc.recoveryContract, uc.auditContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Verificación en dos pasos restablecida"})
+}
+
+func (uc *UserController) CreateInvitation(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.CreateInvitation
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if body.Language == "" {
+		body.Language = models.ParseLanguage(c.GetHeader("Accept-Language"))
+	}
+	body.Actor = c.GetString("userID")
+	body.AcceptURL = uc.appURL + "/register"
+
+	useCase := userUseCase.NewCreateInvitationUseCase(uc.invitationContract, uc.userContract, uc.roleContract, uc.auditContract, uc.mailerContract, uc.templateContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	data, err := useCase.Execute()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
 		c.Abort()
@@ -199,22 +776,99 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, data)
 }
 
+func (uc *UserController) ListInvitations(c *gin.Context) {
+	uc.SetContext(c)
+	useCase := userUseCase.NewListInvitationsUseCase(uc.invitationContract)
+	data, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (uc *UserController) RevokeInvitation(c *gin.Context) {
+	uc.SetContext(c)
+	var body models.RevokeInvitation
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	body.Actor = c.GetString("userID")
+
+	useCase := userUseCase.NewRevokeInvitationUseCase(uc.invitationContract, contracts.NewGenericRequest(body))
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	if err := useCase.Execute(); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitación revocada"})
+}
+
+// mfaCode reads the code sent to confirm an MFA operation of the authenticated user
+func (uc *UserController) mfaCode(c *gin.Context) (models.MFACode, bool) {
+	var body models.MFACode
+	if _, err := uc.BaseController.GetBody(c, &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Message})
+		c.Abort()
+		return body, false
+	}
+	body.Username = c.GetString("userID")
+	body.IP = c.ClientIP()
+	return body, true
+}
+
 func (uc *UserController) RegisterRoutes(router *gin.RouterGroup) {
 	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/create")
 	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/login")
+	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/refresh")
+	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/mfa/verify")
+	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/forgot-password")
+	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/reset-password")
+	uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/verify-email")
 	routeController := router.Group("/auth")
 	public := routeController.Group("/")
 	{
-		public.POST("/login", uc.LoginUser)
-		public.POST("/create", uc.CreateUser)
+		public.POST("/login", uc.loginLimiter.Limit(), uc.LoginUser)
+		public.POST("/create", uc.loginLimiter.Limit(), uc.RegisterUser)
+		public.POST("/refresh", uc.RefreshToken)
+		public.POST("/mfa/verify", uc.loginLimiter.Limit(), uc.VerifyMFA)
+		public.POST("/forgot-password", uc.loginLimiter.Limit(), uc.ForgotPassword)
+		public.POST("/reset-password", uc.loginLimiter.Limit(), uc.ResetPassword)
+		public.POST("/verify-email", uc.VerifyEmail)
+	}
+	if uc.oidcContract != nil {
+		uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/oidc/login")
+		uc.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/oidc/callback")
+		public.POST("/oidc/login", uc.loginLimiter.Limit(), uc.StartOIDCLogin)
+		public.POST("/oidc/callback", uc.loginLimiter.Limit(), uc.OIDCCallback)
 	}
 	private := router.Group("/auth")
 	private.Use(uc.authMiddleware.AuthMiddleware())
 	{
 		private.GET("/me", uc.Me)
-		private.POST("/list", uc.ListUsers)
-		private.POST("/get-user-by-field", uc.GetUserByField)
-		private.POST("/update", uc.UpdateUser)
+		private.POST("/me/update", uc.UpdateProfile)
+		private.POST("/me/verify-email", uc.ResendEmailVerification)
+		private.POST("/logout", uc.LogoutUser)
+		uc.permission.Guard(private).POST("/list", models.PermissionViewUsers, uc.ListUsers)
+		uc.permission.Guard(private).POST("/get-user-by-field", models.PermissionViewUsers, uc.GetUserByField)
+		uc.permission.Guard(private).POST("/update", models.PermissionEditUsers, uc.UpdateUser)
+		uc.permission.Guard(private).POST("/unlock", models.PermissionEditUsers, uc.UnlockUser)
+		uc.permission.Guard(private).POST("/users/create", models.PermissionEditUsers, uc.CreateUser)
+		uc.permission.Guard(private).POST("/invitations", models.PermissionEditUsers, uc.ListInvitations)
+		uc.permission.Guard(private).POST("/invitations/create", models.PermissionEditUsers, uc.CreateInvitation)
+		uc.permission.Guard(private).POST("/invitations/revoke", models.PermissionEditUsers, uc.RevokeInvitation)
+		private.POST("/mfa/enroll", uc.EnrollMFA)
+		private.POST("/mfa/activate", uc.ActivateMFA)
+		private.POST("/mfa/disable", uc.DisableMFA)
+		private.POST("/mfa/recovery-codes", uc.RegenerateRecoveryCodes)
+		uc.permission.Guard(private).POST("/mfa/reset", models.PermissionEditUsers, uc.ResetMFA)
 
 	}
 	//return router
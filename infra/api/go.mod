@@ -7,6 +7,8 @@ require (
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/joho/godotenv v1.5.1
 	hrms.local/core v0.0.0
+	hrms.local/export v0.0.0
+	hrms.local/mail v0.0.0
 	hrms.local/repository v0.0.0
 )
 
@@ -54,6 +56,10 @@ require (
 	hrms.local/security v0.0.0
 )
 
+replace hrms.local/export v0.0.0 => ../../boundaries/export
+
+replace hrms.local/mail v0.0.0 => ../../boundaries/mail
+
 replace hrms.local/security v0.0.0 => ../../boundaries/security
 
 replace hrms.local/core v0.0.0 => ../../core
@@ -1,10 +1,13 @@
 package middleware
 
 import (
-	"fmt"
+	"crypto/rand"
+	"encoding/hex"
+	"hrms.local/core/contracts"
 	"hrms.local/core/models"
+	"hrms.local/core/usecases/apikeys"
+	"hrms.local/repository/postgress/repo"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -12,19 +15,24 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// serviceAccountKey marks the requests authenticated with an API key
+const serviceAccountKey = "serviceAccount"
+
 type AuthMiddleware struct {
-	secretKey []byte
-	Config    *AuthConfig
+	signerContract   contracts.TokenSignerContract
+	Config           *AuthConfig
+	denylistContract contracts.TokenDenylistContract
+	apiKeyContract   contracts.APIKeyContract
+	accountContract  contracts.ServiceAccountContract
 }
 
-func NewAuthMiddleware() *AuthMiddleware {
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	if secretKey == "" {
-		secretKey = "3xtr3m4d4m3nt3C0mpl3j0"
-	}
+func NewAuthMiddleware(signerContract contracts.TokenSignerContract, denylistContract contracts.TokenDenylistContract, apiKeyContract contracts.APIKeyContract, accountContract contracts.ServiceAccountContract) *AuthMiddleware {
 	return &AuthMiddleware{
-		secretKey: []byte(secretKey),
-		Config:    NewAuthConfig(),
+		signerContract:   signerContract,
+		Config:           NewAuthConfig(),
+		denylistContract: denylistContract,
+		apiKeyContract:   apiKeyContract,
+		accountContract:  accountContract,
 	}
 }
 
@@ -56,6 +64,11 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if key := c.GetHeader(models.APIKeyHeader); key != "" {
+			m.authenticateAPIKey(c, key)
+			return
+		}
+
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, authError)
@@ -65,35 +78,109 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
-			}
-			return m.secretKey, nil
-		})
+		verified, verifyErr := m.signerContract.Verify(tokenString)
+		if verifyErr != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+			c.Abort()
+			return
+		}
 
-		if err != nil || !token.Valid {
+		claims := jwt.MapClaims(verified)
+		jti, _ := claims["jti"].(string)
+		// tokens signed for another purpose, like the MFA challenge, are not access tokens
+		if _, scoped := claims["purpose"]; jti == "" || scoped {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
 			c.Abort()
 			return
 		}
+		if r, ok := m.denylistContract.(*repo.TokenDenylistRepository); ok {
+			r.WithContext(c.Request.Context())
+		}
+		revoked, sysErr := m.denylistContract.IsRevoked(jti)
+		if sysErr != nil || revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+			c.Abort()
+			return
+		}
+
+		data, _ := claims["data"].(map[string]interface{})
+		if data["must_change_password"] == true && !m.Config.AllowsPasswordChange(c.Request.Method, c.FullPath()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Debe cambiar su contraseña"})
+			c.Abort()
+			return
+		}
+		if data["mfa_setup_required"] == true && !m.Config.AllowsMFASetup(c.Request.Method, c.FullPath()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Debe configurar la verificación en dos pasos"})
+			c.Abort()
+			return
+		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("userID", claims["sub"])
-			c.Set("data", claims["data"])
+		c.Set("userID", claims["sub"])
+		c.Set("data", claims["data"])
+		c.Set("jti", jti)
+		if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+			c.Set("tokenExpiresAt", expiresAt.Time)
 		}
 
 		c.Next()
 	}
 }
 
+// authenticateAPIKey lets a service account in with the permissions of its key. Keys only reach routes
+// registered with PermissionMiddleware.Guard, the routes open to every user act on the personal data of the caller.
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, key string) {
+	if !m.Config.AllowsAPIKey(c.Request.Method, c.FullPath()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Ruta no disponible para claves de API"})
+		c.Abort()
+		return
+	}
+	if r, ok := m.apiKeyContract.(*repo.APIKeyRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := m.accountContract.(*repo.ServiceAccountRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+
+	useCase := apikeys.NewAuthenticateAPIKeyUseCase(m.apiKeyContract, m.accountContract, key)
+	if err := useCase.Validate(); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+	identity, err := useCase.Execute()
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Message})
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", identity.ServiceAccount.Actor())
+	c.Set(serviceAccountKey, identity.ServiceAccount.ID)
+	// RequirePermission finds the permissions already resolved
+	c.Set(permissionsKey, identity.Key.Grants())
+	c.Next()
+}
+
+// IsServiceAccount reports whether the request was authenticated with an API key
+func IsServiceAccount(c *gin.Context) bool {
+	_, ok := c.Get(serviceAccountKey)
+	return ok
+}
+
+// GenerateToken signs a short lived access token, its jti allows revoking it before it expires
 func (m *AuthMiddleware) GenerateToken(userID string, data map[string]interface{}) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	tokenString, err := m.signerContract.Sign(map[string]any{
 		"sub":  userID,
 		"data": data,
-		"exp":  jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+		"jti":  hex.EncodeToString(jti),
+		"iat":  jwt.NewNumericDate(now),
+		"exp":  jwt.NewNumericDate(now.Add(models.AccessTokenTTL)),
 	})
-	tokenString, err := token.SignedString(m.secretKey)
 	if err != nil {
 		return "", err
 	}
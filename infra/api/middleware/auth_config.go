@@ -7,6 +7,12 @@ import (
 
 type AuthConfig struct {
 	PublicRoutes []string
+	// Routes allowed to users whose password expired
+	PasswordChangeRoutes []string
+	// Routes allowed to users whose role requires MFA and have not enabled it
+	MFASetupRoutes []string
+	// Routes guarded by a permission, the only ones API keys reach. Filled by PermissionMiddleware.Guard
+	APIKeyRoutes []string
 	mu           sync.Mutex
 }
 
@@ -17,7 +23,54 @@ func NewAuthConfig() *AuthConfig {
 			"POST_/api/auth/login",
 			"POST_/api/auth/register",
 		},
+		PasswordChangeRoutes: []string{
+			"GET_/api/auth/me",
+			"POST_/api/auth/me/update",
+			"POST_/api/auth/logout",
+		},
+		MFASetupRoutes: []string{
+			"GET_/api/auth/me",
+			"POST_/api/auth/mfa/enroll",
+			"POST_/api/auth/mfa/activate",
+			"POST_/api/auth/logout",
+		},
+	}
+}
+
+// AllowsPasswordChange verifica si la ruta está permitida con la contraseña expirada
+func (ac *AuthConfig) AllowsPasswordChange(method, path string) bool {
+	return ac.allows(ac.PasswordChangeRoutes, method, path)
+}
+
+// AllowsMFASetup verifica si la ruta está permitida sin la verificación en dos pasos configurada
+func (ac *AuthConfig) AllowsMFASetup(method, path string) bool {
+	return ac.allows(ac.MFASetupRoutes, method, path)
+}
+
+// AllowsAPIKey verifica si la ruta está permitida con una clave de API
+func (ac *AuthConfig) AllowsAPIKey(method, path string) bool {
+	return ac.allows(ac.APIKeyRoutes, method, path)
+}
+
+// AddAPIKeyRoute agrega una ruta protegida por un permiso
+func (ac *AuthConfig) AddAPIKeyRoute(method, path string) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.APIKeyRoutes = append(ac.APIKeyRoutes, strings.ToUpper(method)+"_"+path)
+}
+
+func (ac *AuthConfig) allows(routes []string, method, path string) bool {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	route := strings.ToUpper(method) + "_" + path
+	for _, r := range routes {
+		if r == route {
+			return true
+		}
 	}
+	return false
 }
 
 // AddPublicRoute agrega una ruta pública
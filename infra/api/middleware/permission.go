@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	userUseCase "hrms.local/core/usecases/users"
+	"hrms.local/repository/postgress/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// permissionsKey stores the permissions resolved for the caller in the gin context
+const permissionsKey = "permissions"
+
+// PermissionMiddleware authorizes authenticated users against the permissions of their role
+type PermissionMiddleware struct {
+	userContract contracts.UserContract
+	roleContract contracts.RoleContract
+	config       *AuthConfig
+}
+
+func NewPermissionMiddleware(userContract contracts.UserContract, roleContract contracts.RoleContract, config *AuthConfig) *PermissionMiddleware {
+	return &PermissionMiddleware{
+		userContract: userContract,
+		roleContract: roleContract,
+		config:       config,
+	}
+}
+
+// GuardedRoutes registers routes of a group behind RequirePermission
+type GuardedRoutes struct {
+	permission *PermissionMiddleware
+	group      *gin.RouterGroup
+}
+
+// Guard registers routes of the group that require a permission. They are recorded in
+// AuthConfig.APIKeyRoutes, the routes registered without it are never reached with an API key.
+func (m *PermissionMiddleware) Guard(group *gin.RouterGroup) *GuardedRoutes {
+	return &GuardedRoutes{permission: m, group: group}
+}
+
+func (g *GuardedRoutes) GET(relativePath string, permission string, handlers ...gin.HandlerFunc) {
+	g.handle(http.MethodGet, relativePath, permission, handlers)
+}
+
+func (g *GuardedRoutes) POST(relativePath string, permission string, handlers ...gin.HandlerFunc) {
+	g.handle(http.MethodPost, relativePath, permission, handlers)
+}
+
+func (g *GuardedRoutes) handle(method string, relativePath string, permission string, handlers []gin.HandlerFunc) {
+	g.group.Handle(method, relativePath, append([]gin.HandlerFunc{g.permission.RequirePermission(permission)}, handlers...)...)
+	// the full path as gin joins it, c.FullPath() answers the same
+	fullPath := path.Join(g.group.BasePath(), relativePath)
+	if len(relativePath) > 0 && relativePath[len(relativePath)-1] == '/' && fullPath[len(fullPath)-1] != '/' {
+		fullPath += "/"
+	}
+	g.permission.config.AddAPIKeyRoute(method, fullPath)
+}
+
+// RequirePermission must run after AuthMiddleware, it rejects users missing any of the permissions.
+// Routes needing a single permission are registered with Guard instead, so API keys can reach them.
+// The permissions of the caller are resolved once per request and shared by every check of the chain.
+func (m *PermissionMiddleware) RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, err := m.resolve(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Message})
+			return
+		}
+		if !models.HasPermission(granted, permissions...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permiso denegado"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAnyPermission is RequirePermission for handlers that serve callers holding any one of the
+// permissions, the handler narrows what it answers to the permissions of the caller
+func (m *PermissionMiddleware) RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, err := m.resolve(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Message})
+			return
+		}
+		for _, permission := range permissions {
+			if models.HasPermission(granted, permission) {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permiso denegado"})
+	}
+}
+
+// Permissions returns the permissions resolved for the caller by RequirePermission or RequireAnyPermission
+func Permissions(c *gin.Context) []models.Permission {
+	if cached, ok := c.Get(permissionsKey); ok {
+		return cached.([]models.Permission)
+	}
+	return nil
+}
+
+// Has reports whether the caller holds every permission, for handlers open to every user that
+// let some callers act on the records of others
+func (m *PermissionMiddleware) Has(c *gin.Context, permissions ...string) bool {
+	granted, err := m.resolve(c)
+	if err != nil {
+		return false
+	}
+	return models.HasPermission(granted, permissions...)
+}
+
+// resolve returns the permissions of the caller, loading them from its role the first time
+func (m *PermissionMiddleware) resolve(c *gin.Context) ([]models.Permission, *models.SystemError) {
+	if cached, ok := c.Get(permissionsKey); ok {
+		return cached.([]models.Permission), nil
+	}
+	if r, ok := m.userContract.(*repo.UserRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+	if r, ok := m.roleContract.(*repo.RoleRepository); ok {
+		r.WithContext(c.Request.Context())
+	}
+
+	useCase := userUseCase.NewGetUserPermissionsUseCase(m.userContract, m.roleContract, c.GetString("userID"))
+	if err := useCase.Validate(); err != nil {
+		return nil, err
+	}
+	granted, err := useCase.Execute()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(permissionsKey, granted)
+	return granted, nil
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter throttles requests per client IP with a fixed window kept in memory
+type RateLimiter struct {
+	limit     int
+	window    time.Duration
+	mu        sync.Mutex
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: map[string]*rateWindow{},
+	}
+}
+
+// Allow counts a request of the key and returns how long to wait when the limit is exceeded
+func (rl *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) >= rl.window {
+		for k, w := range rl.clients {
+			if now.Sub(w.start) >= rl.window {
+				delete(rl.clients, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	w, ok := rl.clients[key]
+	if !ok || now.Sub(w.start) >= rl.window {
+		rl.clients[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+	if w.count >= rl.limit {
+		return false, w.start.Add(rl.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// Limit rejects the requests of a client IP over the limit with 429
+func (rl *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, retryAfter := rl.Allow(c.ClientIP(), time.Now())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Demasiados intentos, intente más tarde"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
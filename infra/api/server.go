@@ -7,15 +7,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
+	"hrms.local/core/usecases/payroll"
+	"hrms.local/export"
 	"hrms.local/infra/api/config"
 	"hrms.local/infra/api/controller"
 	"hrms.local/infra/api/middleware"
 	BaseController "hrms.local/infra/api/types"
+	"hrms.local/mail"
 	"hrms.local/security"
 
 	"hrms.local/repository/postgress"
@@ -27,27 +31,61 @@ type Server struct {
 	router         *gin.Engine
 	appController  []BaseController.Controller
 	authMiddleware *middleware.AuthMiddleware
+	permission     *middleware.PermissionMiddleware
 	config         *config.Config
 	context        struct {
 		userContract       contracts.UserContract
 		departmentContract contracts.DepartmentContract
 		roleContract       contracts.RoleContract
 		permissionContract contracts.PermissionContract
+		employeeContract   contracts.EmployeeContract
+		positionContract   contracts.PositionContract
+		attendanceContract contracts.AttendanceContract
+		scheduleContract   contracts.WorkScheduleContract
+		leaveTypeContract  contracts.LeaveTypeContract
+		leaveContract      contracts.LeaveRequestContract
+		periodContract     contracts.PayPeriodContract
+		payrollContract    contracts.PayrollRunContract
+		payslipContract    contracts.PayslipContract
+		refreshContract    contracts.RefreshTokenContract
+		denylistContract   contracts.TokenDenylistContract
+		auditContract      contracts.AuditContract
+		historyContract    contracts.PasswordHistoryContract
+		recoveryContract   contracts.RecoveryCodeContract
+		tokenContract      contracts.AccountTokenContract
+		invitationContract contracts.InvitationContract
+		accountContract    contracts.ServiceAccountContract
+		apiKeyContract     contracts.APIKeyContract
+		searchContract     contracts.SearchContract
 	}
+	payrollRules        []contracts.PayrollRuleContract
+	exportContext       contracts.PayrollExportContract
 	cryptographyContext contracts.CryptographyContract
+	signerContext       contracts.TokenSignerContract
+	passwordPolicy      contracts.PasswordPolicyContract
+	otpContext          contracts.OTPContract
+	mailerContext       contracts.MailerContract
+	mailTemplates       contracts.MailTemplateContract
+	oidcContext         contracts.OIDCProviderContract
+	oidcSettings        models.OIDCSettings
+	oidcMock            http.Handler
 }
 
 func NewServer(cfg *config.Config) *Server {
 
 	server := &Server{
-		router:         gin.New(),
-		appController:  []BaseController.Controller{},
-		authMiddleware: middleware.NewAuthMiddleware(),
-		config:         cfg,
+		router:        gin.New(),
+		appController: []BaseController.Controller{},
+		config:        cfg,
 	}
 
 	server.SetupHeaders()
 	server.SetupContext()
+	server.SetupSigner()
+	server.SetupMailer()
+	server.SetupOIDC()
+	server.authMiddleware = middleware.NewAuthMiddleware(server.signerContext, server.context.denylistContract, server.context.apiKeyContract, server.context.accountContract)
+	server.permission = middleware.NewPermissionMiddleware(server.context.userContract, server.context.roleContract, server.authMiddleware.Config)
 	server.SetupControllers()
 
 	return server
@@ -59,7 +97,7 @@ func (s *Server) SetupHeaders() {
 		c.Header("Content-Type", "application/json")
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-API-Key")
 		// c.Header("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -70,9 +108,20 @@ func (s *Server) SetupHeaders() {
 }
 
 func (s *Server) SetupControllers() {
+	if !s.config.Registration.Mode.IsValid() {
+		log.Fatal("Unknown REGISTRATION_MODE ", s.config.Registration.Mode, ", use disabled, invite or open")
+	}
 	s.appController = []BaseController.Controller{
-		controller.NewUserController(s.authMiddleware, s.context.userContract, s.cryptographyContext),
-		controller.NewRoleController(s.authMiddleware, s.context.roleContract, s.context.permissionContract),
+		controller.NewUserController(s.authMiddleware, s.permission, middleware.NewRateLimiter(s.config.LoginRateLimit, time.Minute), s.context.userContract, s.context.roleContract, s.context.refreshContract, s.context.denylistContract, s.context.auditContract, s.context.historyContract, s.passwordPolicy, s.signerContext, s.otpContext, s.context.recoveryContract, s.context.tokenContract, s.context.invitationContract, s.mailerContext, s.mailTemplates, s.config.AppURL, s.config.Registration, s.oidcContext, s.oidcSettings, s.cryptographyContext),
+		controller.NewServiceAccountController(s.authMiddleware, s.permission, s.context.accountContract, s.context.apiKeyContract, s.context.permissionContract, s.context.auditContract),
+		controller.NewRoleController(s.authMiddleware, s.permission, s.context.roleContract, s.context.permissionContract, s.context.userContract),
+		controller.NewEmployeeController(s.authMiddleware, s.permission, s.context.employeeContract, s.context.userContract, s.context.departmentContract, s.context.positionContract, s.context.auditContract, s.context.refreshContract),
+		controller.NewDepartmentController(s.authMiddleware, s.permission, s.context.departmentContract, s.context.employeeContract, s.context.positionContract),
+		controller.NewPositionController(s.authMiddleware, s.permission, s.context.positionContract, s.context.departmentContract, s.context.employeeContract),
+		controller.NewAttendanceController(s.authMiddleware, s.permission, s.context.attendanceContract, s.context.scheduleContract, s.context.employeeContract, s.context.departmentContract, s.context.userContract),
+		controller.NewLeaveController(s.authMiddleware, s.permission, s.context.leaveContract, s.context.leaveTypeContract, s.context.employeeContract, s.context.departmentContract, s.context.scheduleContract, s.context.userContract),
+		controller.NewPayrollController(s.authMiddleware, s.permission, s.context.periodContract, s.context.payrollContract, s.context.payslipContract, s.context.employeeContract, s.context.positionContract, s.context.attendanceContract, s.context.scheduleContract, s.context.departmentContract, s.context.userContract, s.exportContext, s.payrollRules, s.config.CompanyName),
+		controller.NewSearchController(s.authMiddleware, s.permission, s.context.searchContract),
 	}
 }
 
@@ -86,7 +135,143 @@ func (s *Server) SetupContext() {
 	s.context.departmentContract = context.DepartmentContract
 	s.context.roleContract = context.RoleContract
 	s.context.permissionContract = context.PermissionContract
+	s.context.employeeContract = context.EmployeeContract
+	s.context.positionContract = context.PositionContract
+	s.context.attendanceContract = context.AttendanceContract
+	s.context.scheduleContract = context.ScheduleContract
+	s.context.leaveTypeContract = context.LeaveTypeContract
+	s.context.leaveContract = context.LeaveContract
+	s.context.periodContract = context.PayPeriodContract
+	s.context.payrollContract = context.PayrollContract
+	s.context.payslipContract = context.PayslipContract
+	s.context.refreshContract = context.RefreshContract
+	s.context.denylistContract = context.DenylistContract
+	s.context.auditContract = context.AuditContract
+	s.context.historyContract = context.HistoryContract
+	s.context.recoveryContract = context.RecoveryContract
+	s.context.tokenContract = context.TokenContract
+	s.context.invitationContract = context.InvitationContract
+	s.context.accountContract = context.AccountContract
+	s.context.apiKeyContract = context.APIKeyContract
+	s.context.searchContract = context.SearchContract
+	s.payrollRules = payroll.DefaultRules()
+	s.exportContext = export.NewPayrollExporter()
 	s.cryptographyContext = security.NewSecurityImpl()
+	s.otpContext = security.NewTOTP(s.config.CompanyName)
+	passwordPolicy, policyErr := security.NewPasswordPolicy(s.config.PasswordPolicy, s.config.PasswordBreachedList)
+	if policyErr != nil {
+		log.Fatal("Failed to load password policy: ", policyErr.Message)
+	}
+	s.passwordPolicy = passwordPolicy
+}
+
+// SetupSigner loads the token signing keys. Production refuses to start without a configured key,
+// development falls back to a throwaway Ed25519 key so tokens do not survive a restart.
+func (s *Server) SetupSigner() {
+	var keys []security.SigningKey
+	switch {
+	case s.config.JWTKeysDir != "":
+		loaded, err := security.LoadSigningKeys(s.config.JWTKeysDir)
+		if err != nil {
+			log.Fatal("Failed to load signing keys: ", err.Message)
+		}
+		keys = loaded
+	case s.config.JWTSecret != "":
+		keys = []security.SigningKey{security.NewHMACSigningKey("hs256", []byte(s.config.JWTSecret))}
+	case s.config.IsProduction():
+		log.Fatal("No token signing key configured, set JWT_KEYS_DIR or JWT_SECRET")
+	default:
+		log.Println("Warning: no token signing key configured, using an ephemeral development key")
+		key, err := security.GenerateSigningKey("development")
+		if err != nil {
+			log.Fatal("Failed to generate signing key: ", err.Message)
+		}
+		keys = []security.SigningKey{key}
+	}
+	signer, err := security.NewTokenSigner(s.config.JWTActiveKeyID, keys...)
+	if err != nil {
+		log.Fatal("Failed to configure token signer: ", err.Message)
+	}
+	s.signerContext = signer
+}
+
+// SetupMailer selects the mail delivery. Production only sends through SMTP,
+// the file and memory drivers would silently lose the reset and verification links.
+func (s *Server) SetupMailer() {
+	switch s.config.MailDriver {
+	case "smtp":
+		s.mailerContext = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     s.config.SMTPHost,
+			Port:     s.config.SMTPPort,
+			Username: s.config.SMTPUsername,
+			Password: s.config.SMTPPassword,
+			From:     s.config.MailFrom,
+		})
+	case "file", "memory":
+		if s.config.IsProduction() {
+			log.Fatal("MAIL_DRIVER must be smtp in production")
+		}
+		if s.config.MailDriver == "memory" {
+			s.mailerContext = mail.NewMemoryMailer()
+			break
+		}
+		mailer, err := mail.NewFileMailer(s.config.MailDir, s.config.MailFrom)
+		if err != nil {
+			log.Fatal("Failed to configure mailer: ", err.Message)
+		}
+		s.mailerContext = mailer
+	default:
+		log.Fatal("Unknown MAIL_DRIVER ", s.config.MailDriver, ", use smtp, file or memory")
+	}
+	templates, err := mail.NewTemplates(s.config.CompanyName)
+	if err != nil {
+		log.Fatal("Failed to load mail templates: ", err.Message)
+	}
+	s.mailTemplates = templates
+}
+
+// SetupOIDC enables single sign-on when an issuer is configured. OIDC_MOCK serves a development issuer
+// at /mock-oidc signing in any email, the API reaches it through its own port.
+func (s *Server) SetupOIDC() {
+	if s.config.OIDCMock {
+		if s.config.IsProduction() {
+			log.Fatal("OIDC_MOCK is not allowed in production")
+		}
+		if s.config.OIDCIssuerURL == "" {
+			s.config.OIDCIssuerURL = "http://localhost:" + s.config.ServerPort + "/mock-oidc"
+		}
+		if s.config.OIDCClientID == "" {
+			s.config.OIDCClientID = "hrms"
+		}
+		mock, err := security.NewMockIssuer(s.config.OIDCIssuerURL, s.config.OIDCClientID, s.config.OIDCClientSecret)
+		if err != nil {
+			log.Fatal("Failed to start the mock OpenID Connect issuer: ", err.Message)
+		}
+		s.oidcMock = mock
+	}
+	if s.config.OIDCIssuerURL == "" {
+		return
+	}
+	if s.config.OIDCClientID == "" {
+		log.Fatal("OIDC_CLIENT_ID is required with OIDC_ISSUER_URL")
+	}
+	groupRoles, err := models.ParseOIDCGroupRoles(s.config.OIDCGroupRoles)
+	if err != nil {
+		log.Fatal("Invalid OIDC_GROUP_ROLES: ", err.Message)
+	}
+	s.oidcSettings = models.OIDCSettings{
+		AutoProvision: s.config.OIDCAutoProvision,
+		DefaultRole:   s.config.OIDCDefaultRole,
+		GroupRoles:    groupRoles,
+	}
+	s.oidcContext = security.NewOIDCProvider(security.OIDCConfig{
+		IssuerURL:    s.config.OIDCIssuerURL,
+		ClientID:     s.config.OIDCClientID,
+		ClientSecret: s.config.OIDCClientSecret,
+		RedirectURL:  s.config.OIDCRedirectURL,
+		Scopes:       strings.Fields(s.config.OIDCScopes),
+		GroupsClaim:  s.config.OIDCGroupsClaim,
+	})
 }
 
 func (s *Server) StartServer() {
@@ -95,11 +280,21 @@ func (s *Server) StartServer() {
 		controller.RegisterRoutes(s.router.Group("/api"))
 	}
 
+	if s.oidcMock != nil {
+		s.router.Any("/mock-oidc/*path", gin.WrapH(s.oidcMock))
+	}
+
 	// Register health check
 	s.router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Public keys other services use to verify our access tokens
+	s.router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, s.signerContext.PublicKeys())
+	})
+
 	srv := &http.Server{
 		Addr:           ":" + s.config.ServerPort,
 		Handler:        s.router,
@@ -1,16 +1,48 @@
+export type FilterOperator =
+  | 'eq'
+  | 'neq'
+  | 'lt'
+  | 'lte'
+  | 'gt'
+  | 'gte'
+  | 'in'
+  | 'not_in'
+  | 'like'
+  | 'ilike'
+  | 'between'
+  | 'is_null';
+
 export interface Filter {
   key: string;
+  operator?: FilterOperator;
   value: any;
 }
 
 export type Filters = Filter[];
 
+export interface FilterGroup {
+  logic?: 'and' | 'or';
+  filters: Filters;
+  groups?: FilterGroup[];
+}
+
+export interface Sort {
+  field: string;
+  direction?: 'asc' | 'desc';
+  nulls?: 'first' | 'last';
+}
+
 export interface Pagination {
   page: number;
   limit: number;
+  mode?: 'offset' | 'cursor';
+  cursor?: string;
+  count?: 'exact' | 'estimate' | 'none';
 }
 
 export interface SearchQuery {
   filters: Filters;
+  groups?: FilterGroup[];
+  sort?: Sort[];
   pagination: Pagination;
 }
@@ -1,5 +1,8 @@
 export interface PaginatedResponse<T> {
     total_rows: number;
     total_pages: number;
+    total_estimated?: boolean;
+    next_cursor?: string;
+    prev_cursor?: string;
     rows: T[];
 }
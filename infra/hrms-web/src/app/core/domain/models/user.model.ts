@@ -44,7 +44,9 @@ export interface UserData {
   type: UserType;
   token: string;
   picture?: string;
+  roleId?: string;
   role?: string;
+  permissions?: string[];
 }
 
 export interface LoginUser {
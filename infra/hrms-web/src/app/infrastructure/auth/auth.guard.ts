@@ -6,7 +6,13 @@ export const authGuard: CanActivateFn = (route, state) => {
   const authService = inject(AuthService);
   const router = inject(Router);
   if (authService.isAuthenticated()) {
-    return true;
+    // Routes may declare the permissions they need in data.permissions
+    const required: string[] = route.data?.['permissions'] ?? [];
+    if (authService.hasPermission(...required)) {
+      return true;
+    }
+    router.navigate(['/']);
+    return false;
   }
 
   // Redirect to login page if not authenticated
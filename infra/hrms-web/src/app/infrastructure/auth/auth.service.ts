@@ -21,6 +21,19 @@ export class AuthService {
     this._currentUser.set(user);
   }
 
+  // Merges the profile returned by /api/auth/me keeping the session token
+  setProfile(profile: UserData): void {
+    const current = this._currentUser();
+    if (!current) return;
+    this.setAuth({ ...current, ...profile, token: current.token });
+  }
+
+  hasPermission(...required: string[]): boolean {
+    const granted = this._currentUser()?.permissions ?? [];
+    if (granted.includes('all_access')) return true;
+    return required.every(permission => granted.includes(permission));
+  }
+
   logout(): void {
     localStorage.removeItem(this.AUTH_KEY);
     this._currentUser.set(null);
@@ -26,7 +26,7 @@ export class UserApiRepository implements UserRepository {
 
   async createUser(user: CreateUser): Promise<UserData> {
     const dto = await firstValueFrom(
-      this.http.post<UserData>(`${environment.apiUrl}/auth/create`, user)
+      this.http.post<UserData>(`${environment.apiUrl}/auth/users/create`, user)
     );
     return dto;
   }
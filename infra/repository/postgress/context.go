@@ -20,6 +20,25 @@ type Context struct {
 	DepartmentContract contracts.DepartmentContract
 	RoleContract       contracts.RoleContract
 	PermissionContract contracts.PermissionContract
+	EmployeeContract   contracts.EmployeeContract
+	PositionContract   contracts.PositionContract
+	AttendanceContract contracts.AttendanceContract
+	ScheduleContract   contracts.WorkScheduleContract
+	LeaveTypeContract  contracts.LeaveTypeContract
+	LeaveContract      contracts.LeaveRequestContract
+	PayPeriodContract  contracts.PayPeriodContract
+	PayrollContract    contracts.PayrollRunContract
+	PayslipContract    contracts.PayslipContract
+	RefreshContract    contracts.RefreshTokenContract
+	DenylistContract   contracts.TokenDenylistContract
+	AuditContract      contracts.AuditContract
+	HistoryContract    contracts.PasswordHistoryContract
+	RecoveryContract   contracts.RecoveryCodeContract
+	TokenContract      contracts.AccountTokenContract
+	InvitationContract contracts.InvitationContract
+	AccountContract    contracts.ServiceAccountContract
+	APIKeyContract     contracts.APIKeyContract
+	SearchContract     contracts.SearchContract
 }
 
 func NewContext(dns string) (*Context, models.SystemError) {
@@ -41,6 +60,25 @@ func NewContext(dns string) (*Context, models.SystemError) {
 		DepartmentContract: repo.NewDepartmentRepository(db),
 		RoleContract:       repo.NewRoleRepository(db),
 		PermissionContract: repo.NewPermissionRepository(db),
+		EmployeeContract:   repo.NewEmployeeRepository(db),
+		PositionContract:   repo.NewPositionRepository(db),
+		AttendanceContract: repo.NewAttendanceRepository(db),
+		ScheduleContract:   repo.NewWorkScheduleRepository(db),
+		LeaveTypeContract:  repo.NewLeaveTypeRepository(db),
+		LeaveContract:      repo.NewLeaveRequestRepository(db),
+		PayPeriodContract:  repo.NewPayPeriodRepository(db),
+		PayrollContract:    repo.NewPayrollRunRepository(db),
+		PayslipContract:    repo.NewPayslipRepository(db),
+		RefreshContract:    repo.NewRefreshTokenRepository(db),
+		DenylistContract:   repo.NewTokenDenylistRepository(db),
+		AuditContract:      repo.NewAuditRepository(db),
+		HistoryContract:    repo.NewPasswordHistoryRepository(db),
+		RecoveryContract:   repo.NewRecoveryCodeRepository(db),
+		TokenContract:      repo.NewAccountTokenRepository(db),
+		InvitationContract: repo.NewInvitationRepository(db),
+		AccountContract:    repo.NewServiceAccountRepository(db),
+		APIKeyContract:     repo.NewAPIKeyRepository(db),
+		SearchContract:     repo.NewSearchRepository(db),
 	}, models.SystemError{}
 }
 
@@ -55,11 +93,38 @@ func migrate(db *gorm.DB) models.SystemError {
 		}
 	}
 
+	if err := db.SetupJoinTable(&gormModels.RoleGorm{}, "Permissions", &gormModels.RolePermissionGorm{}); err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to setup role permissions",
+		}
+	}
 	if err := db.AutoMigrate(
 		&repo.UserGorm{},
 		&repo.DepartmentGorm{},
 		&gormModels.RoleGorm{},
 		&gormModels.PermissionGorm{},
+		&gormModels.RolePermissionGorm{},
+		&repo.PositionGorm{},
+		&repo.EmployeeGorm{},
+		&repo.AttendanceEventGorm{},
+		&repo.WorkScheduleGorm{},
+		&repo.LeaveTypeGorm{},
+		&repo.LeaveRequestGorm{},
+		&repo.PayPeriodGorm{},
+		&repo.PayrollRunGorm{},
+		&repo.PayslipGorm{},
+		&repo.RefreshTokenGorm{},
+		&repo.RevokedTokenGorm{},
+		&repo.AuditEntryGorm{},
+		&repo.PasswordHistoryGorm{},
+		&repo.RecoveryCodeGorm{},
+		&repo.AccountTokenGorm{},
+		&repo.InvitationGorm{},
+		&repo.ServiceAccountGorm{},
+		&repo.APIKeyGorm{},
 	); err != nil {
 		return models.SystemError{
 			Code:    models.SystemErrorCodeMigration,
@@ -68,16 +133,51 @@ func migrate(db *gorm.DB) models.SystemError {
 			Message: "Failed to migrate database",
 		}
 	}
-	if err := defaultUsers(db); err.Code != models.SystemErrorCodeNone {
+	if err := repo.MigrateSearch(db); err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to migrate search indexes",
+		}
+	}
+	if err := migrateRolePermissions(db); err.Code != models.SystemErrorCodeNone {
 		return err
 	}
 	if err := defaultPermissions(db); err.Code != models.SystemErrorCodeNone {
 		return err
 	}
+	if err := defaultRoles(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
+	if err := migrateUserRoles(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
+	if err := defaultUsers(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
+	if err := migratePasswordChangedAt(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
+	if err := defaultWorkSchedule(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
+	if err := defaultLeaveTypes(db); err.Code != models.SystemErrorCodeNone {
+		return err
+	}
 	return models.SystemError{}
 }
 
 func defaultUsers(db *gorm.DB) models.SystemError {
+	var userRole gormModels.RoleGorm
+	if err := db.Where("name = ?", defaultUserRoleName).First(&userRole).Error; err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Default User role not found",
+		}
+	}
 	var gormModels []repo.UserGorm
 	for i := range 100 {
 		user := repo.UserGorm{
@@ -93,7 +193,7 @@ func defaultUsers(db *gorm.DB) models.SystemError {
 			UpdatedAt: time.Now(),
 			// DeletedAt: nil,
 			Active: false,
-			Role:   "user",
+			RoleID: &userRole.ID,
 		}
 		query := db.Where("email = ?", user.Email)
 		query.Find(&gormModels)
@@ -113,6 +213,94 @@ func defaultUsers(db *gorm.DB) models.SystemError {
 	return models.SystemError{}
 }
 
+// defaultUserRoleName is the role of regular users, it grants no permission until one is assigned
+const defaultUserRoleName = "User"
+
+func defaultRoles(db *gorm.DB) models.SystemError {
+	var userRole gormModels.RoleGorm
+	if err := db.Where("name = ?", defaultUserRoleName).First(&userRole).Error; err == nil {
+		return models.SystemError{}
+	}
+	userRole = gormModels.RoleGorm{
+		ID:          uuid.New(),
+		Name:        defaultUserRoleName,
+		Description: "Default role for regular users",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := db.Create(&userRole).Error; err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to create default User role",
+		}
+	}
+	return models.SystemError{}
+}
+
+// migrateUserRoles converts the free text role column of older databases into the role_id foreign key.
+// Values are matched against the role names, ignoring case, or ids; users with an unknown role are left without role.
+func migrateUserRoles(db *gorm.DB) models.SystemError {
+	if !db.Migrator().HasColumn("users", "role") {
+		return models.SystemError{}
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE users SET role_id = roles.id FROM roles
+			WHERE users.role_id IS NULL AND roles.deleted_at IS NULL
+			AND (LOWER(roles.name) = LOWER(users.role) OR roles.id::text = users.role)`).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn("users", "role")
+	})
+	if err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to migrate user roles",
+		}
+	}
+	return models.SystemError{}
+}
+
+// migratePasswordChangedAt starts the password expiry of users created before it was tracked
+func migratePasswordChangedAt(db *gorm.DB) models.SystemError {
+	if err := db.Exec("UPDATE users SET password_changed_at = NOW() WHERE password_changed_at IS NULL").Error; err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to migrate password expiry",
+		}
+	}
+	return models.SystemError{}
+}
+
+// migrateRolePermissions moves the owning role of every permission of older databases into role_permissions
+func migrateRolePermissions(db *gorm.DB) models.SystemError {
+	if !db.Migrator().HasColumn("permissions", "role_id") {
+		return models.SystemError{}
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO role_permissions (role_id, permission_id, created_at)
+			SELECT role_id, id, NOW() FROM permissions WHERE role_id IS NOT NULL
+			ON CONFLICT DO NOTHING`).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn("permissions", "role_id")
+	})
+	if err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to migrate role permissions",
+		}
+	}
+	return models.SystemError{}
+}
+
 func defaultPermissions(db *gorm.DB) models.SystemError {
 	// Create default Admin role if not exists
 	var adminRole gormModels.RoleGorm
@@ -140,16 +328,23 @@ func defaultPermissions(db *gorm.DB) models.SystemError {
 		{Name: models.PermissionEditEmployees, Description: "Edit employees"},
 		{Name: models.PermissionViewEmployees, Description: "View employees"},
 		{Name: models.PermissionViewMenuDepartments, Description: "View the departments menu"},
+		{Name: models.PermissionEditDepartments, Description: "Create, edit and delete departments"},
 		{Name: models.PermissionViewMenuPosition, Description: "View the position menu"},
+		{Name: models.PermissionEditPositions, Description: "Create, edit and delete positions"},
 		{Name: models.PermissionViewMenuAttendance, Description: "View the attendance menu"},
+		{Name: models.PermissionManageAttendance, Description: "Clock other employees and manage work schedules"},
 		{Name: models.PermissionViewMenuPayroll, Description: "View the payroll menu"},
+		{Name: models.PermissionManagePayroll, Description: "Run the payroll and change the bank accounts of employees"},
 		{Name: models.PermissionViewMenuLeaveRequests, Description: "View the leave requests menu"},
+		{Name: models.PermissionManageLeaves, Description: "Submit leave for other employees and manage leave types"},
 		{Name: models.PermissionViewMenuSettings, Description: "View the settings menu"},
 		{Name: models.PermissionAllAccess, Description: "Full access to settings"},
 		{Name: models.PermissionViewRoles, Description: "View roles"},
 		{Name: models.PermissionEditRoles, Description: "Edit roles"},
 		{Name: models.PermissionEditUsers, Description: "Edit users"},
 		{Name: models.PermissionViewUsers, Description: "View users"},
+		{Name: models.PermissionExportPayroll, Description: "Download payslips and bank files"},
+		{Name: models.PermissionManageAPIKeys, Description: "Manage service accounts and their API keys"},
 	}
 
 	for _, p := range permissions {
@@ -162,8 +357,8 @@ func defaultPermissions(db *gorm.DB) models.SystemError {
 		if permGorm.ID == uuid.Nil {
 			permGorm.ID = uuid.New()
 		}
-		permGorm.RoleID = adminRole.ID
 
+		// new permissions are granted to Admin, the other roles get them explicitly
 		if err := db.Create(&permGorm).Error; err != nil {
 			return models.SystemError{
 				Code:    models.SystemErrorCodeMigration,
@@ -172,6 +367,68 @@ func defaultPermissions(db *gorm.DB) models.SystemError {
 				Message: "Failed to create default permission: " + p.Name,
 			}
 		}
+		if err := db.Model(&adminRole).Association("Permissions").Append(&permGorm); err != nil {
+			return models.SystemError{
+				Code:    models.SystemErrorCodeMigration,
+				Type:    models.SystemErrorTypeValidation,
+				Level:   models.SystemErrorLevelError,
+				Message: "Failed to grant default permission: " + p.Name,
+			}
+		}
+	}
+	return models.SystemError{}
+}
+
+func defaultWorkSchedule(db *gorm.DB) models.SystemError {
+	var count int64
+	if err := db.Model(&repo.WorkScheduleGorm{}).Where("is_default = ? OR name = ?", true, "Standard").Count(&count).Error; err == nil && count > 0 {
+		return models.SystemError{}
+	}
+
+	schedule := repo.WorkScheduleToEntity(models.WorkSchedule{
+		Name:         "Standard",
+		StartTime:    "09:00",
+		EndTime:      "18:00",
+		BreakMinutes: 60,
+		GraceMinutes: 10,
+		WorkDays:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Timezone:     "UTC",
+		IsDefault:    true,
+	})
+	schedule.ID = uuid.New()
+	if err := db.Create(&schedule).Error; err != nil {
+		return models.SystemError{
+			Code:    models.SystemErrorCodeMigration,
+			Type:    models.SystemErrorTypeValidation,
+			Level:   models.SystemErrorLevelError,
+			Message: "Failed to create default work schedule",
+		}
+	}
+	return models.SystemError{}
+}
+
+func defaultLeaveTypes(db *gorm.DB) models.SystemError {
+	leaveTypes := []models.LeaveType{
+		{Code: "VAC", Name: "Vacation", Category: models.LeaveCategoryVacation, Paid: true, AccrualFrequency: models.AccrualFrequencyMonthly, AccrualDays: 1.25, MaxBalance: 15, Active: true},
+		{Code: "SICK", Name: "Sick leave", Category: models.LeaveCategorySick, Paid: true, AccrualFrequency: models.AccrualFrequencyYearly, AccrualDays: 12, Active: true},
+		{Code: "UNPAID", Name: "Unpaid leave", Category: models.LeaveCategoryUnpaid, AccrualFrequency: models.AccrualFrequencyNone, Active: true},
+	}
+
+	for _, leaveType := range leaveTypes {
+		var count int64
+		if err := db.Model(&repo.LeaveTypeGorm{}).Where("code = ?", leaveType.Code).Count(&count).Error; err == nil && count > 0 {
+			continue
+		}
+		entity := repo.LeaveTypeToEntity(leaveType)
+		entity.ID = uuid.New()
+		if err := db.Create(&entity).Error; err != nil {
+			return models.SystemError{
+				Code:    models.SystemErrorCodeMigration,
+				Type:    models.SystemErrorTypeValidation,
+				Level:   models.SystemErrorLevelError,
+				Message: "Failed to create default leave type: " + leaveType.Code,
+			}
+		}
 	}
 	return models.SystemError{}
 }
@@ -13,7 +13,8 @@ type RoleGorm struct {
 	ID          uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	Name        string           `gorm:"type:varchar(255);unique;not null"`
 	Description string           `gorm:"type:text"`
-	Permissions []PermissionGorm `gorm:"foreignKey:RoleID"`
+	Permissions []PermissionGorm `gorm:"many2many:role_permissions;joinForeignKey:RoleID;joinReferences:PermissionID"`
+	RequireMFA  bool             `gorm:"not null;default:false"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
@@ -23,6 +24,19 @@ func (RoleGorm) TableName() string {
 	return "roles"
 }
 
+// RolePermissionGorm links roles and permissions, a permission can be granted by any number of roles
+type RolePermissionGorm struct {
+	RoleID       uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	Role         *RoleGorm       `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PermissionID uuid.UUID       `gorm:"type:uuid;primaryKey;index"`
+	Permission   *PermissionGorm `gorm:"foreignKey:PermissionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt    time.Time
+}
+
+func (RolePermissionGorm) TableName() string {
+	return "role_permissions"
+}
+
 func (r RoleGorm) ToModel() models.Role {
 	permissions := make([]models.Permission, len(r.Permissions))
 	for i, p := range r.Permissions {
@@ -34,6 +48,7 @@ func (r RoleGorm) ToModel() models.Role {
 		Name:        r.Name,
 		Description: r.Description,
 		Permissions: permissions,
+		RequireMFA:  r.RequireMFA,
 	}
 }
 
@@ -50,5 +65,6 @@ func RoleToEntity(r models.Role) RoleGorm {
 		Name:        r.Name,
 		Description: r.Description,
 		Permissions: permissions,
+		RequireMFA:  r.RequireMFA,
 	}
 }
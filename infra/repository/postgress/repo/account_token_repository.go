@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccountTokenGorm struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	User      *UserGorm  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Purpose   string     `gorm:"type:varchar(32);not null"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Email     string     `gorm:"type:varchar(255)"`
+	ExpiresAt time.Time  `gorm:"type:timestamptz;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time
+}
+
+func (AccountTokenGorm) TableName() string {
+	return "account_tokens"
+}
+
+func (a AccountTokenGorm) ToModel() models.AccountToken {
+	return models.AccountToken{
+		ID:        fromGUIDToString(a.ID),
+		UserID:    fromGUIDToString(a.UserID),
+		Purpose:   models.AccountTokenPurpose(a.Purpose),
+		TokenHash: a.TokenHash,
+		Email:     a.Email,
+		ExpiresAt: a.ExpiresAt,
+		UsedAt:    a.UsedAt,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func AccountTokenToEntity(a models.AccountToken) AccountTokenGorm {
+	id, _ := uuid.Parse(a.ID)
+	userID, _ := uuid.Parse(a.UserID)
+	return AccountTokenGorm{
+		ID:        id,
+		UserID:    userID,
+		Purpose:   string(a.Purpose),
+		TokenHash: a.TokenHash,
+		Email:     a.Email,
+		ExpiresAt: a.ExpiresAt,
+		UsedAt:    a.UsedAt,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+type AccountTokenRepository struct {
+	GenericCrud[models.AccountToken, AccountTokenGorm]
+	db *gorm.DB
+}
+
+func NewAccountTokenRepository(db *gorm.DB) contracts.AccountTokenContract {
+	return &AccountTokenRepository{
+		GenericCrud: NewGenericCrud(db, AccountTokenToEntity, (AccountTokenGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *AccountTokenRepository) Use(id string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&AccountTokenGorm{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to use token", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Invalidate also purges the tokens of the user that expired
+func (r *AccountTokenRepository) Invalidate(userID string, purpose models.AccountTokenPurpose, at time.Time) *models.SystemError {
+	db := r.db.WithContext(r.currentContext())
+	err := db.Model(&AccountTokenGorm{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, string(purpose)).
+		Update("used_at", at).Error
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to invalidate tokens", struct{}{})
+	}
+	db.Where("user_id = ? AND expires_at < ?", userID, at).Delete(&AccountTokenGorm{})
+	return nil
+}
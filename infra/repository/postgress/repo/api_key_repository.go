@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"encoding/json"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ServiceAccountGorm struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name        string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description string    `gorm:"type:text"`
+	CreatedBy   string    `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time
+}
+
+func (ServiceAccountGorm) TableName() string {
+	return "service_accounts"
+}
+
+func (s ServiceAccountGorm) ToModel() models.ServiceAccount {
+	return models.ServiceAccount{
+		ID:          fromGUIDToString(s.ID),
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedBy:   s.CreatedBy,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+func ServiceAccountToEntity(s models.ServiceAccount) ServiceAccountGorm {
+	id, _ := uuid.Parse(s.ID)
+	return ServiceAccountGorm{
+		ID:          id,
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedBy:   s.CreatedBy,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+type ServiceAccountRepository struct {
+	GenericCrud[models.ServiceAccount, ServiceAccountGorm]
+	db *gorm.DB
+}
+
+func NewServiceAccountRepository(db *gorm.DB) contracts.ServiceAccountContract {
+	return &ServiceAccountRepository{
+		GenericCrud: NewGenericCrud(db, ServiceAccountToEntity, (ServiceAccountGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *ServiceAccountRepository) All() ([]models.ServiceAccount, *models.SystemError) {
+	var entities []ServiceAccountGorm
+	if err := r.db.WithContext(r.currentContext()).Order("name").Find(&entities).Error; err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to list service accounts", struct{}{})
+	}
+	accounts := make([]models.ServiceAccount, 0, len(entities))
+	for _, entity := range entities {
+		accounts = append(accounts, entity.ToModel())
+	}
+	return accounts, nil
+}
+
+type APIKeyGorm struct {
+	ID               uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ServiceAccountID uuid.UUID           `gorm:"type:uuid;not null;index"`
+	ServiceAccount   *ServiceAccountGorm `gorm:"foreignKey:ServiceAccountID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Name             string              `gorm:"type:varchar(255)"`
+	Prefix           string              `gorm:"type:varchar(32);not null"`
+	KeyHash          string              `gorm:"type:varchar(64);not null;uniqueIndex"`
+	// Permission names serialized as JSON
+	Permissions string     `gorm:"type:jsonb;not null;default:'[]'"`
+	ExpiresAt   time.Time  `gorm:"type:timestamptz;not null"`
+	LastUsedAt  *time.Time `gorm:"type:timestamptz"`
+	RevokedAt   *time.Time `gorm:"type:timestamptz"`
+	CreatedBy   string     `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time
+}
+
+func (APIKeyGorm) TableName() string {
+	return "api_keys"
+}
+
+func (k APIKeyGorm) ToModel() models.APIKey {
+	var permissions []string
+	_ = json.Unmarshal([]byte(k.Permissions), &permissions)
+	return models.APIKey{
+		ID:               fromGUIDToString(k.ID),
+		ServiceAccountID: fromGUIDToString(k.ServiceAccountID),
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		KeyHash:          k.KeyHash,
+		Permissions:      permissions,
+		ExpiresAt:        k.ExpiresAt,
+		LastUsedAt:       k.LastUsedAt,
+		RevokedAt:        k.RevokedAt,
+		CreatedBy:        k.CreatedBy,
+		CreatedAt:        k.CreatedAt,
+	}
+}
+
+func APIKeyToEntity(k models.APIKey) APIKeyGorm {
+	id, _ := uuid.Parse(k.ID)
+	accountID, _ := uuid.Parse(k.ServiceAccountID)
+	permissions, err := json.Marshal(k.Permissions)
+	if err != nil || k.Permissions == nil {
+		permissions = []byte("[]")
+	}
+	return APIKeyGorm{
+		ID:               id,
+		ServiceAccountID: accountID,
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		KeyHash:          k.KeyHash,
+		Permissions:      string(permissions),
+		ExpiresAt:        k.ExpiresAt,
+		LastUsedAt:       k.LastUsedAt,
+		RevokedAt:        k.RevokedAt,
+		CreatedBy:        k.CreatedBy,
+		CreatedAt:        k.CreatedAt,
+	}
+}
+
+type APIKeyRepository struct {
+	GenericCrud[models.APIKey, APIKeyGorm]
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) contracts.APIKeyContract {
+	return &APIKeyRepository{
+		GenericCrud: NewGenericCrud(db, APIKeyToEntity, (APIKeyGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *APIKeyRepository) ByServiceAccount(serviceAccountID string) ([]models.APIKey, *models.SystemError) {
+	if _, err := uuid.Parse(serviceAccountID); err != nil {
+		return []models.APIKey{}, nil
+	}
+	var entities []APIKeyGorm
+	err := r.db.WithContext(r.currentContext()).
+		Where("service_account_id = ?", serviceAccountID).
+		Order("created_at DESC").
+		Find(&entities).Error
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to list api keys", struct{}{})
+	}
+	keys := make([]models.APIKey, 0, len(entities))
+	for _, entity := range entities {
+		keys = append(keys, entity.ToModel())
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(id string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&APIKeyGorm{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to revoke api key", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *APIKeyRepository) Touch(id string, at time.Time) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).
+		Model(&APIKeyGorm{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to record api key use", struct{}{})
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttendanceEventGorm struct {
+	ID                uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EmployeeID        uuid.UUID     `gorm:"type:uuid;not null;index:idx_attendance_employee_timestamp,priority:1"`
+	Employee          *EmployeeGorm `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Type              string        `gorm:"type:varchar(20);not null"`
+	Source            string        `gorm:"type:varchar(20);not null"`
+	Timestamp         time.Time     `gorm:"type:timestamptz;not null;index:idx_attendance_employee_timestamp,priority:2"`
+	Note              string        `gorm:"type:text"`
+	Corrected         bool          `gorm:"type:boolean;not null;default:false"`
+	OriginalTimestamp *time.Time    `gorm:"type:timestamptz"`
+	CorrectionReason  string        `gorm:"type:text"`
+	CorrectedByID     *uuid.UUID    `gorm:"type:uuid"`
+	CorrectedBy       *EmployeeGorm `gorm:"foreignKey:CorrectedByID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (AttendanceEventGorm) TableName() string {
+	return "attendance_events"
+}
+
+func (a AttendanceEventGorm) ToModel() models.AttendanceEvent {
+	return models.AttendanceEvent{
+		ID:                fromGUIDToString(a.ID),
+		EmployeeID:        fromGUIDToString(a.EmployeeID),
+		Type:              models.ClockEventType(a.Type),
+		Source:            models.ClockSource(a.Source),
+		Timestamp:         a.Timestamp,
+		Note:              a.Note,
+		Corrected:         a.Corrected,
+		OriginalTimestamp: a.OriginalTimestamp,
+		CorrectionReason:  a.CorrectionReason,
+		CorrectedByID:     fromNullableGUID(a.CorrectedByID),
+	}
+}
+
+func AttendanceEventToEntity(a models.AttendanceEvent) AttendanceEventGorm {
+	id, _ := uuid.Parse(a.ID)
+	employeeID, _ := uuid.Parse(a.EmployeeID)
+	return AttendanceEventGorm{
+		ID:                id,
+		EmployeeID:        employeeID,
+		Type:              string(a.Type),
+		Source:            string(a.Source),
+		Timestamp:         a.Timestamp,
+		Note:              a.Note,
+		Corrected:         a.Corrected,
+		OriginalTimestamp: a.OriginalTimestamp,
+		CorrectionReason:  a.CorrectionReason,
+		CorrectedByID:     toNullableGUID(a.CorrectedByID),
+	}
+}
+
+type AttendanceRepository struct {
+	GenericCrud[models.AttendanceEvent, AttendanceEventGorm]
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) contracts.AttendanceContract {
+	return &AttendanceRepository{
+		GenericCrud: NewGenericCrud(db, AttendanceEventToEntity, (AttendanceEventGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *AttendanceRepository) GetEventsBetween(employeeID string, from, to time.Time) ([]models.AttendanceEvent, *models.SystemError) {
+	var gormModels []AttendanceEventGorm
+	dbQuery := r.db.WithContext(r.currentContext()).Where("timestamp >= ? AND timestamp < ?", from, to)
+	if employeeID != "" {
+		dbQuery = dbQuery.Where("employee_id = ?", employeeID)
+	}
+	if err := dbQuery.Order("employee_id, timestamp").Find(&gormModels).Error; err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+
+	events := make([]models.AttendanceEvent, len(gormModels))
+	for i, gm := range gormModels {
+		events[i] = gm.ToModel()
+	}
+	return events, nil
+}
+
+func (r *AttendanceRepository) GetLastEvent(employeeID string) (*models.AttendanceEvent, *models.SystemError) {
+	var gormModel AttendanceEventGorm
+	err := r.db.WithContext(r.currentContext()).Where("employee_id = ?", employeeID).Order("timestamp DESC").First(&gormModel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+	event := gormModel.ToModel()
+	return &event, nil
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditEntryGorm struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Action    string    `gorm:"type:varchar(64);not null;index"`
+	Actor     string    `gorm:"type:varchar(255)"`
+	Subject   string    `gorm:"type:varchar(255);index"`
+	IP        string    `gorm:"type:varchar(64)"`
+	Details   string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;index"`
+}
+
+func (AuditEntryGorm) TableName() string {
+	return "audit_logs"
+}
+
+func (a AuditEntryGorm) ToModel() models.AuditEntry {
+	return models.AuditEntry{
+		ID:        fromGUIDToString(a.ID),
+		Action:    models.AuditAction(a.Action),
+		Actor:     a.Actor,
+		Subject:   a.Subject,
+		IP:        a.IP,
+		Details:   a.Details,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func AuditEntryToEntity(a models.AuditEntry) AuditEntryGorm {
+	id, _ := uuid.Parse(a.ID)
+	return AuditEntryGorm{
+		ID:        id,
+		Action:    string(a.Action),
+		Actor:     a.Actor,
+		Subject:   a.Subject,
+		IP:        a.IP,
+		Details:   a.Details,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+type AuditRepository struct {
+	GenericCrud[models.AuditEntry, AuditEntryGorm]
+}
+
+func NewAuditRepository(db *gorm.DB) contracts.AuditContract {
+	return &AuditRepository{
+		GenericCrud: NewGenericCrud(db, AuditEntryToEntity, (AuditEntryGorm).ToModel),
+	}
+}
+
+func (r *AuditRepository) Record(entry models.AuditEntry) *models.SystemError {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	_, err := r.Create(entry)
+	return err
+}
@@ -11,11 +11,16 @@ import (
 )
 
 type DepartmentGorm struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	Name      string    `gorm:"type:varchar(255)"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID          uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name        string          `gorm:"type:varchar(255)"`
+	Description string          `gorm:"type:text"`
+	ParentID    *uuid.UUID      `gorm:"type:uuid;index"`
+	Parent      *DepartmentGorm `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	// The head is validated by the use cases, a foreign key here would make departments and employees mutually dependent
+	HeadEmployeeID *uuid.UUID `gorm:"type:uuid;index"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 func (DepartmentGorm) TableName() string {
@@ -24,15 +29,22 @@ func (DepartmentGorm) TableName() string {
 
 func (d DepartmentGorm) ToModel() models.Department {
 	return models.Department{
-		ID:   fromGUIDToString(d.ID),
-		Name: d.Name,
+		ID:             fromGUIDToString(d.ID),
+		Name:           d.Name,
+		Description:    d.Description,
+		ParentID:       fromNullableGUID(d.ParentID),
+		HeadEmployeeID: fromNullableGUID(d.HeadEmployeeID),
 	}
 }
 
 func ToEntity(d models.Department) DepartmentGorm {
+	id, _ := uuid.Parse(d.ID)
 	return DepartmentGorm{
-		// Id:   uuid.FromStringOrNil(d.ID),
-		Name: d.Name,
+		ID:             id,
+		Name:           d.Name,
+		Description:    d.Description,
+		ParentID:       toNullableGUID(d.ParentID),
+		HeadEmployeeID: toNullableGUID(d.HeadEmployeeID),
 	}
 }
 
@@ -49,7 +61,3 @@ func NewDepartmentRepository(db *gorm.DB) contracts.DepartmentContract {
 		GenericCrud: NewGenericCrud(db, ToEntity, (DepartmentGorm).ToModel),
 	}
 }
-
-func (d *DepartmentRepository) SomeMethod() models.SystemError {
-	return models.SystemError{}
-}
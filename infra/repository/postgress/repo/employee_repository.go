@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmployeeGorm struct {
+	ID                uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EmployeeNumber    string          `gorm:"type:varchar(50);uniqueIndex;not null"`
+	FirstName         string          `gorm:"type:varchar(255);not null"`
+	LastName          string          `gorm:"type:varchar(255);not null"`
+	NationalID        string          `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Email             string          `gorm:"type:varchar(255)"`
+	Phone             string          `gorm:"type:varchar(50)"`
+	Address           string          `gorm:"type:text"`
+	HireDate          time.Time       `gorm:"type:date;not null"`
+	TerminationDate   *time.Time      `gorm:"type:date"`
+	TerminationReason string          `gorm:"type:text"`
+	Status            string          `gorm:"type:varchar(50);not null;default:'active';index"`
+	UserID            *uuid.UUID      `gorm:"type:uuid;index"`
+	User              *UserGorm       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	ManagerID         *uuid.UUID      `gorm:"type:uuid;index"`
+	Manager           *EmployeeGorm   `gorm:"foreignKey:ManagerID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	DepartmentID      *uuid.UUID      `gorm:"type:uuid;index"`
+	Department        *DepartmentGorm `gorm:"foreignKey:DepartmentID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	PositionID        *uuid.UUID      `gorm:"type:uuid;index"`
+	Position          *PositionGorm   `gorm:"foreignKey:PositionID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Salary            float64         `gorm:"type:numeric(14,2);not null;default:0"`
+	BankName          string          `gorm:"type:varchar(100)"`
+	BankAccount       string          `gorm:"type:varchar(50)"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+}
+
+func (EmployeeGorm) TableName() string {
+	return "employees"
+}
+
+func (e EmployeeGorm) ToModel() models.Employee {
+	return models.Employee{
+		ID:                fromGUIDToString(e.ID),
+		EmployeeNumber:    e.EmployeeNumber,
+		FirstName:         e.FirstName,
+		LastName:          e.LastName,
+		NationalID:        e.NationalID,
+		Email:             e.Email,
+		Phone:             e.Phone,
+		Address:           e.Address,
+		HireDate:          e.HireDate,
+		TerminationDate:   e.TerminationDate,
+		TerminationReason: e.TerminationReason,
+		Status:            models.EmploymentStatus(e.Status),
+		UserID:            fromNullableGUID(e.UserID),
+		ManagerID:         fromNullableGUID(e.ManagerID),
+		DepartmentID:      fromNullableGUID(e.DepartmentID),
+		PositionID:        fromNullableGUID(e.PositionID),
+		Salary:            e.Salary,
+		BankName:          e.BankName,
+		BankAccount:       e.BankAccount,
+	}
+}
+
+func EmployeeToEntity(e models.Employee) EmployeeGorm {
+	id, _ := uuid.Parse(e.ID)
+	return EmployeeGorm{
+		ID:                id,
+		EmployeeNumber:    e.EmployeeNumber,
+		FirstName:         e.FirstName,
+		LastName:          e.LastName,
+		NationalID:        e.NationalID,
+		Email:             e.Email,
+		Phone:             e.Phone,
+		Address:           e.Address,
+		HireDate:          e.HireDate,
+		TerminationDate:   e.TerminationDate,
+		TerminationReason: e.TerminationReason,
+		Status:            string(e.Status),
+		UserID:            toNullableGUID(e.UserID),
+		ManagerID:         toNullableGUID(e.ManagerID),
+		DepartmentID:      toNullableGUID(e.DepartmentID),
+		PositionID:        toNullableGUID(e.PositionID),
+		Salary:            e.Salary,
+		BankName:          e.BankName,
+		BankAccount:       e.BankAccount,
+	}
+}
+
+// toNullableGUID converts an optional string id into a nullable uuid, empty or invalid ids become NULL
+func toNullableGUID(id string) *uuid.UUID {
+	if id == "" {
+		return nil
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+func fromNullableGUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+type EmployeeRepository struct {
+	GenericCrud[models.Employee, EmployeeGorm]
+}
+
+func NewEmployeeRepository(db *gorm.DB) contracts.EmployeeContract {
+	return &EmployeeRepository{
+		GenericCrud: NewGenericCrud(db, EmployeeToEntity, (EmployeeGorm).ToModel),
+	}
+}
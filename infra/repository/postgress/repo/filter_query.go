@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"reflect"
+	"strings"
+
+	"hrms.local/core/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// whereFilters adds the conditions of the query to dbQuery, its filters and groups joined with AND.
+// Filter keys are resolved through fields, a key that is not a filterable field never reaches SQL.
+func whereFilters(dbQuery *gorm.DB, fields models.SearchFields, query models.SearchQuery) (*gorm.DB, *models.SystemError) {
+	condition, args, err := groupCondition(fields, models.FilterGroup{Filters: query.Filters, Groups: query.Groups})
+	if err != nil {
+		return nil, err
+	}
+	if condition == "" {
+		return dbQuery, nil
+	}
+	return dbQuery.Where(condition, args...), nil
+}
+
+// groupCondition builds the SQL of a group, nested groups are parenthesized
+func groupCondition(fields models.SearchFields, group models.FilterGroup) (string, []any, *models.SystemError) {
+	parts := make([]string, 0, len(group.Filters)+len(group.Groups))
+	args := []any{}
+	for _, filter := range group.Filters {
+		condition, values, err := filterCondition(fields, filter)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, condition)
+		args = append(args, values...)
+	}
+	for _, nested := range group.Groups {
+		condition, values, err := groupCondition(fields, nested)
+		if err != nil {
+			return "", nil, err
+		}
+		if condition != "" {
+			parts = append(parts, "("+condition+")")
+			args = append(args, values...)
+		}
+	}
+	separator := " AND "
+	if group.Or() {
+		separator = " OR "
+	}
+	return strings.Join(parts, separator), args, nil
+}
+
+func filterCondition(fields models.SearchFields, filter models.Filter) (string, []any, *models.SystemError) {
+	_, field, ok := fields.Lookup(filter.Key)
+	if !ok || !field.Filterable {
+		return "", nil, invalidFilterQuery("field cannot be filtered: " + filter.Key)
+	}
+	column := field.Column
+	switch filter.Op() {
+	case models.FilterOperatorEq:
+		return column + " = ?", []any{filter.Value}, nil
+	case models.FilterOperatorNeq:
+		return column + " <> ?", []any{filter.Value}, nil
+	case models.FilterOperatorLt:
+		return column + " < ?", []any{filter.Value}, nil
+	case models.FilterOperatorLte:
+		return column + " <= ?", []any{filter.Value}, nil
+	case models.FilterOperatorGt:
+		return column + " > ?", []any{filter.Value}, nil
+	case models.FilterOperatorGte:
+		return column + " >= ?", []any{filter.Value}, nil
+	case models.FilterOperatorLike:
+		return column + " LIKE ?", []any{filter.Value}, nil
+	case models.FilterOperatorILike:
+		return column + " ILIKE ?", []any{filter.Value}, nil
+	case models.FilterOperatorIn, models.FilterOperatorNotIn:
+		values := listValues(filter.Value)
+		if len(values) == 0 {
+			return "", nil, invalidFilterQuery(string(filter.Op()) + " needs a list of values for " + filter.Key)
+		}
+		if filter.Op() == models.FilterOperatorNotIn {
+			return column + " NOT IN ?", []any{values}, nil
+		}
+		return column + " IN ?", []any{values}, nil
+	case models.FilterOperatorBetween:
+		bounds := listValues(filter.Value)
+		if len(bounds) != 2 {
+			return "", nil, invalidFilterQuery("between needs two bounds for " + filter.Key)
+		}
+		return column + " BETWEEN ? AND ?", bounds, nil
+	case models.FilterOperatorIsNull:
+		if isNull, _ := filter.Value.(bool); isNull {
+			return column + " IS NULL", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
+	}
+	return "", nil, invalidFilterQuery("unknown operator " + string(filter.Operator))
+}
+
+// orderBy sorts dbQuery by the sort of the query, rows tied on every field are ordered by id
+func orderBy(dbQuery *gorm.DB, fields models.SearchFields, sort models.SortOrder) (*gorm.DB, *models.SystemError) {
+	keys, err := sortKeys(fields, sort)
+	if err != nil {
+		return nil, err
+	}
+	return orderByKeys(dbQuery, keys, false), nil
+}
+
+// orderByKeys sorts dbQuery by keys, in the opposite order when reversed
+func orderByKeys(dbQuery *gorm.DB, keys []sortKey, reversed bool) *gorm.DB {
+	for _, key := range keys {
+		dbQuery = dbQuery.Order(key.order(reversed))
+	}
+	return dbQuery
+}
+
+func orderClauses(fields models.SearchFields, sort models.SortOrder) ([]string, *models.SystemError) {
+	keys, err := sortKeys(fields, sort)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]string, len(keys))
+	for i, key := range keys {
+		orders[i] = key.order(false)
+	}
+	return orders, nil
+}
+
+// sortKey is a column the rows of a query are sorted by
+type sortKey struct {
+	column string
+	desc   bool
+	nulls  models.SortNulls
+}
+
+func (k sortKey) order(reversed bool) string {
+	desc, nulls := k.desc, k.nulls
+	if reversed {
+		desc = !desc
+		switch nulls {
+		case models.SortNullsFirst:
+			nulls = models.SortNullsLast
+		case models.SortNullsLast:
+			nulls = models.SortNullsFirst
+		}
+	}
+	order := k.column + " ASC"
+	if desc {
+		order = k.column + " DESC"
+	}
+	switch nulls {
+	case models.SortNullsFirst:
+		order += " NULLS FIRST"
+	case models.SortNullsLast:
+		order += " NULLS LAST"
+	}
+	return order
+}
+
+// sortKeys resolves the sort through fields and ends it with the id, in the direction of the
+// last field so a sort in one direction can be read from a single index
+func sortKeys(fields models.SearchFields, sort models.SortOrder) ([]sortKey, *models.SystemError) {
+	idColumn := "id"
+	if id, ok := fields["ID"]; ok {
+		idColumn = id.Column
+	}
+	keys := make([]sortKey, 0, len(sort)+1)
+	tieBreaker := true
+	for _, s := range sort {
+		_, field, ok := fields.Lookup(s.Field)
+		if !ok || !field.Sortable {
+			return nil, invalidFilterQuery("field cannot be sorted: " + s.Field)
+		}
+		// the id is unique, nothing sorted after it changes the order
+		if field.Column == idColumn {
+			tieBreaker = false
+		}
+		keys = append(keys, sortKey{column: field.Column, desc: s.Desc(), nulls: s.Nulls})
+	}
+	if tieBreaker {
+		desc := len(keys) > 0 && keys[len(keys)-1].desc
+		keys = append(keys, sortKey{column: idColumn, desc: desc})
+	}
+	return keys, nil
+}
+
+// columnEquals matches a column by name, quoted so the name cannot inject SQL
+func columnEquals(column string, value any) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: column}, Value: value}
+}
+
+// listValues copies any slice into the []any gorm expands for IN and BETWEEN
+func listValues(value any) []any {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil
+	}
+	values := make([]any, list.Len())
+	for i := range values {
+		values[i] = list.Index(i).Interface()
+	}
+	return values
+}
+
+func invalidFilterQuery(message string) *models.SystemError {
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, message, struct{}{})
+}
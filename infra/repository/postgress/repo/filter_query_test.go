@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+
+	"hrms.local/core/models"
+)
+
+var testSearchFields = models.SearchFields{
+	"ID":          {Column: "id", Filterable: true, Sortable: true},
+	"Active":      {Column: "active", Filterable: true},
+	"Type":        {Column: "type", Filterable: true},
+	"LockedUntil": {Column: "locked_until", Filterable: true},
+	"Name":        {Column: "name", Filterable: true, Sortable: true},
+	"CreatedAt":   {Column: "created_at", Filterable: true, Sortable: true},
+	"Password":    {Column: "password"},
+}
+
+func TestGroupConditionBuildsOperatorsAndGroups(t *testing.T) {
+	condition, args, err := groupCondition(testSearchFields, models.FilterGroup{
+		Filters: models.Filters{
+			{Key: "Active", Value: true},
+			{Key: "Type", Operator: models.FilterOperatorIn, Value: []string{"admin", "normal"}},
+			{Key: "LockedUntil", Operator: models.FilterOperatorIsNull, Value: true},
+		},
+		Groups: []models.FilterGroup{{Logic: models.FilterLogicOr, Filters: models.Filters{
+			{Key: "Name", Operator: models.FilterOperatorILike, Value: "ana%"},
+			{Key: "CreatedAt", Operator: models.FilterOperatorBetween, Value: []any{"2026-01-01", "2026-12-31"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "active = ? AND type IN ? AND locked_until IS NULL AND (name ILIKE ? OR created_at BETWEEN ? AND ?)"
+	if condition != expected {
+		t.Fatalf("Expected %q, got %q", expected, condition)
+	}
+	expectedArgs := []any{true, []any{"admin", "normal"}, "ana%", "2026-01-01", "2026-12-31"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestFilterConditionRefusesMalformedValues(t *testing.T) {
+	for _, filter := range []models.Filter{
+		{Key: "Type", Operator: models.FilterOperatorNotIn, Value: "1"},
+		{Key: "CreatedAt", Operator: models.FilterOperatorBetween, Value: []any{"2026-01-01"}},
+		{Key: "Name", Operator: "regex", Value: ".*"},
+		{Key: "Password", Value: "secret"},
+		{Key: "1 = 1 OR name", Value: "x"},
+	} {
+		if _, _, err := filterCondition(testSearchFields, filter); err == nil {
+			t.Errorf("Expected %s on %s to be refused", filter.Operator, filter.Key)
+		}
+	}
+}
+
+func TestOrderClausesEndWithTheID(t *testing.T) {
+	orders, err := orderClauses(testSearchFields, nil)
+	if err != nil || !reflect.DeepEqual(orders, []string{"id ASC"}) {
+		t.Fatalf("Expected rows ordered by id, got %v %v", orders, err)
+	}
+
+	orders, err = orderClauses(testSearchFields, models.SortOrder{
+		{Field: "CreatedAt", Direction: models.SortDesc, Nulls: models.SortNullsLast},
+		{Field: "name"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"created_at DESC NULLS LAST", "name ASC", "id ASC"}
+	if !reflect.DeepEqual(orders, expected) {
+		t.Fatalf("Expected %v, got %v", expected, orders)
+	}
+
+	orders, _ = orderClauses(testSearchFields, models.SortOrder{{Field: "Name", Direction: models.SortDesc}})
+	if !reflect.DeepEqual(orders, []string{"name DESC", "id DESC"}) {
+		t.Fatalf("Expected the id to follow the direction of the last field, got %v", orders)
+	}
+
+	orders, _ = orderClauses(testSearchFields, models.SortOrder{{Field: "ID", Direction: models.SortDesc}})
+	if !reflect.DeepEqual(orders, []string{"id DESC"}) {
+		t.Fatalf("Expected no tie-breaker after the id, got %v", orders)
+	}
+
+	for _, field := range []string{"Active", "Password", "name; DROP TABLE users"} {
+		if _, err := orderClauses(testSearchFields, models.SortOrder{{Field: field}}); err == nil {
+			t.Errorf("Expected sorting on %s to be refused", field)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 
 	"hrms.local/core/models"
@@ -15,6 +16,8 @@ type GenericCrud[T any, G any] struct {
 	ctx      context.Context
 	ToGorm   func(T) G
 	ToEntity func(G) T
+	// Columns search queries can use, declared by T through models.Searchable
+	fields models.SearchFields
 }
 
 func NewGenericCrud[T any, G any](db *gorm.DB, toGorm func(T) G, toEntity func(G) T) GenericCrud[T, G] {
@@ -23,6 +26,7 @@ func NewGenericCrud[T any, G any](db *gorm.DB, toGorm func(T) G, toEntity func(G
 		ctx:      context.Background(),
 		ToGorm:   toGorm,
 		ToEntity: toEntity,
+		fields:   models.SearchFieldsOf(*new(T)),
 	}
 }
 
@@ -46,22 +50,39 @@ func (g *GenericCrud[T, G]) currentContext() context.Context {
 }
 
 func (g *GenericCrud[T, G]) GetByFilter(query models.SearchQuery) (*models.PaginatedResponse[T], *models.SystemError) {
-	totalRows, sysErr := g.CountByFilter(query)
+	return g.getByFilter(g.db.WithContext(g.currentContext()), query)
+}
+
+// getByFilter lists the rows of base matching the query, overrides pass a base with their preloads
+func (g *GenericCrud[T, G]) getByFilter(base *gorm.DB, query models.SearchQuery) (*models.PaginatedResponse[T], *models.SystemError) {
+	totalRows, estimated, sysErr := g.total(query)
 	if sysErr != nil {
 		return nil, sysErr
 	}
 
-	var gormModels []G
-	dbQuery := g.db.WithContext(g.currentContext())
-	for _, filter := range query.Filters {
-		dbQuery = dbQuery.Where(filter.Key+" = ?", filter.Value)
+	dbQuery, sysErr := whereFilters(base, g.fields, query)
+	if sysErr != nil {
+		return nil, sysErr
 	}
 
-	limit := query.Pagination.GetLimit()
-	dbQuery = dbQuery.Limit(limit).Offset(query.Pagination.GetOffset())
+	keys, sysErr := sortKeys(g.fields, query.Sort)
+	if sysErr != nil {
+		return nil, sysErr
+	}
 
-	if err := dbQuery.Find(&gormModels).Error; err != nil {
-		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	limit := query.Pagination.GetLimit()
+	var gormModels []G
+	var next, prev string
+	if query.Pagination.UsesCursor() {
+		gormModels, next, prev, sysErr = keysetPage[G](dbQuery, keys, query.Pagination.Cursor, limit)
+		if sysErr != nil {
+			return nil, sysErr
+		}
+	} else {
+		dbQuery = orderByKeys(dbQuery, keys, false).Limit(limit).Offset(query.Pagination.GetOffset())
+		if err := dbQuery.Find(&gormModels).Error; err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+		}
 	}
 
 	var entities []T
@@ -70,23 +91,41 @@ func (g *GenericCrud[T, G]) GetByFilter(query models.SearchQuery) (*models.Pagin
 	}
 
 	totalPages := 0
-	if limit > 0 {
+	if totalRows < 0 {
+		totalPages = -1
+	} else if limit > 0 {
 		totalPages = int((totalRows + int64(limit) - 1) / int64(limit))
 	}
 
 	return &models.PaginatedResponse[T]{
-		TotalRows:  totalRows,
-		TotalPages: totalPages,
-		Rows:       entities,
+		TotalRows:      totalRows,
+		TotalPages:     totalPages,
+		TotalEstimated: estimated,
+		NextCursor:     next,
+		PrevCursor:     prev,
+		Rows:           entities,
 	}, nil
 }
 
+// total counts the rows of the query the way its pagination asks, -1 when the count is skipped
+func (g *GenericCrud[T, G]) total(query models.SearchQuery) (int64, bool, *models.SystemError) {
+	switch query.Pagination.CountMode() {
+	case models.CountNone:
+		return -1, false, nil
+	case models.CountEstimate:
+		count, sysErr := g.estimateByFilter(query)
+		return count, true, sysErr
+	}
+	count, sysErr := g.CountByFilter(query)
+	return count, false, sysErr
+}
+
 func (g *GenericCrud[T, G]) CountByFilter(query models.SearchQuery) (int64, *models.SystemError) {
 	var count int64
 	var gormModel G
-	dbQuery := g.db.WithContext(g.currentContext()).Model(&gormModel)
-	for _, filter := range query.Filters {
-		dbQuery = dbQuery.Where(filter.Key+" = ?", filter.Value)
+	dbQuery, sysErr := whereFilters(g.db.WithContext(g.currentContext()).Model(&gormModel), g.fields, query)
+	if sysErr != nil {
+		return 0, sysErr
 	}
 
 	if err := dbQuery.Count(&count).Error; err != nil {
@@ -96,6 +135,35 @@ func (g *GenericCrud[T, G]) CountByFilter(query models.SearchQuery) (int64, *mod
 	return count, nil
 }
 
+// estimateByFilter returns the rows the query planner expects the query to match, read from
+// EXPLAIN without scanning them. It follows the statistics of the table, not its latest rows.
+func (g *GenericCrud[T, G]) estimateByFilter(query models.SearchQuery) (int64, *models.SystemError) {
+	ctx := g.currentContext()
+	var gormModels []G
+	dbQuery, sysErr := whereFilters(g.db.WithContext(ctx), g.fields, query)
+	if sysErr != nil {
+		return 0, sysErr
+	}
+
+	// a dry run builds the SQL of the query without running it
+	statement := dbQuery.Session(&gorm.Session{DryRun: true}).Find(&gormModels).Statement
+	var plan []byte
+	row := g.db.WithContext(ctx).ConnPool.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+statement.SQL.String(), statement.Vars...)
+	if err := row.Scan(&plan); err != nil {
+		return 0, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Count failed", struct{}{})
+	}
+
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil || len(explained) == 0 {
+		return 0, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Count failed", struct{}{})
+	}
+	return int64(explained[0].Plan.Rows), nil
+}
+
 func (g *GenericCrud[T, G]) Create(item T) (T, *models.SystemError) {
 	gormModel := g.ToGorm(item)
 	if err := g.db.WithContext(g.currentContext()).Create(&gormModel).Error; err != nil {
@@ -122,7 +190,7 @@ func (g *GenericCrud[T, G]) Delete(id string) (interface{}, error) {
 
 func (g *GenericCrud[T, G]) GetOnce(key string, value any) (*T, *models.SystemError) {
 	var gormModel G
-	dbQuery := g.db.WithContext(g.currentContext()).Where(key+" = ?", value)
+	dbQuery := g.db.WithContext(g.currentContext()).Where(columnEquals(key, value))
 	if err := dbQuery.First(&gormModel).Error; err != nil {
 		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "GetOnce failed", struct{}{})
 	}
@@ -132,7 +200,7 @@ func (g *GenericCrud[T, G]) GetOnce(key string, value any) (*T, *models.SystemEr
 
 func (g *GenericCrud[T, G]) Exists(key string, value any) (bool, *models.SystemError) {
 	var gormModel G
-	dbQuery := g.db.WithContext(g.currentContext()).Where(key+" = ?", value)
+	dbQuery := g.db.WithContext(g.currentContext()).Where(columnEquals(key, value))
 	if err := dbQuery.First(&gormModel).Error; err != nil {
 		return false, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Exists failed", struct{}{})
 	}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+	gormModels "hrms.local/repository/postgress/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvitationGorm struct {
+	ID        uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Email     string               `gorm:"type:varchar(255);not null;index"`
+	RoleID    *uuid.UUID           `gorm:"type:uuid"`
+	Role      *gormModels.RoleGorm `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Type      string               `gorm:"type:varchar(32);not null"`
+	TokenHash string               `gorm:"type:varchar(64);not null;uniqueIndex"`
+	InvitedBy string               `gorm:"type:varchar(255)"`
+	ExpiresAt time.Time            `gorm:"type:timestamptz;not null"`
+	UsedAt    *time.Time           `gorm:"type:timestamptz"`
+	RevokedAt *time.Time           `gorm:"type:timestamptz"`
+	CreatedAt time.Time
+}
+
+func (InvitationGorm) TableName() string {
+	return "invitations"
+}
+
+func (i InvitationGorm) ToModel() models.Invitation {
+	return models.Invitation{
+		ID:        fromGUIDToString(i.ID),
+		Email:     i.Email,
+		RoleID:    fromNullableGUID(i.RoleID),
+		Type:      models.UserType(i.Type),
+		TokenHash: i.TokenHash,
+		InvitedBy: i.InvitedBy,
+		ExpiresAt: i.ExpiresAt,
+		UsedAt:    i.UsedAt,
+		RevokedAt: i.RevokedAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func InvitationToEntity(i models.Invitation) InvitationGorm {
+	id, _ := uuid.Parse(i.ID)
+	return InvitationGorm{
+		ID:        id,
+		Email:     i.Email,
+		RoleID:    toNullableGUID(i.RoleID),
+		Type:      string(i.Type),
+		TokenHash: i.TokenHash,
+		InvitedBy: i.InvitedBy,
+		ExpiresAt: i.ExpiresAt,
+		UsedAt:    i.UsedAt,
+		RevokedAt: i.RevokedAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+type InvitationRepository struct {
+	GenericCrud[models.Invitation, InvitationGorm]
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) contracts.InvitationContract {
+	return &InvitationRepository{
+		GenericCrud: NewGenericCrud(db, InvitationToEntity, (InvitationGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *InvitationRepository) Accept(id string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&InvitationGorm{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to accept invitation", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *InvitationRepository) Revoke(id string, at time.Time) (bool, *models.SystemError) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	result := r.db.WithContext(r.currentContext()).
+		Model(&InvitationGorm{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to revoke invitation", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *InvitationRepository) Pending(now time.Time) ([]models.Invitation, *models.SystemError) {
+	var entities []InvitationGorm
+	err := r.db.WithContext(r.currentContext()).
+		Where("used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&entities).Error
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to list invitations", struct{}{})
+	}
+	invitations := make([]models.Invitation, 0, len(entities))
+	for _, entity := range entities {
+		invitations = append(invitations, entity.ToModel())
+	}
+	return invitations, nil
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"hrms.local/core/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// cursor is the position of a row in a sorted listing, the next and prev cursors of a page
+// are cursors encoded as base64 JSON
+type cursor struct {
+	// Sort is the order the cursor was made for, a cursor only continues the same listing
+	Sort string `json:"s"`
+	// Values are the values of the row for each sort key
+	Values []any `json:"v"`
+	// Backward continues with the rows before the row instead of the ones after it
+	Backward bool `json:"b,omitempty"`
+}
+
+func encodeCursor(c cursor) (string, *models.SystemError) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Cursor failed", struct{}{})
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reads an encoded cursor of the listing sorted by keys
+func decodeCursor(encoded string, keys []sortKey) (cursor, *models.SystemError) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return c, invalidFilterQuery("invalid cursor")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return c, invalidFilterQuery("invalid cursor")
+	}
+	if c.Sort != keysFingerprint(keys) || len(c.Values) != len(keys) {
+		return c, invalidFilterQuery("the cursor belongs to a listing with another sort")
+	}
+	for i, value := range c.Values {
+		switch v := value.(type) {
+		case nil:
+			return c, invalidFilterQuery("invalid cursor")
+		case json.Number:
+			// integers bind as integers, JSON would turn them into float64
+			if n, err := v.Int64(); err == nil {
+				c.Values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				c.Values[i] = f
+			} else {
+				return c, invalidFilterQuery("invalid cursor")
+			}
+		case string, bool:
+		default:
+			return c, invalidFilterQuery("invalid cursor")
+		}
+	}
+	return c, nil
+}
+
+// keysFingerprint names the order of keys, the cursors of another order are refused
+func keysFingerprint(keys []sortKey) string {
+	orders := make([]string, len(keys))
+	for i, key := range keys {
+		orders[i] = key.order(false)
+	}
+	return strings.Join(orders, ",")
+}
+
+// keysetCondition matches the rows after values in the order of keys, or before them when backward.
+// Keys sorted in one direction compare as a row, mixed directions expand to
+// (a > ?) OR (a = ? AND b < ?) OR ...
+func keysetCondition(keys []sortKey, values []any, backward bool) (string, []any) {
+	operator := func(key sortKey) string {
+		if key.desc != backward {
+			return "<"
+		}
+		return ">"
+	}
+
+	uniform := true
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.column
+		uniform = uniform && key.desc == keys[0].desc
+	}
+	if uniform {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		return "(" + strings.Join(columns, ", ") + ") " + operator(keys[0]) + " (" + placeholders + ")", values
+	}
+
+	parts := make([]string, len(keys))
+	args := []any{}
+	for i, key := range keys {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, keys[j].column+" = ?")
+			args = append(args, values[j])
+		}
+		conditions = append(conditions, key.column+" "+operator(key)+" ?")
+		args = append(args, values[i])
+		parts[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+	return strings.Join(parts, " OR "), args
+}
+
+// keysetPage reads the page of dbQuery after the encoded cursor, or before it when the cursor
+// points backward, the first page when there is no cursor. One row more than the limit is read
+// to know whether the listing goes on in that direction.
+func keysetPage[G any](dbQuery *gorm.DB, keys []sortKey, encoded string, limit int) ([]G, string, string, *models.SystemError) {
+	fingerprint := keysFingerprint(keys)
+	var position cursor
+	if encoded != "" {
+		var sysErr *models.SystemError
+		position, sysErr = decodeCursor(encoded, keys)
+		if sysErr != nil {
+			return nil, "", "", sysErr
+		}
+		condition, args := keysetCondition(keys, position.Values, position.Backward)
+		dbQuery = dbQuery.Where(condition, args...)
+	}
+
+	var rows []G
+	result := orderByKeys(dbQuery, keys, position.Backward).Limit(limit + 1).Find(&rows)
+	if result.Error != nil {
+		return nil, "", "", models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+	if position.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, "", "", nil
+	}
+
+	// a page read forward has rows before it when it continues a cursor, and the other way round
+	hasNext, hasPrev := more, encoded != ""
+	if position.Backward {
+		hasNext, hasPrev = true, more
+	}
+	var next, prev string
+	if hasNext {
+		c, sysErr := rowCursor(result.Statement.Context, result.Statement.Schema, keys, rows[len(rows)-1], fingerprint, false)
+		if sysErr != nil {
+			return nil, "", "", sysErr
+		}
+		next = c
+	}
+	if hasPrev {
+		c, sysErr := rowCursor(result.Statement.Context, result.Statement.Schema, keys, rows[0], fingerprint, true)
+		if sysErr != nil {
+			return nil, "", "", sysErr
+		}
+		prev = c
+	}
+	return rows, next, prev, nil
+}
+
+// rowCursor encodes the position of row, read from its columns through the schema of the table
+func rowCursor(ctx context.Context, tableSchema *schema.Schema, keys []sortKey, row any, fingerprint string, backward bool) (string, *models.SystemError) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		field := tableSchema.LookUpField(key.column)
+		if field == nil {
+			return "", models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Cursor failed", struct{}{})
+		}
+		values[i], _ = field.ValueOf(ctx, reflect.ValueOf(row))
+	}
+	return encodeCursor(cursor{Sort: fingerprint, Values: values, Backward: backward})
+}
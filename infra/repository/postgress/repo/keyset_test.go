@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+)
+
+func TestKeysetConditionFollowsTheSort(t *testing.T) {
+	keys := []sortKey{{column: "created_at", desc: true}, {column: "id", desc: true}}
+	condition, args := keysetCondition(keys, []any{"2026-01-01T00:00:00Z", "a"}, false)
+	if condition != "(created_at, id) < (?, ?)" || !reflect.DeepEqual(args, []any{"2026-01-01T00:00:00Z", "a"}) {
+		t.Fatalf("Expected a row comparison, got %q %v", condition, args)
+	}
+	condition, _ = keysetCondition(keys, []any{"2026-01-01T00:00:00Z", "a"}, true)
+	if condition != "(created_at, id) > (?, ?)" {
+		t.Fatalf("Expected the rows before the cursor, got %q", condition)
+	}
+
+	keys = []sortKey{{column: "name"}, {column: "age", desc: true}, {column: "id"}}
+	condition, args = keysetCondition(keys, []any{"Ana", int64(30), "a"}, false)
+	expected := "(name > ?) OR (name = ? AND age < ?) OR (name = ? AND age = ? AND id > ?)"
+	if condition != expected {
+		t.Fatalf("Expected %q, got %q", expected, condition)
+	}
+	expectedArgs := []any{"Ana", "Ana", int64(30), "Ana", int64(30), "a"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestCursorsContinueTheirOwnSort(t *testing.T) {
+	keys, err := sortKeys(testSearchFields, models.SortOrder{{Field: "Name"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	encoded, err := encodeCursor(cursor{Sort: keysFingerprint(keys), Values: []any{"Ana", int64(42)}, Backward: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := decodeCursor(encoded, keys)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !decoded.Backward || !reflect.DeepEqual(decoded.Values, []any{"Ana", int64(42)}) {
+		t.Fatalf("Expected the cursor back, got %+v", decoded)
+	}
+
+	other, _ := sortKeys(testSearchFields, models.SortOrder{{Field: "Name", Direction: models.SortDesc}})
+	if _, err := decodeCursor(encoded, other); err == nil {
+		t.Fatal("Expected a cursor of another sort to be refused")
+	}
+	tampered, _ := encodeCursor(cursor{Sort: keysFingerprint(keys), Values: []any{map[string]any{"a": 1}, "x"}})
+	for _, invalid := range []string{"not a cursor", "e30", tampered} {
+		if _, err := decodeCursor(invalid, keys); err == nil {
+			t.Errorf("Expected %q to be refused", invalid)
+		}
+	}
+}
+
+const benchmarkAttendanceEvents = 50000
+
+// BenchmarkAttendancePages reads a page deep into the attendance of an employee with offsets
+// and with cursors, against the database of HRMS_TEST_DATABASE_URL
+func BenchmarkAttendancePages(b *testing.B) {
+	db := openTestDB(b, &UserGorm{}, &DepartmentGorm{}, &PositionGorm{}, &EmployeeGorm{}, &AttendanceEventGorm{})
+
+	suffix := uuid.NewString()
+	employee := EmployeeGorm{ID: uuid.New(), EmployeeNumber: "bench_" + suffix, FirstName: "Bench", LastName: "Mark", NationalID: "bench_" + suffix, HireDate: time.Now()}
+	if err := db.Create(&employee).Error; err != nil {
+		b.Fatalf("Failed to create employee: %v", err)
+	}
+	b.Cleanup(func() {
+		db.Where("employee_id = ?", employee.ID).Delete(&AttendanceEventGorm{})
+		db.Unscoped().Delete(&employee)
+	})
+	events := make([]AttendanceEventGorm, benchmarkAttendanceEvents)
+	start := time.Now().Add(-benchmarkAttendanceEvents * time.Minute)
+	for i := range events {
+		events[i] = AttendanceEventGorm{ID: uuid.New(), EmployeeID: employee.ID, Type: "in", Source: "web", Timestamp: start.Add(time.Duration(i) * time.Minute)}
+	}
+	if err := db.CreateInBatches(events, 1000).Error; err != nil {
+		b.Fatalf("Failed to create events: %v", err)
+	}
+	// the estimates follow the statistics of the table
+	db.Exec("ANALYZE attendance_events")
+
+	repository := NewAttendanceRepository(db)
+	const limit, page = 50, 800
+	query := func(pagination models.Pagination) models.SearchQuery {
+		return models.SearchQuery{
+			Filters:    models.Filters{{Key: "EmployeeID", Value: employee.ID.String()}},
+			Sort:       models.SortOrder{{Field: "Timestamp", Direction: models.SortDesc}},
+			Pagination: pagination,
+		}
+	}
+
+	// walking to the page with cursors also checks both modes list the same rows
+	pagination := models.Pagination{Mode: models.PaginationModeCursor, Limit: limit, Count: models.CountNone}
+	for i := 1; i < page; i++ {
+		result, sysErr := repository.GetByFilter(query(pagination))
+		if sysErr != nil {
+			b.Fatalf("Failed to read page %d: %v", i, sysErr.Message)
+		}
+		pagination.Cursor = result.NextCursor
+	}
+	byCursor, sysErr := repository.GetByFilter(query(pagination))
+	if sysErr != nil {
+		b.Fatalf("Failed to read page: %v", sysErr.Message)
+	}
+	byOffset, sysErr := repository.GetByFilter(query(models.Pagination{Page: page, Limit: limit}))
+	if sysErr != nil {
+		b.Fatalf("Failed to read page: %v", sysErr.Message)
+	}
+	if !reflect.DeepEqual(byCursor.Rows, byOffset.Rows) {
+		b.Fatalf("Expected page %d to hold the same rows with offsets and cursors", page)
+	}
+
+	for _, mode := range []struct {
+		name       string
+		pagination models.Pagination
+	}{
+		{"offset/count=exact", models.Pagination{Page: page, Limit: limit}},
+		{"offset/count=none", models.Pagination{Page: page, Limit: limit, Count: models.CountNone}},
+		{"cursor/count=estimate", models.Pagination{Mode: models.PaginationModeCursor, Cursor: pagination.Cursor, Limit: limit, Count: models.CountEstimate}},
+		{"cursor/count=none", pagination},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, sysErr := repository.GetByFilter(query(mode.pagination)); sysErr != nil {
+					b.Fatalf("Failed to read page %d: %v", page, sysErr.Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LeaveTypeGorm struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Code             string    `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Name             string    `gorm:"type:varchar(255);not null"`
+	Description      string    `gorm:"type:text"`
+	Category         string    `gorm:"type:varchar(20);not null"`
+	Paid             bool      `gorm:"type:boolean;not null;default:false"`
+	AccrualFrequency string    `gorm:"type:varchar(20);not null;default:'none'"`
+	AccrualDays      float64   `gorm:"type:numeric(6,2);not null;default:0"`
+	MaxBalance       float64   `gorm:"type:numeric(6,2);not null;default:0"`
+	Active           bool      `gorm:"type:boolean;not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+}
+
+func (LeaveTypeGorm) TableName() string {
+	return "leave_types"
+}
+
+func (l LeaveTypeGorm) ToModel() models.LeaveType {
+	return models.LeaveType{
+		ID:               fromGUIDToString(l.ID),
+		Code:             l.Code,
+		Name:             l.Name,
+		Description:      l.Description,
+		Category:         models.LeaveCategory(l.Category),
+		Paid:             l.Paid,
+		AccrualFrequency: models.AccrualFrequency(l.AccrualFrequency),
+		AccrualDays:      l.AccrualDays,
+		MaxBalance:       l.MaxBalance,
+		Active:           l.Active,
+	}
+}
+
+func LeaveTypeToEntity(l models.LeaveType) LeaveTypeGorm {
+	id, _ := uuid.Parse(l.ID)
+	return LeaveTypeGorm{
+		ID:               id,
+		Code:             l.Code,
+		Name:             l.Name,
+		Description:      l.Description,
+		Category:         string(l.Category),
+		Paid:             l.Paid,
+		AccrualFrequency: string(l.AccrualFrequency),
+		AccrualDays:      l.AccrualDays,
+		MaxBalance:       l.MaxBalance,
+		Active:           l.Active,
+	}
+}
+
+type LeaveTypeRepository struct {
+	GenericCrud[models.LeaveType, LeaveTypeGorm]
+}
+
+func NewLeaveTypeRepository(db *gorm.DB) contracts.LeaveTypeContract {
+	return &LeaveTypeRepository{
+		GenericCrud: NewGenericCrud(db, LeaveTypeToEntity, (LeaveTypeGorm).ToModel),
+	}
+}
+
+type LeaveRequestGorm struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EmployeeID   uuid.UUID      `gorm:"type:uuid;not null;index:idx_leave_requests_employee_dates,priority:1"`
+	Employee     *EmployeeGorm  `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	LeaveTypeID  uuid.UUID      `gorm:"type:uuid;not null;index"`
+	LeaveType    *LeaveTypeGorm `gorm:"foreignKey:LeaveTypeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	StartDate    time.Time      `gorm:"type:date;not null;index:idx_leave_requests_employee_dates,priority:2"`
+	EndDate      time.Time      `gorm:"type:date;not null"`
+	Days         float64        `gorm:"type:numeric(6,2);not null"`
+	Reason       string         `gorm:"type:text"`
+	Status       string         `gorm:"type:varchar(20);not null;default:'pending';index"`
+	ApproverID   *uuid.UUID     `gorm:"type:uuid"`
+	Approver     *EmployeeGorm  `gorm:"foreignKey:ApproverID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	DecisionNote string         `gorm:"type:text"`
+	DecidedAt    *time.Time     `gorm:"type:timestamptz"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (LeaveRequestGorm) TableName() string {
+	return "leave_requests"
+}
+
+func (l LeaveRequestGorm) ToModel() models.LeaveRequest {
+	return models.LeaveRequest{
+		ID:           fromGUIDToString(l.ID),
+		EmployeeID:   fromGUIDToString(l.EmployeeID),
+		LeaveTypeID:  fromGUIDToString(l.LeaveTypeID),
+		StartDate:    l.StartDate,
+		EndDate:      l.EndDate,
+		Days:         l.Days,
+		Reason:       l.Reason,
+		Status:       models.LeaveStatus(l.Status),
+		ApproverID:   fromNullableGUID(l.ApproverID),
+		DecisionNote: l.DecisionNote,
+		DecidedAt:    l.DecidedAt,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func LeaveRequestToEntity(l models.LeaveRequest) LeaveRequestGorm {
+	id, _ := uuid.Parse(l.ID)
+	employeeID, _ := uuid.Parse(l.EmployeeID)
+	leaveTypeID, _ := uuid.Parse(l.LeaveTypeID)
+	return LeaveRequestGorm{
+		ID:           id,
+		EmployeeID:   employeeID,
+		LeaveTypeID:  leaveTypeID,
+		StartDate:    l.StartDate,
+		EndDate:      l.EndDate,
+		Days:         l.Days,
+		Reason:       l.Reason,
+		Status:       string(l.Status),
+		ApproverID:   toNullableGUID(l.ApproverID),
+		DecisionNote: l.DecisionNote,
+		DecidedAt:    l.DecidedAt,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+type LeaveRequestRepository struct {
+	GenericCrud[models.LeaveRequest, LeaveRequestGorm]
+	db *gorm.DB
+}
+
+func NewLeaveRequestRepository(db *gorm.DB) contracts.LeaveRequestContract {
+	return &LeaveRequestRepository{
+		GenericCrud: NewGenericCrud(db, LeaveRequestToEntity, (LeaveRequestGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *LeaveRequestRepository) GetOverlapping(employeeID string, start, end time.Time, statuses []models.LeaveStatus) ([]models.LeaveRequest, *models.SystemError) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+
+	var gormModels []LeaveRequestGorm
+	err := r.db.WithContext(r.currentContext()).
+		Where("employee_id = ? AND start_date <= ? AND end_date >= ?", employeeID, end, start).
+		Where("status IN ?", values).
+		Order("start_date").
+		Find(&gormModels).Error
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+
+	requests := make([]models.LeaveRequest, len(gormModels))
+	for i, gm := range gormModels {
+		requests[i] = gm.ToModel()
+	}
+	return requests, nil
+}
+
+// Transition only writes the status and the decision, a request decided or cancelled meanwhile is not changed
+func (r *LeaveRequestRepository) Transition(request models.LeaveRequest, from models.LeaveStatus) (models.LeaveRequest, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).Model(&LeaveRequestGorm{}).
+		Where("id = ? AND status = ?", request.ID, string(from)).
+		Updates(map[string]any{
+			"status":        string(request.Status),
+			"approver_id":   toNullableGUID(request.ApproverID),
+			"decision_note": request.DecisionNote,
+			"decided_at":    request.DecidedAt,
+		})
+	if result.Error != nil {
+		return request, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Update failed", struct{}{})
+	}
+	if result.RowsAffected == 0 {
+		return request, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the leave request is no longer "+string(from), struct{}{})
+	}
+	updated, sysErr := r.GetOnce("id", request.ID)
+	if sysErr != nil {
+		return request, sysErr
+	}
+	return *updated, nil
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryGorm struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	User         *UserGorm `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time `gorm:"type:timestamptz;not null"`
+}
+
+func (PasswordHistoryGorm) TableName() string {
+	return "password_histories"
+}
+
+func (p PasswordHistoryGorm) ToModel() models.PasswordHistory {
+	return models.PasswordHistory{
+		ID:           fromGUIDToString(p.ID),
+		UserID:       fromGUIDToString(p.UserID),
+		PasswordHash: p.PasswordHash,
+		CreatedAt:    p.CreatedAt,
+	}
+}
+
+func PasswordHistoryToEntity(p models.PasswordHistory) PasswordHistoryGorm {
+	id, _ := uuid.Parse(p.ID)
+	userID, _ := uuid.Parse(p.UserID)
+	return PasswordHistoryGorm{
+		ID:           id,
+		UserID:       userID,
+		PasswordHash: p.PasswordHash,
+		CreatedAt:    p.CreatedAt,
+	}
+}
+
+type PasswordHistoryRepository struct {
+	GenericCrud[models.PasswordHistory, PasswordHistoryGorm]
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) contracts.PasswordHistoryContract {
+	return &PasswordHistoryRepository{
+		GenericCrud: NewGenericCrud(db, PasswordHistoryToEntity, (PasswordHistoryGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *PasswordHistoryRepository) Recent(userID string, limit int) ([]string, *models.SystemError) {
+	var hashes []string
+	err := r.db.WithContext(r.currentContext()).
+		Model(&PasswordHistoryGorm{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to read password history", struct{}{})
+	}
+	return hashes, nil
+}
+
+func (r *PasswordHistoryRepository) Record(userID string, hash string, keep int) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		entity := PasswordHistoryToEntity(models.PasswordHistory{UserID: userID, PasswordHash: hash, CreatedAt: time.Now().UTC()})
+		if err := tx.Create(&entity).Error; err != nil {
+			return err
+		}
+		kept := tx.Model(&PasswordHistoryGorm{}).
+			Select("id").
+			Where("user_id = ?", userID).
+			Order("created_at DESC").
+			Limit(keep)
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, kept).Delete(&PasswordHistoryGorm{}).Error
+	})
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to record password history", struct{}{})
+	}
+	return nil
+}
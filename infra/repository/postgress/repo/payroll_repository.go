@@ -0,0 +1,325 @@
+package repo
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PayPeriodGorm struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	StartDate time.Time `gorm:"type:date;not null;uniqueIndex"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	PayDate   time.Time `gorm:"type:date;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (PayPeriodGorm) TableName() string {
+	return "pay_periods"
+}
+
+func (p PayPeriodGorm) ToModel() models.PayPeriod {
+	return models.PayPeriod{
+		ID:        fromGUIDToString(p.ID),
+		Name:      p.Name,
+		StartDate: p.StartDate,
+		EndDate:   p.EndDate,
+		PayDate:   p.PayDate,
+	}
+}
+
+func PayPeriodToEntity(p models.PayPeriod) PayPeriodGorm {
+	id, _ := uuid.Parse(p.ID)
+	return PayPeriodGorm{
+		ID:        id,
+		Name:      p.Name,
+		StartDate: p.StartDate,
+		EndDate:   p.EndDate,
+		PayDate:   p.PayDate,
+	}
+}
+
+type PayPeriodRepository struct {
+	GenericCrud[models.PayPeriod, PayPeriodGorm]
+	db *gorm.DB
+}
+
+func NewPayPeriodRepository(db *gorm.DB) contracts.PayPeriodContract {
+	return &PayPeriodRepository{
+		GenericCrud: NewGenericCrud(db, PayPeriodToEntity, (PayPeriodGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *PayPeriodRepository) GetOverlapping(start, end time.Time) ([]models.PayPeriod, *models.SystemError) {
+	var gormModels []PayPeriodGorm
+	err := r.db.WithContext(r.currentContext()).
+		Where("start_date <= ? AND end_date >= ?", end, start).
+		Order("start_date").
+		Find(&gormModels).Error
+	if err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+
+	periods := make([]models.PayPeriod, len(gormModels))
+	for i, gm := range gormModels {
+		periods[i] = gm.ToModel()
+	}
+	return periods, nil
+}
+
+type PayrollRunGorm struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	PayPeriodID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex"`
+	PayPeriod       *PayPeriodGorm `gorm:"foreignKey:PayPeriodID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Status          string         `gorm:"type:varchar(20);not null;default:'draft';index"`
+	EmployeeCount   int            `gorm:"not null;default:0"`
+	TotalGross      float64        `gorm:"type:numeric(16,2);not null;default:0"`
+	TotalDeductions float64        `gorm:"type:numeric(16,2);not null;default:0"`
+	TotalNet        float64        `gorm:"type:numeric(16,2);not null;default:0"`
+	CalculatedAt    time.Time      `gorm:"type:timestamptz;not null"`
+	FinalizedAt     *time.Time     `gorm:"type:timestamptz"`
+	PaidAt          *time.Time     `gorm:"type:timestamptz"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (PayrollRunGorm) TableName() string {
+	return "payroll_runs"
+}
+
+func (p PayrollRunGorm) ToModel() models.PayrollRun {
+	return models.PayrollRun{
+		ID:              fromGUIDToString(p.ID),
+		PayPeriodID:     fromGUIDToString(p.PayPeriodID),
+		Status:          models.PayrollStatus(p.Status),
+		EmployeeCount:   p.EmployeeCount,
+		TotalGross:      p.TotalGross,
+		TotalDeductions: p.TotalDeductions,
+		TotalNet:        p.TotalNet,
+		CalculatedAt:    p.CalculatedAt,
+		FinalizedAt:     p.FinalizedAt,
+		PaidAt:          p.PaidAt,
+	}
+}
+
+func PayrollRunToEntity(p models.PayrollRun) PayrollRunGorm {
+	id, _ := uuid.Parse(p.ID)
+	periodID, _ := uuid.Parse(p.PayPeriodID)
+	return PayrollRunGorm{
+		ID:              id,
+		PayPeriodID:     periodID,
+		Status:          string(p.Status),
+		EmployeeCount:   p.EmployeeCount,
+		TotalGross:      p.TotalGross,
+		TotalDeductions: p.TotalDeductions,
+		TotalNet:        p.TotalNet,
+		CalculatedAt:    p.CalculatedAt,
+		FinalizedAt:     p.FinalizedAt,
+		PaidAt:          p.PaidAt,
+	}
+}
+
+type PayrollRunRepository struct {
+	GenericCrud[models.PayrollRun, PayrollRunGorm]
+	db *gorm.DB
+}
+
+func NewPayrollRunRepository(db *gorm.DB) contracts.PayrollRunContract {
+	return &PayrollRunRepository{
+		GenericCrud: NewGenericCrud(db, PayrollRunToEntity, (PayrollRunGorm).ToModel),
+		db:          db,
+	}
+}
+
+// errRunNotDraft reports a run finalized or paid while it was being calculated or moved
+var errRunNotDraft = errors.New("the payroll run changed its status")
+
+// SaveCalculation locks the run, so a status change waits for the calculation to be stored
+func (r *PayrollRunRepository) SaveCalculation(run models.PayrollRun, payslips []models.Payslip) (models.PayrollRun, *models.SystemError) {
+	entity := PayrollRunToEntity(run)
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		if run.ID == "" {
+			entity.ID = uuid.New()
+			if err := tx.Create(&entity).Error; err != nil {
+				return err
+			}
+		} else {
+			var current PayrollRunGorm
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", entity.ID).First(&current).Error; err != nil {
+				return err
+			}
+			if current.Status != string(models.PayrollStatusDraft) {
+				return errRunNotDraft
+			}
+			if err := tx.Where("payroll_run_id = ?", entity.ID).Delete(&PayslipGorm{}).Error; err != nil {
+				return err
+			}
+			// a map so empty runs store their zero totals
+			if err := tx.Model(&PayrollRunGorm{}).Where("id = ?", entity.ID).Updates(map[string]any{
+				"employee_count":   entity.EmployeeCount,
+				"total_gross":      entity.TotalGross,
+				"total_deductions": entity.TotalDeductions,
+				"total_net":        entity.TotalNet,
+				"calculated_at":    entity.CalculatedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if len(payslips) == 0 {
+			return nil
+		}
+		rows := make([]PayslipGorm, len(payslips))
+		for i, payslip := range payslips {
+			payslip.PayrollRunID = entity.ID.String()
+			rows[i] = PayslipToEntity(payslip)
+		}
+		return tx.CreateInBatches(rows, 100).Error
+	})
+	if errors.Is(err, errRunNotDraft) {
+		return run, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the payroll run is no longer a draft and cannot be recalculated", struct{}{})
+	}
+	if err != nil {
+		return run, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to save the payroll run", struct{}{})
+	}
+	saved, sysErr := r.GetOnce("id", entity.ID)
+	if sysErr != nil {
+		return run, sysErr
+	}
+	return *saved, nil
+}
+
+// ChangeStatus only writes the status and its dates, the totals stay those of the last calculation
+func (r *PayrollRunRepository) ChangeStatus(run models.PayrollRun, from models.PayrollStatus) (models.PayrollRun, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).Model(&PayrollRunGorm{}).
+		Where("id = ? AND status = ?", run.ID, string(from)).
+		Updates(map[string]any{
+			"status":       string(run.Status),
+			"finalized_at": run.FinalizedAt,
+			"paid_at":      run.PaidAt,
+		})
+	if result.Error != nil {
+		return run, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Update failed", struct{}{})
+	}
+	if result.RowsAffected == 0 {
+		return run, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the payroll run is no longer "+string(from), struct{}{})
+	}
+	updated, sysErr := r.GetOnce("id", run.ID)
+	if sysErr != nil {
+		return run, sysErr
+	}
+	return *updated, nil
+}
+
+// DeleteDraft removes the run only while it is a draft, a run finalized meanwhile keeps its payslips
+func (r *PayrollRunRepository) DeleteDraft(id string) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", id, string(models.PayrollStatusDraft)).Delete(&PayrollRunGorm{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRunNotDraft
+		}
+		return tx.Where("payroll_run_id = ?", id).Delete(&PayslipGorm{}).Error
+	})
+	if errors.Is(err, errRunNotDraft) {
+		return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "the payroll run is no longer a draft and cannot be deleted", struct{}{})
+	}
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to delete the payroll run", struct{}{})
+	}
+	return nil
+}
+
+type PayslipGorm struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	PayrollRunID    uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_payslips_run_employee,priority:1"`
+	PayrollRun      *PayrollRunGorm `gorm:"foreignKey:PayrollRunID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PayPeriodID     uuid.UUID       `gorm:"type:uuid;not null;index"`
+	EmployeeID      uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_payslips_run_employee,priority:2;index"`
+	Employee        *EmployeeGorm   `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	PositionID      *uuid.UUID      `gorm:"type:uuid"`
+	Currency        string          `gorm:"type:varchar(3);not null"`
+	BaseSalary      float64         `gorm:"type:numeric(14,2);not null"`
+	WorkedDays      int             `gorm:"not null"`
+	OvertimeMinutes int             `gorm:"not null;default:0"`
+	GrossPay        float64         `gorm:"type:numeric(14,2);not null"`
+	TotalDeductions float64         `gorm:"type:numeric(14,2);not null"`
+	NetPay          float64         `gorm:"type:numeric(14,2);not null"`
+	// Earning and deduction lines serialized as JSON
+	Lines     string `gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (PayslipGorm) TableName() string {
+	return "payslips"
+}
+
+func (p PayslipGorm) ToModel() models.Payslip {
+	var lines []models.PayslipLine
+	_ = json.Unmarshal([]byte(p.Lines), &lines)
+	return models.Payslip{
+		ID:              fromGUIDToString(p.ID),
+		PayrollRunID:    fromGUIDToString(p.PayrollRunID),
+		PayPeriodID:     fromGUIDToString(p.PayPeriodID),
+		EmployeeID:      fromGUIDToString(p.EmployeeID),
+		PositionID:      fromNullableGUID(p.PositionID),
+		Currency:        p.Currency,
+		BaseSalary:      p.BaseSalary,
+		WorkedDays:      p.WorkedDays,
+		OvertimeMinutes: p.OvertimeMinutes,
+		GrossPay:        p.GrossPay,
+		TotalDeductions: p.TotalDeductions,
+		NetPay:          p.NetPay,
+		Lines:           lines,
+	}
+}
+
+func PayslipToEntity(p models.Payslip) PayslipGorm {
+	id, _ := uuid.Parse(p.ID)
+	runID, _ := uuid.Parse(p.PayrollRunID)
+	periodID, _ := uuid.Parse(p.PayPeriodID)
+	employeeID, _ := uuid.Parse(p.EmployeeID)
+	lines, err := json.Marshal(p.Lines)
+	if err != nil || p.Lines == nil {
+		lines = []byte("[]")
+	}
+	return PayslipGorm{
+		ID:              id,
+		PayrollRunID:    runID,
+		PayPeriodID:     periodID,
+		EmployeeID:      employeeID,
+		PositionID:      toNullableGUID(p.PositionID),
+		Currency:        p.Currency,
+		BaseSalary:      p.BaseSalary,
+		WorkedDays:      p.WorkedDays,
+		OvertimeMinutes: p.OvertimeMinutes,
+		GrossPay:        p.GrossPay,
+		TotalDeductions: p.TotalDeductions,
+		NetPay:          p.NetPay,
+		Lines:           string(lines),
+	}
+}
+
+type PayslipRepository struct {
+	GenericCrud[models.Payslip, PayslipGorm]
+	db *gorm.DB
+}
+
+func NewPayslipRepository(db *gorm.DB) contracts.PayslipContract {
+	return &PayslipRepository{
+		GenericCrud: NewGenericCrud(db, PayslipToEntity, (PayslipGorm).ToModel),
+		db:          db,
+	}
+}
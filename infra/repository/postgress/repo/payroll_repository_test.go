@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+)
+
+// TestSaveCalculationReplacesADraftOnly covers DeleteDraft too, it runs against the database of HRMS_TEST_DATABASE_URL
+func TestSaveCalculationReplacesADraftOnly(t *testing.T) {
+	db := openTestDB(t, &UserGorm{}, &DepartmentGorm{}, &PositionGorm{}, &EmployeeGorm{}, &PayPeriodGorm{}, &PayrollRunGorm{}, &PayslipGorm{})
+
+	suffix := uuid.NewString()[:8]
+	// periods are unique by start date, a random past month keeps runs of the test apart
+	start := time.Date(1000+rand.Intn(800), time.Month(1+rand.Intn(12)), 1, 0, 0, 0, 0, time.UTC)
+	period := PayPeriodGorm{ID: uuid.New(), Name: "Period " + suffix, StartDate: start, EndDate: start.AddDate(0, 1, -1), PayDate: start.AddDate(0, 1, -1)}
+	employees := []EmployeeGorm{
+		{ID: uuid.New(), EmployeeNumber: "P1" + suffix, FirstName: "Ana", LastName: "Pay", NationalID: "P1" + suffix, HireDate: start},
+		{ID: uuid.New(), EmployeeNumber: "P2" + suffix, FirstName: "Luis", LastName: "Pay", NationalID: "P2" + suffix, HireDate: start},
+	}
+	if err := db.Create(&period).Error; err != nil {
+		t.Fatalf("Failed to create period: %v", err)
+	}
+	if err := db.Create(&employees).Error; err != nil {
+		t.Fatalf("Failed to create employees: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("pay_period_id = ?", period.ID).Delete(&PayrollRunGorm{})
+		db.Unscoped().Delete(&employees)
+		db.Delete(&period)
+	})
+
+	repository := NewPayrollRunRepository(db)
+	payslip := func(employee EmployeeGorm, gross float64) models.Payslip {
+		return models.Payslip{PayPeriodID: period.ID.String(), EmployeeID: employee.ID.String(), Currency: "USD", GrossPay: gross, NetPay: gross}
+	}
+	run, sysErr := repository.SaveCalculation(models.PayrollRun{PayPeriodID: period.ID.String(), Status: models.PayrollStatusDraft, EmployeeCount: 2, TotalGross: 300, TotalNet: 300, CalculatedAt: time.Now()},
+		[]models.Payslip{payslip(employees[0], 100), payslip(employees[1], 200)})
+	if sysErr != nil {
+		t.Fatalf("Failed to save the run: %v", sysErr.Message)
+	}
+
+	run.EmployeeCount, run.TotalGross, run.TotalNet = 1, 50, 50
+	run, sysErr = repository.SaveCalculation(run, []models.Payslip{payslip(employees[0], 50)})
+	if sysErr != nil {
+		t.Fatalf("Failed to recalculate the run: %v", sysErr.Message)
+	}
+	var count int64
+	db.Model(&PayslipGorm{}).Where("payroll_run_id = ?", run.ID).Count(&count)
+	if count != 1 || run.TotalGross != 50 {
+		t.Fatalf("Expected the payslips and totals replaced, got %d payslips and gross %v", count, run.TotalGross)
+	}
+
+	finalized := run
+	finalized.Status = models.PayrollStatusFinalized
+	if _, sysErr := repository.ChangeStatus(finalized, models.PayrollStatusDraft); sysErr != nil {
+		t.Fatalf("Failed to finalize the run: %v", sysErr.Message)
+	}
+	if _, sysErr := repository.ChangeStatus(finalized, models.PayrollStatusDraft); sysErr == nil {
+		t.Fatal("Expected a run no longer in draft not to change")
+	}
+	run.TotalGross = 0
+	if _, sysErr := repository.SaveCalculation(run, nil); sysErr == nil {
+		t.Fatal("Expected a finalized run not to be recalculated")
+	}
+	db.Model(&PayslipGorm{}).Where("payroll_run_id = ?", run.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("Expected the payslips of the finalized run kept, got %d", count)
+	}
+	if sysErr := repository.DeleteDraft(run.ID); sysErr == nil {
+		t.Fatal("Expected a finalized run not to be deleted")
+	}
+	db.Model(&PayslipGorm{}).Where("payroll_run_id = ?", run.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("Expected the payslips of the finalized run kept, got %d", count)
+	}
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PositionGorm struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name         string          `gorm:"type:varchar(255);not null"`
+	Description  string          `gorm:"type:text"`
+	DepartmentID uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Department   *DepartmentGorm `gorm:"foreignKey:DepartmentID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	MinSalary    float64         `gorm:"type:numeric(14,2);not null;default:0"`
+	MaxSalary    float64         `gorm:"type:numeric(14,2);not null;default:0"`
+	Currency     string          `gorm:"type:char(3);not null"`
+	Headcount    int             `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (PositionGorm) TableName() string {
+	return "positions"
+}
+
+func (p PositionGorm) ToModel() models.Position {
+	return models.Position{
+		ID:           fromGUIDToString(p.ID),
+		Name:         p.Name,
+		Description:  p.Description,
+		DepartmentID: fromGUIDToString(p.DepartmentID),
+		MinSalary:    p.MinSalary,
+		MaxSalary:    p.MaxSalary,
+		Currency:     p.Currency,
+		Headcount:    p.Headcount,
+	}
+}
+
+func PositionToEntity(p models.Position) PositionGorm {
+	id, _ := uuid.Parse(p.ID)
+	departmentID, _ := uuid.Parse(p.DepartmentID)
+	return PositionGorm{
+		ID:           id,
+		Name:         p.Name,
+		Description:  p.Description,
+		DepartmentID: departmentID,
+		MinSalary:    p.MinSalary,
+		MaxSalary:    p.MaxSalary,
+		Currency:     strings.ToUpper(p.Currency),
+		Headcount:    p.Headcount,
+	}
+}
+
+type PositionRepository struct {
+	GenericCrud[models.Position, PositionGorm]
+}
+
+func NewPositionRepository(db *gorm.DB) contracts.PositionContract {
+	return &PositionRepository{
+		GenericCrud: NewGenericCrud(db, PositionToEntity, (PositionGorm).ToModel),
+	}
+}
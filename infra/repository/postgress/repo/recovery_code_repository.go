@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeGorm struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	User      *UserGorm  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CodeHash  string     `gorm:"type:varchar(255);not null"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time  `gorm:"type:timestamptz;not null"`
+}
+
+func (RecoveryCodeGorm) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+func (r RecoveryCodeGorm) ToModel() models.RecoveryCode {
+	return models.RecoveryCode{
+		ID:        fromGUIDToString(r.ID),
+		UserID:    fromGUIDToString(r.UserID),
+		CodeHash:  r.CodeHash,
+		UsedAt:    r.UsedAt,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func RecoveryCodeToEntity(r models.RecoveryCode) RecoveryCodeGorm {
+	id, _ := uuid.Parse(r.ID)
+	userID, _ := uuid.Parse(r.UserID)
+	return RecoveryCodeGorm{
+		ID:        id,
+		UserID:    userID,
+		CodeHash:  r.CodeHash,
+		UsedAt:    r.UsedAt,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type RecoveryCodeRepository struct {
+	GenericCrud[models.RecoveryCode, RecoveryCodeGorm]
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) contracts.RecoveryCodeContract {
+	return &RecoveryCodeRepository{
+		GenericCrud: NewGenericCrud(db, RecoveryCodeToEntity, (RecoveryCodeGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *RecoveryCodeRepository) Replace(userID string, hashes []string) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeGorm{}).Error; err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		codes := make([]RecoveryCodeGorm, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = RecoveryCodeToEntity(models.RecoveryCode{UserID: userID, CodeHash: hash, CreatedAt: now})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to store recovery codes", struct{}{})
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepository) Unused(userID string) ([]models.RecoveryCode, *models.SystemError) {
+	var codes []RecoveryCodeGorm
+	if err := r.db.WithContext(r.currentContext()).Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error; err != nil {
+		return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to read recovery codes", struct{}{})
+	}
+	result := make([]models.RecoveryCode, len(codes))
+	for i, code := range codes {
+		result[i] = code.ToModel()
+	}
+	return result, nil
+}
+
+func (r *RecoveryCodeRepository) Use(id string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&RecoveryCodeGorm{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to use recovery code", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUser(userID string) *models.SystemError {
+	if err := r.db.WithContext(r.currentContext()).Where("user_id = ?", userID).Delete(&RecoveryCodeGorm{}).Error; err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to delete recovery codes", struct{}{})
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"errors"
+
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
 	gormModels "hrms.local/repository/postgress/models"
@@ -37,7 +39,7 @@ func (r *RoleRepository) GetPermissions(roleID string) ([]models.Permission, *mo
 // Override GetOnce to preload permissions
 func (r *RoleRepository) GetOnce(key string, value any) (*models.Role, *models.SystemError) {
 	var gormModel gormModels.RoleGorm
-	dbQuery := r.db.Preload("Permissions").Where(key+" = ?", value)
+	dbQuery := r.db.Preload("Permissions").Where(columnEquals(key, value))
 	if err := dbQuery.First(&gormModel).Error; err != nil {
 		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "GetOnce failed", struct{}{})
 	}
@@ -47,93 +49,97 @@ func (r *RoleRepository) GetOnce(key string, value any) (*models.Role, *models.S
 
 // Override GetByFilter to preload permissions
 func (r *RoleRepository) GetByFilter(query models.SearchQuery) (*models.PaginatedResponse[models.Role], *models.SystemError) {
-	// We need to implement Count separately or reuse generic count but here we override the whole method
-	totalRows, sysErr := r.GenericCrud.CountByFilter(query)
-	if sysErr != nil {
-		return nil, sysErr
-	}
-
-	var gormModels []gormModels.RoleGorm
-	dbQuery := r.db.Preload("Permissions")
-	for _, filter := range query.Filters {
-		dbQuery = dbQuery.Where(filter.Key+" = ?", filter.Value)
-	}
-
-	limit := query.Pagination.GetLimit()
-	dbQuery = dbQuery.Limit(limit).Offset(query.Pagination.GetOffset())
-
-	if err := dbQuery.Find(&gormModels).Error; err != nil {
-		return nil, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
-	}
-
-	var entities []models.Role
-	for _, gm := range gormModels {
-		entities = append(entities, gm.ToModel())
-	}
-
-	totalPages := 0
-	if limit > 0 {
-		totalPages = int((totalRows + int64(limit) - 1) / int64(limit))
-	}
-
-	return &models.PaginatedResponse[models.Role]{
-		TotalRows:  totalRows,
-		TotalPages: totalPages,
-		Rows:       entities,
-	}, nil
+	return r.getByFilter(r.db.WithContext(r.currentContext()).Preload("Permissions"), query)
 }
 
-// Override Create to ensure permissions are handled (though GORM usually handles this)
+// Override Create to link the role to existing permissions instead of inserting them
 func (r *RoleRepository) Create(item models.Role) (models.Role, *models.SystemError) {
-	// For Many-to-Many, we need to be careful not to duplicate permissions if we just pass them.
-	// We should probably strip permissions or ensure they only have IDs if they are existing permissions.
-	// But PermissionToEntity handles converting Model to Gorm.
-
-	// Issue: If we pass a Permission with ID, GORM might try to Insert it if we are not careful,
-	// unless we use Omit("Permissions") and then Association().Replace() or similar,
-	// OR if the PermissionGorm is set up correctly and the IDs exist.
-
-	// Use standard create but let's see.
-	// Safest for M2M with existing items is to use Association replace or ensure we are just linking.
-
 	gormModel := gormModels.RoleToEntity(item)
-
-	// Using Clause(clause.OnConflict{DoNothing: true}) for permissions? No, that's for the role itself.
-
-	if err := r.db.Create(&gormModel).Error; err != nil {
-		return item, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		permissions, err := findPermissions(tx, item.Permissions)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("Permissions").Create(&gormModel).Error; err != nil {
+			return err
+		}
+		return tx.Model(&gormModel).Association("Permissions").Replace(permissions)
+	})
+	if err != nil {
+		return item, permissionError(err, "Query failed")
 	}
-	// Reload to get properly populated fields if needed?
-	// Usually Create returns the created object.
-
-	return gormModel.ToModel(), nil
+	role, sysErr := r.GetOnce("id", gormModel.ID)
+	if sysErr != nil {
+		return item, sysErr
+	}
+	return *role, nil
 }
 
-// Override Update to handle permission association replacement
+// Override Update to replace the permissions granted by the role.
+// Only the rows of the role in role_permissions change, other roles keep their permissions.
 func (r *RoleRepository) Update(id string, item models.Role) (models.Role, *models.SystemError) {
 	gormModel := gormModels.RoleToEntity(item)
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		permissions, err := findPermissions(tx, item.Permissions)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&gormModel).Where("id = ?", id).Omit("Permissions").Updates(&gormModel).Error; err != nil {
+			return err
+		}
+		// Updates skips zero values, turning the requirement off needs its own update
+		if err := tx.Model(&gormModels.RoleGorm{}).Where("id = ?", id).Update("require_mfa", item.RequireMFA).Error; err != nil {
+			return err
+		}
+		return tx.Model(&gormModels.RoleGorm{ID: gormModel.ID}).Association("Permissions").Replace(permissions)
+	})
+	if err != nil {
+		return item, permissionError(err, "Update failed")
+	}
+
+	// Return updated model with permissions
+	role, sysErr := r.GetOnce("id", id)
+	if sysErr != nil {
+		return item, sysErr
+	}
+	return *role, nil
+}
 
-	// We should update the role fields AND the associations.
-	// Generic Update uses Save(), which might work but for M2M replacement we often need to be explicit.
+// errPermissionNotFound reports a permission of the role that does not exist
+type errPermissionNotFound struct {
+	name string
+}
 
-	// Transaction
-	tx := r.db.Begin()
+func (e errPermissionNotFound) Error() string {
+	return "Permission not found: " + e.name
+}
 
-	// 1. Update Role fields
-	if err := tx.Model(&gormModel).Where("id = ?", id).Omit("Permissions").Updates(&gormModel).Error; err != nil {
-		tx.Rollback()
-		return item, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Update failed", struct{}{})
+// findPermissions loads the permissions of a role by id, or by name when the id is missing
+func findPermissions(tx *gorm.DB, permissions []models.Permission) ([]gormModels.PermissionGorm, error) {
+	result := make([]gormModels.PermissionGorm, 0, len(permissions))
+	for _, permission := range permissions {
+		var found gormModels.PermissionGorm
+		query := tx.Where("name = ?", permission.Name)
+		key := permission.Name
+		if permission.ID != "" {
+			query = tx.Where("id = ?", permission.ID)
+			key = permission.ID
+		}
+		if err := query.First(&found).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errPermissionNotFound{name: key}
+			}
+			return nil, err
+		}
+		result = append(result, found)
 	}
+	return result, nil
+}
 
-	// 2. Replace Associations
-	if err := tx.Model(&gormModel).Association("Permissions").Replace(gormModel.Permissions); err != nil {
-		tx.Rollback()
-		return item, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Update associations failed", struct{}{})
+func permissionError(err error, message string) *models.SystemError {
+	var notFound errPermissionNotFound
+	if errors.As(err, &notFound) {
+		message = notFound.Error()
 	}
-
-	tx.Commit()
-
-	// Return updated model with permissions
-	role, _ := r.GetOnce("id", id)
-	return *role, nil
+	return models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, message, struct{}{})
 }
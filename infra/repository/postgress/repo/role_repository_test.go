@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"sync"
+	"testing"
+
+	"hrms.local/core/models"
+	gormModels "hrms.local/repository/postgress/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+func TestRolePermissionsAreManyToMany(t *testing.T) {
+	roleSchema, err := schema.Parse(&gormModels.RoleGorm{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	relation, ok := roleSchema.Relationships.Relations["Permissions"]
+	if !ok || relation.Type != schema.Many2Many {
+		t.Fatal("Expected roles and permissions to be many to many")
+	}
+	if relation.JoinTable.Name != "role_permissions" {
+		t.Fatalf("Expected the role_permissions join table, got %s", relation.JoinTable.Name)
+	}
+
+	permissionSchema, err := schema.Parse(&gormModels.PermissionGorm{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if permissionSchema.LookUpField("RoleID") != nil {
+		t.Fatal("Expected permissions not to be owned by a role")
+	}
+}
+
+// TestRolesSharePermissions runs against the database of HRMS_TEST_DATABASE_URL
+func TestRolesSharePermissions(t *testing.T) {
+	db := openTestDB(t)
+
+	suffix := uuid.NewString()
+	permission := gormModels.PermissionGorm{ID: uuid.New(), Name: "shared_" + suffix}
+	if err := db.Create(&permission).Error; err != nil {
+		t.Fatalf("Failed to create permission: %v", err)
+	}
+	repository := NewRoleRepository(db)
+	var roleIDs []string
+	t.Cleanup(func() {
+		db.Unscoped().Where("id IN ?", roleIDs).Delete(&gormModels.RoleGorm{})
+		db.Delete(&permission)
+	})
+
+	admin, sysErr := repository.Create(models.Role{Name: "admin_" + suffix, Permissions: []models.Permission{{ID: permission.ID.String()}}})
+	if sysErr != nil {
+		t.Fatalf("Failed to create role: %v", sysErr)
+	}
+	roleIDs = append(roleIDs, admin.ID)
+	// granted by name to a second role
+	manager, sysErr := repository.Create(models.Role{Name: "manager_" + suffix, Permissions: []models.Permission{{Name: permission.Name}}})
+	if sysErr != nil {
+		t.Fatalf("Failed to create role: %v", sysErr)
+	}
+	roleIDs = append(roleIDs, manager.ID)
+	if _, sysErr := repository.Update(manager.ID, manager); sysErr != nil {
+		t.Fatalf("Failed to update role: %v", sysErr)
+	}
+
+	for _, id := range roleIDs {
+		permissions, sysErr := repository.GetPermissions(id)
+		if sysErr != nil {
+			t.Fatalf("Failed to get permissions: %v", sysErr)
+		}
+		if !models.HasPermission(permissions, permission.Name) {
+			t.Fatalf("Expected role %s to keep the shared permission", id)
+		}
+	}
+
+	if _, sysErr := repository.Create(models.Role{Name: "unknown_" + suffix, Permissions: []models.Permission{{Name: "missing_" + suffix}}}); sysErr == nil {
+		t.Fatal("Expected unknown permissions to be rejected")
+	}
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"sync"
+	"testing"
+
+	"hrms.local/core/models"
+	gormModels "hrms.local/repository/postgress/models"
+
+	"gorm.io/gorm/schema"
+)
+
+// TestSearchFieldsNameExistingColumns keeps the search fields of the domain in step with the tables
+func TestSearchFieldsNameExistingColumns(t *testing.T) {
+	entities := []struct {
+		entity models.Searchable
+		table  any
+	}{
+		{models.User{}, &UserGorm{}},
+		{models.Role{}, &gormModels.RoleGorm{}},
+		{models.Employee{}, &EmployeeGorm{}},
+		{models.Department{}, &DepartmentGorm{}},
+		{models.Position{}, &PositionGorm{}},
+		{models.AttendanceEvent{}, &AttendanceEventGorm{}},
+		{models.WorkSchedule{}, &WorkScheduleGorm{}},
+		{models.LeaveType{}, &LeaveTypeGorm{}},
+		{models.LeaveRequest{}, &LeaveRequestGorm{}},
+		{models.PayPeriod{}, &PayPeriodGorm{}},
+		{models.PayrollRun{}, &PayrollRunGorm{}},
+		{models.Payslip{}, &PayslipGorm{}},
+	}
+	for _, e := range entities {
+		tableSchema, err := schema.Parse(e.table, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for name, field := range e.entity.SearchFields() {
+			if tableSchema.LookUpField(field.Column) == nil {
+				t.Errorf("%s.%s names the unknown column %s of %s", tableSchema.Name, name, field.Column, tableSchema.Table)
+			}
+		}
+	}
+}
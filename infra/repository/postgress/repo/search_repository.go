@@ -0,0 +1,189 @@
+package repo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"gorm.io/gorm"
+)
+
+// The documents searched for each table, lowercased and without accents. The indexes of
+// MigrateSearch are built on the same expressions, a query only uses them when both match.
+const (
+	userSearchDocument = `hrms_unaccent(lower(coalesce(users.name, '') || ' ' || coalesce(users.last_name, '') || ' ' || ` +
+		`coalesce(users.username, '') || ' ' || coalesce(users.email, '')))`
+	employeeSearchDocument = `hrms_unaccent(lower(coalesce(employees.first_name, '') || ' ' || coalesce(employees.last_name, '') || ' ' || ` +
+		`coalesce(employees.email, '') || ' ' || coalesce(employees.employee_number, '')))`
+	departmentSearchDocument = `hrms_unaccent(lower(coalesce(departments.name, '')))`
+)
+
+// MigrateSearch installs unaccent and pg_trgm and indexes the documents searched.
+// unaccent is only stable, hrms_unaccent pins its dictionary so indexes can use it.
+func MigrateSearch(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS unaccent`,
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE OR REPLACE FUNCTION hrms_unaccent(text) RETURNS text AS
+			$$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT`,
+		`CREATE INDEX IF NOT EXISTS idx_users_search_fts ON users USING gin (to_tsvector('simple'::regconfig, ` + userSearchDocument + `))`,
+		`CREATE INDEX IF NOT EXISTS idx_users_search_trgm ON users USING gin ((` + userSearchDocument + `) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_employees_search_fts ON employees USING gin (to_tsvector('simple'::regconfig, ` + employeeSearchDocument + `))`,
+		`CREATE INDEX IF NOT EXISTS idx_employees_search_trgm ON employees USING gin ((` + employeeSearchDocument + `) gin_trgm_ops)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Names are matched word by word with the simple configuration, the spanish one would stem them.
+// A row matches when every word of the query starts one of its words, or when the query is close
+// enough to a part of the document for a trigram match, which forgives typos.
+const (
+	userSearchQuery = `SELECT users.id, coalesce(users.name, '') AS name, coalesce(users.last_name, '') AS last_name,
+		coalesce(users.email, '') AS email, coalesce(users.username, '') AS username,
+		` + userRank + ` AS rank
+		FROM users
+		WHERE users.deleted_at IS NULL
+			AND (to_tsvector('simple', ` + userSearchDocument + `) @@ to_tsquery('simple', hrms_unaccent(@all))
+				OR hrms_unaccent(@query) <% ` + userSearchDocument + `)
+		ORDER BY rank DESC, users.id
+		LIMIT @limit`
+	userRank = `ts_rank(to_tsvector('simple', ` + userSearchDocument + `), to_tsquery('simple', hrms_unaccent(@all)))
+		+ word_similarity(hrms_unaccent(@query), ` + userSearchDocument + `)`
+
+	// employees are found by their own document, or by the department name together with it
+	// ("ana ventas" is Ana of the sales department) through the employees of matching departments
+	employeeSearchQuery = `SELECT id, name, last_name, email, employee_number, department, MAX(rank) AS rank FROM (
+			SELECT ` + employeeColumns + `
+			FROM employees
+			LEFT JOIN departments ON departments.id = employees.department_id AND departments.deleted_at IS NULL
+			WHERE employees.deleted_at IS NULL
+				AND (to_tsvector('simple', ` + employeeSearchDocument + `) @@ to_tsquery('simple', hrms_unaccent(@all))
+					OR hrms_unaccent(@query) <% ` + employeeSearchDocument + `)
+			UNION ALL
+			SELECT ` + employeeColumns + `
+			FROM departments
+			JOIN employees ON employees.department_id = departments.id AND employees.deleted_at IS NULL
+			WHERE departments.deleted_at IS NULL
+				AND to_tsvector('simple', ` + departmentSearchDocument + `) @@ to_tsquery('simple', hrms_unaccent(@any))
+				AND ` + employeeVector + ` @@ to_tsquery('simple', hrms_unaccent(@all))
+		) matches
+		GROUP BY id, name, last_name, email, employee_number, department
+		ORDER BY rank DESC, id
+		LIMIT @limit`
+	employeeColumns = `employees.id, employees.first_name AS name, employees.last_name, coalesce(employees.email, '') AS email,
+		employees.employee_number, coalesce(departments.name, '') AS department,
+		ts_rank(` + employeeVector + `, to_tsquery('simple', hrms_unaccent(@all)))
+		+ word_similarity(hrms_unaccent(@query), ` + employeeSearchDocument + ` || ' ' || ` + departmentSearchDocument + `) AS rank`
+	employeeVector = `(to_tsvector('simple', ` + employeeSearchDocument + `) || to_tsvector('simple', ` + departmentSearchDocument + `))`
+)
+
+type searchRow struct {
+	ID             string
+	Name           string
+	LastName       string
+	Email          string
+	Username       string
+	EmployeeNumber string
+	Department     string
+	Rank           float64
+}
+
+// SearchRepository searches users and employees with PostgreSQL full-text and trigram matching
+type SearchRepository struct {
+	db  *gorm.DB
+	mu  sync.RWMutex
+	ctx context.Context
+}
+
+func NewSearchRepository(db *gorm.DB) contracts.SearchContract {
+	return &SearchRepository{db: db, ctx: context.Background()}
+}
+
+// WithContext sets the context of the next queries, usually the one of the HTTP request
+func (r *SearchRepository) WithContext(ctx context.Context) *SearchRepository {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r.mu.Lock()
+	r.ctx = ctx
+	r.mu.Unlock()
+	return r
+}
+
+func (r *SearchRepository) currentContext() context.Context {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ctx
+}
+
+func (r *SearchRepository) Search(search models.PeopleSearch) ([]models.SearchResult, *models.SystemError) {
+	args := searchArgs(search)
+	db := r.db.WithContext(r.currentContext())
+	results := []models.SearchResult{}
+
+	if search.Searches(models.SearchKindUser) {
+		var rows []searchRow
+		if err := db.Raw(userSearchQuery, args).Scan(&rows).Error; err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Search failed", struct{}{})
+		}
+		results = appendSearchRows(results, models.SearchKindUser, rows)
+	}
+	if search.Searches(models.SearchKindEmployee) {
+		var rows []searchRow
+		if err := db.Raw(employeeSearchQuery, args).Scan(&rows).Error; err != nil {
+			return nil, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Search failed", struct{}{})
+		}
+		results = appendSearchRows(results, models.SearchKindEmployee, rows)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > search.GetLimit() {
+		results = results[:search.GetLimit()]
+	}
+	return results, nil
+}
+
+// searchArgs builds the named arguments of the search queries. The terms only hold letters and
+// digits, so joined with & and | they are valid tsqueries, each one matching as a prefix.
+func searchArgs(search models.PeopleSearch) map[string]any {
+	terms := models.SearchTerms(search.Query)
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	return map[string]any{
+		"query": strings.Join(terms, " "),
+		"all":   strings.Join(prefixes, " & "),
+		"any":   strings.Join(prefixes, " | "),
+		"limit": search.GetLimit(),
+	}
+}
+
+func appendSearchRows(results []models.SearchResult, kind models.SearchKind, rows []searchRow) []models.SearchResult {
+	for _, row := range rows {
+		results = append(results, models.SearchResult{
+			Kind:           kind,
+			ID:             row.ID,
+			Name:           row.Name,
+			LastName:       row.LastName,
+			Email:          row.Email,
+			Username:       row.Username,
+			EmployeeNumber: row.EmployeeNumber,
+			Department:     row.Department,
+			Rank:           row.Rank,
+		})
+	}
+	return results
+}
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+)
+
+func TestSearchArgsMatchEveryWordAsAPrefix(t *testing.T) {
+	args := searchArgs(models.PeopleSearch{Query: "José  GAR'; DROP", Limit: 5})
+	expected := map[string]any{
+		"query": "josé gar drop",
+		"all":   "josé:* & gar:* & drop:*",
+		"any":   "josé:* | gar:* | drop:*",
+		"limit": 5,
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Expected %v, got %v", expected, args)
+	}
+}
+
+// TestSearchIgnoresAccents runs against the database of HRMS_TEST_DATABASE_URL
+func TestSearchIgnoresAccents(t *testing.T) {
+	db := openTestDB(t, &UserGorm{}, &DepartmentGorm{}, &PositionGorm{}, &EmployeeGorm{})
+	if err := MigrateSearch(db); err != nil {
+		t.Fatalf("Failed to migrate search: %v", err)
+	}
+
+	suffix := uuid.NewString()[:8]
+	department := DepartmentGorm{ID: uuid.New(), Name: "Almacén " + suffix}
+	employee := EmployeeGorm{ID: uuid.New(), EmployeeNumber: "S" + suffix, FirstName: "José", LastName: "Núñez" + suffix, NationalID: "S" + suffix, HireDate: time.Now(), DepartmentID: &department.ID}
+	user := UserGorm{ID: uuid.New(), Username: "jnunez" + suffix, Email: "jnunez" + suffix + "@mail.com", Name: "José", LastName: "Núñez" + suffix}
+	if err := db.Create(&department).Error; err != nil {
+		t.Fatalf("Failed to create department: %v", err)
+	}
+	if err := db.Create(&employee).Error; err != nil {
+		t.Fatalf("Failed to create employee: %v", err)
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Unscoped().Delete(&employee)
+		db.Unscoped().Delete(&department)
+		db.Unscoped().Delete(&user)
+	})
+
+	repository := NewSearchRepository(db)
+	for query, kinds := range map[string][]models.SearchKind{
+		"jose nunez" + suffix:    {models.SearchKindUser, models.SearchKindEmployee},
+		"NUÑEZ" + suffix:         {models.SearchKindUser, models.SearchKindEmployee},
+		"jose almacen " + suffix: {models.SearchKindEmployee},
+	} {
+		results, sysErr := repository.Search(models.PeopleSearch{Query: query})
+		if sysErr != nil {
+			t.Fatalf("Failed to search %q: %v", query, sysErr.Message)
+		}
+		found := map[models.SearchKind]bool{}
+		for _, result := range results {
+			if result.ID == user.ID.String() || result.ID == employee.ID.String() {
+				found[result.Kind] = true
+			}
+		}
+		for _, kind := range kinds {
+			if !found[kind] {
+				t.Errorf("Expected %q to find the %s", query, kind)
+			}
+		}
+	}
+}
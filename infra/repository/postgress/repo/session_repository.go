@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenGorm struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	User         *UserGorm  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FamilyID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash    string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt    time.Time  `gorm:"type:timestamptz;not null"`
+	RevokedAt    *time.Time `gorm:"type:timestamptz"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (RefreshTokenGorm) TableName() string {
+	return "refresh_tokens"
+}
+
+func (r RefreshTokenGorm) ToModel() models.RefreshToken {
+	return models.RefreshToken{
+		ID:           fromGUIDToString(r.ID),
+		UserID:       fromGUIDToString(r.UserID),
+		FamilyID:     fromGUIDToString(r.FamilyID),
+		TokenHash:    r.TokenHash,
+		ExpiresAt:    r.ExpiresAt,
+		RevokedAt:    r.RevokedAt,
+		ReplacedByID: fromNullableGUID(r.ReplacedByID),
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+func RefreshTokenToEntity(r models.RefreshToken) RefreshTokenGorm {
+	id, _ := uuid.Parse(r.ID)
+	userID, _ := uuid.Parse(r.UserID)
+	familyID, _ := uuid.Parse(r.FamilyID)
+	return RefreshTokenGorm{
+		ID:           id,
+		UserID:       userID,
+		FamilyID:     familyID,
+		TokenHash:    r.TokenHash,
+		ExpiresAt:    r.ExpiresAt,
+		RevokedAt:    r.RevokedAt,
+		ReplacedByID: toNullableGUID(r.ReplacedByID),
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+type RefreshTokenRepository struct {
+	GenericCrud[models.RefreshToken, RefreshTokenGorm]
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) contracts.RefreshTokenContract {
+	return &RefreshTokenRepository{
+		GenericCrud: NewGenericCrud(db, RefreshTokenToEntity, (RefreshTokenGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *RefreshTokenRepository) Revoke(id string, replacedByID string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&RefreshTokenGorm{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": at, "replaced_by_id": toNullableGUID(replacedByID)})
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to revoke refresh token", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, at time.Time) *models.SystemError {
+	return r.revokeWhere("family_id = ?", familyID, at)
+}
+
+func (r *RefreshTokenRepository) RevokeByUser(userID string, at time.Time) *models.SystemError {
+	return r.revokeWhere("user_id = ?", userID, at)
+}
+
+func (r *RefreshTokenRepository) revokeWhere(condition string, value string, at time.Time) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).
+		Model(&RefreshTokenGorm{}).
+		Where(condition+" AND revoked_at IS NULL", value).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to revoke refresh tokens", struct{}{})
+	}
+	return nil
+}
+
+type RevokedTokenGorm struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey"`
+	ExpiresAt time.Time `gorm:"type:timestamptz;not null;index"`
+	CreatedAt time.Time
+}
+
+func (RevokedTokenGorm) TableName() string {
+	return "revoked_tokens"
+}
+
+func (r RevokedTokenGorm) ToModel() models.RevokedToken {
+	return models.RevokedToken{JTI: r.JTI, ExpiresAt: r.ExpiresAt}
+}
+
+func RevokedTokenToEntity(r models.RevokedToken) RevokedTokenGorm {
+	return RevokedTokenGorm{JTI: r.JTI, ExpiresAt: r.ExpiresAt}
+}
+
+type TokenDenylistRepository struct {
+	GenericCrud[models.RevokedToken, RevokedTokenGorm]
+	db *gorm.DB
+}
+
+func NewTokenDenylistRepository(db *gorm.DB) contracts.TokenDenylistContract {
+	return &TokenDenylistRepository{
+		GenericCrud: NewGenericCrud(db, RevokedTokenToEntity, (RevokedTokenGorm).ToModel),
+		db:          db,
+	}
+}
+
+// Revoke adds the token to the denylist and purges the entries of tokens that expired on their own
+func (r *TokenDenylistRepository) Revoke(token models.RevokedToken) (bool, *models.SystemError) {
+	db := r.db.WithContext(r.currentContext())
+	entity := RevokedTokenToEntity(token)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to revoke token", struct{}{})
+	}
+	db.Where("expires_at < ?", time.Now().UTC()).Delete(&RevokedTokenGorm{})
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TokenDenylistRepository) IsRevoked(jti string) (bool, *models.SystemError) {
+	var count int64
+	if err := r.db.WithContext(r.currentContext()).Model(&RevokedTokenGorm{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to check token", struct{}{})
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"os"
+	"testing"
+
+	gormModels "hrms.local/repository/postgress/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDB connects to the database of HRMS_TEST_DATABASE_URL, skipping when it is not set, and migrates
+// the roles and permissions every table refers to along with the given models
+func openTestDB(tb testing.TB, models ...any) *gorm.DB {
+	tb.Helper()
+	dsn := os.Getenv("HRMS_TEST_DATABASE_URL")
+	if dsn == "" {
+		tb.Skip("HRMS_TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		tb.Fatalf("Failed to connect: %v", err)
+	}
+	if err := db.SetupJoinTable(&gormModels.RoleGorm{}, "Permissions", &gormModels.RolePermissionGorm{}); err != nil {
+		tb.Fatalf("Failed to setup join table: %v", err)
+	}
+	if err := db.AutoMigrate(append([]any{&gormModels.RoleGorm{}, &gormModels.PermissionGorm{}, &gormModels.RolePermissionGorm{}}, models...)...); err != nil {
+		tb.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
@@ -5,25 +5,45 @@ import (
 
 	"hrms.local/core/contracts"
 	"hrms.local/core/models"
+	gormModels "hrms.local/repository/postgress/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserGorm struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	Username  string    `gorm:"type:varchar(255)"`
-	Password  string    `gorm:"type:varchar(255)"`
-	Email     string    `gorm:"type:varchar(255)"`
-	Name      string    `gorm:"type:varchar(255)"`
-	LastName  string    `gorm:"type:varchar(255)"`
-	Type      string    `gorm:"type:varchar(255)"`
-	Picture   string    `gorm:"type:varchar(255)"`
-	Role      string    `gorm:"type:varchar(255)"`
+	ID        uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Username  string               `gorm:"type:varchar(255)"`
+	Password  string               `gorm:"type:varchar(255)"`
+	Email     string               `gorm:"type:varchar(255);uniqueIndex:idx_users_email,where:email <> '' AND deleted_at IS NULL"`
+	Name      string               `gorm:"type:varchar(255)"`
+	LastName  string               `gorm:"type:varchar(255)"`
+	Type      string               `gorm:"type:varchar(255)"`
+	Picture   string               `gorm:"type:varchar(255)"`
+	RoleID    *uuid.UUID           `gorm:"type:uuid;index"`
+	Role      *gormModels.RoleGorm `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 	Active    bool           `gorm:"type:boolean"`
+
+	// Lockout state, see models.LockoutDuration
+	FailedLoginAttempts int        `gorm:"not null;default:0"`
+	LockedUntil         *time.Time `gorm:"type:timestamptz"`
+
+	PasswordChangedAt  *time.Time `gorm:"type:timestamptz"`
+	MustChangePassword bool       `gorm:"not null;default:false"`
+
+	MFASecret   string `gorm:"column:mfa_secret;type:varchar(64)"`
+	MFAEnabled  bool   `gorm:"column:mfa_enabled;not null;default:false"`
+	MFALastStep int64  `gorm:"column:mfa_last_step;not null;default:0"`
+
+	EmailVerifiedAt *time.Time `gorm:"type:timestamptz"`
+
+	// Single sign-on identity, see models.OIDCIdentity.ExternalID
+	ExternalID         string `gorm:"type:varchar(512);index"`
+	LocalLoginDisabled bool   `gorm:"not null;default:false"`
 }
 
 func (UserGorm) TableName() string {
@@ -44,8 +64,19 @@ func ToModel(entity models.User) UserGorm {
 		LastName: entity.LastName,
 		Type:     string(entity.Type),
 		Picture:  entity.Picture,
-		Role:     string(entity.Role),
+		RoleID:   toNullableGUID(entity.RoleID),
 		Active:   entity.Active,
+
+		FailedLoginAttempts: entity.FailedLoginAttempts,
+		LockedUntil:         entity.LockedUntil,
+		PasswordChangedAt:   entity.PasswordChangedAt,
+		MustChangePassword:  entity.MustChangePassword,
+		MFASecret:           entity.MFASecret,
+		MFAEnabled:          entity.MFAEnabled,
+		MFALastStep:         entity.MFALastStep,
+		EmailVerifiedAt:     entity.EmailVerifiedAt,
+		ExternalID:          entity.ExternalID,
+		LocalLoginDisabled:  entity.LocalLoginDisabled,
 	}
 }
 
@@ -59,20 +90,50 @@ func ToEntityUser(gorm UserGorm) models.User {
 		LastName: gorm.LastName,
 		Type:     models.UserType(gorm.Type),
 		Picture:  gorm.Picture,
-		Role:     gorm.Role,
+		RoleID:   fromNullableGUID(gorm.RoleID),
 		Active:   gorm.Active,
+
+		FailedLoginAttempts: gorm.FailedLoginAttempts,
+		LockedUntil:         gorm.LockedUntil,
+		PasswordChangedAt:   gorm.PasswordChangedAt,
+		MustChangePassword:  gorm.MustChangePassword,
+		MFASecret:           gorm.MFASecret,
+		MFAEnabled:          gorm.MFAEnabled,
+		MFALastStep:         gorm.MFALastStep,
+		EmailVerifiedAt:     gorm.EmailVerifiedAt,
+		ExternalID:          gorm.ExternalID,
+		LocalLoginDisabled:  gorm.LocalLoginDisabled,
 	}
 }
 
 type UserRepository struct {
 	GenericCrud[models.User, UserGorm]
+	db *gorm.DB
 }
 
+// Update keeps the lockout and MFA state, only changed through their own methods,
+// keeps the current password and its expiry when item has no password
+// and keeps the email verified while the email does not change.
+// The single sign-on identity is kept when item has none, it is only linked, never unlinked.
 func (r *UserRepository) Update(id string, item models.User) (models.User, *models.SystemError) {
-	if item.Password == "" {
-		existing, err := r.GetOnce("id", id)
-		if err == nil && existing != nil {
+	existing, err := r.GetOnce("id", id)
+	if err == nil && existing != nil {
+		if item.ExternalID == "" {
+			item.ExternalID = existing.ExternalID
+		}
+		item.FailedLoginAttempts = existing.FailedLoginAttempts
+		item.LockedUntil = existing.LockedUntil
+		item.MFASecret = existing.MFASecret
+		item.MFAEnabled = existing.MFAEnabled
+		item.MFALastStep = existing.MFALastStep
+		item.EmailVerifiedAt = nil
+		if item.Email == existing.Email {
+			item.EmailVerifiedAt = existing.EmailVerifiedAt
+		}
+		if item.Password == "" {
 			item.Password = existing.Password
+			item.PasswordChangedAt = existing.PasswordChangedAt
+			item.MustChangePassword = existing.MustChangePassword
 		}
 	}
 	return r.GenericCrud.Update(id, item)
@@ -81,5 +142,74 @@ func (r *UserRepository) Update(id string, item models.User) (models.User, *mode
 func NewUserRepository(db *gorm.DB) contracts.UserContract {
 	return &UserRepository{
 		GenericCrud: NewGenericCrud(db, ToModel, ToEntityUser),
+		db:          db,
+	}
+}
+
+// RegisterFailedLogin increments the counter in the database so concurrent failures are all counted
+func (r *UserRepository) RegisterFailedLogin(id string) (int, *models.SystemError) {
+	var user UserGorm
+	result := r.db.WithContext(r.currentContext()).
+		Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1"))
+	if result.Error != nil || result.RowsAffected == 0 {
+		return 0, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to register failed login", struct{}{})
+	}
+	return user.FailedLoginAttempts, nil
+}
+
+func (r *UserRepository) Lock(id string, until time.Time) *models.SystemError {
+	return r.updateLockout(id, map[string]any{"locked_until": until})
+}
+
+func (r *UserRepository) ResetFailedLogins(id string) *models.SystemError {
+	return r.updateLockout(id, map[string]any{"failed_login_attempts": 0, "locked_until": nil})
+}
+
+func (r *UserRepository) UpdateMFA(id string, secret string, enabled bool) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).
+		Model(&UserGorm{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{"mfa_secret": secret, "mfa_enabled": enabled, "mfa_last_step": 0}).Error
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to update MFA", struct{}{})
+	}
+	return nil
+}
+
+// AcceptMFAStep only moves the step forward, two requests with the same code cannot both succeed
+func (r *UserRepository) AcceptMFAStep(id string, step int64) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&UserGorm{}).
+		Where("id = ? AND mfa_last_step < ?", id, step).
+		UpdateColumn("mfa_last_step", step)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to update MFA", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// VerifyEmail only applies while the user keeps the email the token was sent to
+func (r *UserRepository) VerifyEmail(id string, email string, at time.Time) (bool, *models.SystemError) {
+	result := r.db.WithContext(r.currentContext()).
+		Model(&UserGorm{}).
+		Where("id = ? AND email = ?", id, email).
+		UpdateColumn("email_verified_at", at)
+	if result.Error != nil {
+		return false, models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to verify email", struct{}{})
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepository) updateLockout(id string, values map[string]any) *models.SystemError {
+	err := r.db.WithContext(r.currentContext()).
+		Model(&UserGorm{}).
+		Where("id = ?", id).
+		UpdateColumns(values).Error
+	if err != nil {
+		return models.NewSystemError(models.SystemErrorCodeInternal, models.SystemErrorTypeInternal, models.SystemErrorLevelError, "Failed to update user lockout", struct{}{})
 	}
+	return nil
 }
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"hrms.local/core/contracts"
+	"hrms.local/core/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WorkScheduleGorm struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name         string    `gorm:"type:varchar(255);uniqueIndex;not null"`
+	StartTime    string    `gorm:"type:varchar(5);not null"`
+	EndTime      string    `gorm:"type:varchar(5);not null"`
+	BreakMinutes int       `gorm:"not null;default:0"`
+	GraceMinutes int       `gorm:"not null;default:0"`
+	// Comma separated list of weekdays, 0 is Sunday
+	WorkDays  string `gorm:"type:varchar(20);not null"`
+	Timezone  string `gorm:"type:varchar(64)"`
+	IsDefault bool   `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (WorkScheduleGorm) TableName() string {
+	return "work_schedules"
+}
+
+func (w WorkScheduleGorm) ToModel() models.WorkSchedule {
+	var workDays []time.Weekday
+	for _, day := range strings.Split(w.WorkDays, ",") {
+		if value, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+			workDays = append(workDays, time.Weekday(value))
+		}
+	}
+	return models.WorkSchedule{
+		ID:           fromGUIDToString(w.ID),
+		Name:         w.Name,
+		StartTime:    w.StartTime,
+		EndTime:      w.EndTime,
+		BreakMinutes: w.BreakMinutes,
+		GraceMinutes: w.GraceMinutes,
+		WorkDays:     workDays,
+		Timezone:     w.Timezone,
+		IsDefault:    w.IsDefault,
+	}
+}
+
+func WorkScheduleToEntity(w models.WorkSchedule) WorkScheduleGorm {
+	id, _ := uuid.Parse(w.ID)
+	workDays := make([]string, len(w.WorkDays))
+	for i, day := range w.WorkDays {
+		workDays[i] = strconv.Itoa(int(day))
+	}
+	return WorkScheduleGorm{
+		ID:           id,
+		Name:         w.Name,
+		StartTime:    w.StartTime,
+		EndTime:      w.EndTime,
+		BreakMinutes: w.BreakMinutes,
+		GraceMinutes: w.GraceMinutes,
+		WorkDays:     strings.Join(workDays, ","),
+		Timezone:     w.Timezone,
+		IsDefault:    w.IsDefault,
+	}
+}
+
+type WorkScheduleRepository struct {
+	GenericCrud[models.WorkSchedule, WorkScheduleGorm]
+	db *gorm.DB
+}
+
+func NewWorkScheduleRepository(db *gorm.DB) contracts.WorkScheduleContract {
+	return &WorkScheduleRepository{
+		GenericCrud: NewGenericCrud(db, WorkScheduleToEntity, (WorkScheduleGorm).ToModel),
+		db:          db,
+	}
+}
+
+func (r *WorkScheduleRepository) GetDefault() (*models.WorkSchedule, *models.SystemError) {
+	return r.GetOnce("is_default", true)
+}
+
+// Override Create so a new default schedule replaces the previous one
+func (r *WorkScheduleRepository) Create(item models.WorkSchedule) (models.WorkSchedule, *models.SystemError) {
+	gormModel := WorkScheduleToEntity(item)
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		if gormModel.IsDefault {
+			if err := tx.Model(&WorkScheduleGorm{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&gormModel).Error
+	})
+	if err != nil {
+		return item, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Query failed", struct{}{})
+	}
+	return gormModel.ToModel(), nil
+}
+
+// Override Update so a new default schedule replaces the previous one
+func (r *WorkScheduleRepository) Update(id string, item models.WorkSchedule) (models.WorkSchedule, *models.SystemError) {
+	gormModel := WorkScheduleToEntity(item)
+	err := r.db.WithContext(r.currentContext()).Transaction(func(tx *gorm.DB) error {
+		if gormModel.IsDefault {
+			if err := tx.Model(&WorkScheduleGorm{}).Where("is_default = ? AND id <> ?", true, id).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("CreatedAt").Save(&gormModel).Error
+	})
+	if err != nil {
+		return item, models.NewSystemError(models.SystemErrorCodeValidation, models.SystemErrorTypeValidation, models.SystemErrorLevelError, "Update failed", struct{}{})
+	}
+	return gormModel.ToModel(), nil
+}